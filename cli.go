@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"osymapp/psychometrics"
+	"osymapp/services"
+)
+
+// Yönetim komutları: go run . <komut> [bayraklar]
+func runCommand(args []string) error {
+	switch args[0] {
+	case "gc-images":
+		return runImageGC(args[1:])
+	case "purge-questions":
+		return runQuestionPurge()
+	case "calibrate-difficulty":
+		return runDifficultyCalibration()
+	case "calibrate-irt":
+		return runIRTCalibration(args[1:])
+	case "review-stats":
+		return runReviewStats()
+	case "rebuild-user-stats":
+		return runUserStatsRebuild()
+	default:
+		return fmt.Errorf("bilinmeyen komut: %s", args[0])
+	}
+}
+
+// Sahipsiz resimleri ve eksik resim referanslarını raporlar
+func runImageGC(args []string) error {
+	_, defaults := services.ImageGCConfigFromEnv()
+
+	fs := flag.NewFlagSet("gc-images", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "dosyaları silmeden sadece raporla")
+	grace := fs.Duration("grace", defaults.GracePeriod, "bu süreden yeni dosyalara dokunma")
+	fs.Parse(args)
+
+	report, err := services.CollectOrphanImages(context.Background(), services.ImageGCOptions{
+		DryRun:      *dryRun,
+		GracePeriod: *grace,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printJSON(report)
+}
+
+// Çöp kutusunda saklama süresi dolan soruları kalıcı olarak siler
+func runQuestionPurge() error {
+	report, err := services.PurgeTrashedQuestions(context.Background())
+	if err != nil {
+		return err
+	}
+	return printJSON(report)
+}
+
+// Öğrenci cevaplarından gözlenen zorluğu yeniden hesaplar
+func runDifficultyCalibration() error {
+	report, err := services.CalibrateQuestionDifficulty(context.Background())
+	if err != nil {
+		return err
+	}
+	return printJSON(report)
+}
+
+// IRT madde parametrelerini ve öğrenci yeteneklerini yeniden kestirir
+func runIRTCalibration(args []string) error {
+	fs := flag.NewFlagSet("calibrate-irt", flag.ExitOnError)
+	model := fs.String("model", psychometrics.Model3PL, "IRT modeli (2pl veya 3pl)")
+	fs.Parse(args)
+
+	report, err := services.RunIRTCalibration(context.Background(), *model)
+	if err != nil {
+		return err
+	}
+	return printJSON(report)
+}
+
+// SM-2 ve FSRS kartlarının tekrar başarısını karşılaştırır
+func runReviewStats() error {
+	stats, err := services.GetReviewAlgorithmStats(context.Background())
+	if err != nil {
+		return err
+	}
+	return printJSON(stats)
+}
+
+// Kullanıcı konu istatistiklerini cevap geçmişinden yeniden üretir
+func runUserStatsRebuild() error {
+	report, err := services.RebuildUserTopicStats(context.Background())
+	if err != nil {
+		return err
+	}
+	return printJSON(report)
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Henüz uygulanmamış migration dosyalarını sırayla çalıştırır.
+// Her dosya kendi transaction'ı içinde uygulanır ve schema_migrations tablosuna kaydedilir.
+func Migrate() error {
+	ctx := context.Background()
+
+	_, err := pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	if err != nil {
+		return fmt.Errorf("unable to create schema_migrations table: %v", err)
+	}
+
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return fmt.Errorf("unable to read migrations: %v", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".sql") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version := strings.TrimSuffix(name, ".sql")
+
+		var applied bool
+		err := pool.QueryRow(ctx,
+			"SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = $1)",
+			version).Scan(&applied)
+		if err != nil {
+			return fmt.Errorf("unable to check migration %s: %v", version, err)
+		}
+		if applied {
+			continue
+		}
+
+		sql, err := migrationFiles.ReadFile("migrations/" + name)
+		if err != nil {
+			return fmt.Errorf("unable to read migration %s: %v", version, err)
+		}
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to start migration %s: %v", version, err)
+		}
+
+		if _, err := tx.Exec(ctx, string(sql)); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("migration %s failed: %v", version, err)
+		}
+
+		if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", version); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("unable to record migration %s: %v", version, err)
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("unable to commit migration %s: %v", version, err)
+		}
+
+		log.Printf("Migration uygulandı: %s", version)
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+-- Yayıncı filigran ayarları
+ALTER TABLE publishers
+    ADD COLUMN IF NOT EXISTS logo_url TEXT,
+    ADD COLUMN IF NOT EXISTS watermark_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS watermark_position TEXT NOT NULL DEFAULT 'bottom-right',
+    ADD COLUMN IF NOT EXISTS watermark_opacity REAL NOT NULL DEFAULT 0.35;
+
+ALTER TABLE publishers
+    ADD CONSTRAINT publishers_watermark_position_check
+        CHECK (watermark_position IN ('top-left', 'top-right', 'bottom-left', 'bottom-right', 'center')),
+    ADD CONSTRAINT publishers_watermark_opacity_check
+        CHECK (watermark_opacity > 0 AND watermark_opacity <= 1);
@@ -0,0 +1,32 @@
+-- Sınırsız derinlikte kategori ağacı.
+-- Eski üç seviyeli yapı (main_categories > sub_categories > categories) geçiş süresince
+-- korunur; mevcut veriyi aktarmak için: go run . sync-category-tree
+CREATE TABLE IF NOT EXISTS category_tree (
+    id SERIAL PRIMARY KEY,
+    parent_id INT REFERENCES category_tree(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    legacy_level TEXT CHECK (legacy_level IN ('main', 'sub', 'category')),
+    legacy_id INT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_tree_parent ON category_tree (parent_id);
+
+-- Aynı ebeveyn altında aynı isimde iki düğüm olamaz
+CREATE UNIQUE INDEX IF NOT EXISTS uq_category_tree_sibling_name
+    ON category_tree (COALESCE(parent_id, 0), name);
+
+-- Bir alt kategori birden fazla ana kategoriye bağlı olabildiği için eşleme ebeveyn bazındadır
+CREATE UNIQUE INDEX IF NOT EXISTS uq_category_tree_legacy
+    ON category_tree (legacy_level, legacy_id, COALESCE(parent_id, 0))
+    WHERE legacy_level IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS question_category_nodes (
+    question_id INT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    node_id INT NOT NULL REFERENCES category_tree(id) ON DELETE CASCADE,
+    PRIMARY KEY (question_id, node_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_category_nodes_node ON question_category_nodes (node_id);
@@ -0,0 +1,24 @@
+-- Kardeş kategoriler için açık görüntüleme sırası
+ALTER TABLE main_categories ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+ALTER TABLE main_category_sub_category ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+
+-- Mevcut kayıtlar için sıra, önceki davranışta olduğu gibi id'ye göre
+UPDATE main_categories mc
+SET position = o.rn
+FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY id) AS rn FROM main_categories) o
+WHERE mc.id = o.id;
+
+UPDATE main_category_sub_category mcsc
+SET position = o.rn
+FROM (
+    SELECT main_category_id, sub_category_id,
+           ROW_NUMBER() OVER (PARTITION BY main_category_id ORDER BY sub_category_id) AS rn
+    FROM main_category_sub_category
+) o
+WHERE mcsc.main_category_id = o.main_category_id AND mcsc.sub_category_id = o.sub_category_id;
+
+UPDATE categories c
+SET position = o.rn
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY sub_category_id ORDER BY id) AS rn FROM categories) o
+WHERE c.id = o.id;
@@ -0,0 +1,11 @@
+-- Öğrenci cevapları; istatistikler ve ileride cevap geçmişi için temel tablo
+CREATE TABLE IF NOT EXISTS question_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    question_id INT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    is_correct BOOLEAN NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_attempts_question ON question_attempts (question_id);
+CREATE INDEX IF NOT EXISTS idx_question_attempts_user ON question_attempts (user_id, created_at);
@@ -0,0 +1,8 @@
+-- Kategoriler silindiğinde 30 gün boyunca geri alınabilir şekilde işaretlenir
+ALTER TABLE main_categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE sub_categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_main_categories_deleted_at ON main_categories (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_sub_categories_deleted_at ON sub_categories (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories (deleted_at) WHERE deleted_at IS NOT NULL;
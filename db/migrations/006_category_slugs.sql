@@ -0,0 +1,21 @@
+-- Kategorilere URL'de kullanılacak slug eklenir; mevcut kayıtlar uygulama açılışında doldurulur
+ALTER TABLE main_categories ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+ALTER TABLE sub_categories ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_main_categories_slug ON main_categories (slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_sub_slug ON categories (sub_category_id, slug);
+CREATE INDEX IF NOT EXISTS idx_sub_categories_slug ON sub_categories (slug);
+
+-- Yeniden adlandırılan veya taşınan düğümlerin eski slug'ları yönlendirme için saklanır.
+-- parent_id: ana kategoriler için 0, alt kategoriler için ana kategori, kategoriler için alt kategori
+CREATE TABLE IF NOT EXISTS category_slug_redirects (
+    id SERIAL PRIMARY KEY,
+    level VARCHAR(16) NOT NULL CHECK (level IN ('main', 'sub', 'category')),
+    parent_id INTEGER NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    node_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_slug_redirects_lookup ON category_slug_redirects (level, parent_id, slug);
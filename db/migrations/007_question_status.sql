@@ -0,0 +1,27 @@
+-- Soru yaşam döngüsü: taslak -> incelemede -> yayında -> arşiv
+-- Mevcut sorular görünür kalsın diye yayında olarak işaretlenir, yeni sorular taslak başlar
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published';
+ALTER TABLE questions ALTER COLUMN status SET DEFAULT 'draft';
+
+ALTER TABLE questions DROP CONSTRAINT IF EXISTS questions_status_check;
+ALTER TABLE questions ADD CONSTRAINT questions_status_check
+    CHECK (status IN ('draft', 'in_review', 'published', 'archived'));
+
+CREATE INDEX IF NOT EXISTS idx_questions_status ON questions (status);
+
+CREATE TABLE IF NOT EXISTS question_status_transitions (
+    id BIGSERIAL PRIMARY KEY,
+    question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_status_transitions_question ON question_status_transitions (question_id, created_at);
+
+-- Soru hazırlayan ve onaylayan roller
+INSERT INTO roles (name) SELECT 'Editor' WHERE NOT EXISTS (SELECT 1 FROM roles WHERE name = 'Editor');
+INSERT INTO roles (name) SELECT 'Reviewer' WHERE NOT EXISTS (SELECT 1 FROM roles WHERE name = 'Reviewer');
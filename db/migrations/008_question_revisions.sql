@@ -0,0 +1,12 @@
+-- Sorudaki her değişiklik değiştirilemez bir revizyon olarak saklanır
+CREATE TABLE IF NOT EXISTS question_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    restored_from INTEGER,
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (question_id, revision)
+);
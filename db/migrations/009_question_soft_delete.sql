@@ -0,0 +1,5 @@
+-- Silinen sorular çöp kutusuna taşınır ve saklama süresi dolunca kalıcı olarak silinir
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS deleted_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_questions_deleted_at ON questions (deleted_at) WHERE deleted_at IS NOT NULL;
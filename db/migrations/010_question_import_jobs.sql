@@ -0,0 +1,19 @@
+-- ZIP + manifest ile toplu soru içe aktarma işleri
+CREATE TABLE IF NOT EXISTS question_import_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'running', 'completed', 'failed')),
+    mode VARCHAR(20) NOT NULL CHECK (mode IN ('atomic', 'partial')),
+    file_name TEXT NOT NULL DEFAULT '',
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    imported_rows INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    row_errors JSONB NOT NULL DEFAULT '[]',
+    question_ids INTEGER[] NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT '',
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
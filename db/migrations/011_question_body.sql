@@ -0,0 +1,2 @@
+-- Sorular için isteğe bağlı yapılandırılmış metin (Markdown alt kümesi + LaTeX)
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS body JSONB;
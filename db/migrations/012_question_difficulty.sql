@@ -0,0 +1,29 @@
+-- Zorluk seviyesi 1 (çok kolay) - 5 (çok zor) ölçeğine sabitlenir; tanınmayan değerler etiketsiz ('') kalır
+UPDATE questions SET difficulty_level = CASE lower(trim(COALESCE(difficulty_level, '')))
+    WHEN '1' THEN '1' WHEN 'çok kolay' THEN '1' WHEN 'cok kolay' THEN '1' WHEN 'very easy' THEN '1' WHEN 'very_easy' THEN '1'
+    WHEN '2' THEN '2' WHEN 'kolay' THEN '2' WHEN 'easy' THEN '2'
+    WHEN '3' THEN '3' WHEN 'orta' THEN '3' WHEN 'medium' THEN '3' WHEN 'normal' THEN '3'
+    WHEN '4' THEN '4' WHEN 'zor' THEN '4' WHEN 'hard' THEN '4'
+    WHEN '5' THEN '5' WHEN 'çok zor' THEN '5' WHEN 'cok zor' THEN '5' WHEN 'very hard' THEN '5' WHEN 'very_hard' THEN '5'
+    ELSE ''
+END
+WHERE difficulty_level IS NULL OR difficulty_level NOT IN ('', '1', '2', '3', '4', '5');
+
+ALTER TABLE questions DROP CONSTRAINT IF EXISTS questions_difficulty_level_check;
+ALTER TABLE questions ADD CONSTRAINT questions_difficulty_level_check
+    CHECK (difficulty_level IN ('', '1', '2', '3', '4', '5'));
+
+-- Öğrenci cevaplarından hesaplanan gözlenen zorluk (klasik test kuramı)
+CREATE TABLE IF NOT EXISTS question_difficulty_stats (
+    question_id INT PRIMARY KEY REFERENCES questions(id) ON DELETE CASCADE,
+    attempts INT NOT NULL,
+    p_value DOUBLE PRECISION NOT NULL,          -- doğru cevaplama oranı
+    discrimination DOUBLE PRECISION,            -- üst %27 - alt %27 doğru oranı farkı
+    observed_level VARCHAR(1) NOT NULL,
+    labelled_level VARCHAR(1) NOT NULL DEFAULT '',
+    flags TEXT[] NOT NULL DEFAULT '{}',
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_difficulty_stats_flagged
+    ON question_difficulty_stats (question_id) WHERE cardinality(flags) > 0;
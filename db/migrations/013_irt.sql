@@ -0,0 +1,18 @@
+-- Madde tepki kuramı (IRT) parametreleri; kalibrasyon yapılmamış sorularda NULL
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS irt_model VARCHAR(3);
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS irt_a DOUBLE PRECISION;  -- ayırt edicilik
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS irt_b DOUBLE PRECISION;  -- zorluk
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS irt_c DOUBLE PRECISION;  -- şans parametresi
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS irt_responses INT;
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS irt_calibrated_at TIMESTAMP;
+
+-- Öğrencinin ana kategori bazında yetenek kestirimi
+CREATE TABLE IF NOT EXISTS user_abilities (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    main_category_id INT NOT NULL REFERENCES main_categories(id) ON DELETE CASCADE,
+    theta DOUBLE PRECISION NOT NULL,
+    standard_error DOUBLE PRECISION NOT NULL,
+    responses INT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, main_category_id)
+);
@@ -0,0 +1,34 @@
+-- Uyarlanabilir (bilgisayar destekli) test oturumları
+CREATE TABLE IF NOT EXISTS adaptive_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed')),
+    filters JSONB NOT NULL DEFAULT '{}',
+    max_questions INT NOT NULL,
+    se_threshold DOUBLE PRECISION NOT NULL,
+    theta DOUBLE PRECISION NOT NULL DEFAULT 0,
+    standard_error DOUBLE PRECISION NOT NULL DEFAULT 1,
+    stop_reason VARCHAR(20) NOT NULL DEFAULT '',
+    current_question_id INT REFERENCES questions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_adaptive_sessions_user ON adaptive_sessions (user_id, created_at);
+
+-- Oturumda sorulan sorular ve her cevaptan sonraki yetenek kestirimi
+CREATE TABLE IF NOT EXISTS adaptive_session_items (
+    session_id BIGINT NOT NULL REFERENCES adaptive_sessions(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    question_id INT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    content_area INT NOT NULL DEFAULT 0,  -- içerik dengelemesi için alt kategori
+    information DOUBLE PRECISION NOT NULL,
+    is_correct BOOLEAN,
+    theta_after DOUBLE PRECISION,
+    se_after DOUBLE PRECISION,
+    selected_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    answered_at TIMESTAMP,
+    PRIMARY KEY (session_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_adaptive_session_items_question ON adaptive_session_items (question_id);
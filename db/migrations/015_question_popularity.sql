@@ -0,0 +1,18 @@
+-- Soru görüntüleme, cevaplama ve yer imi olaylarının günlük toplamları.
+-- Olaylar bellekte toplanıp toplu yazılır; popülerlik skoru bu tablodan hesaplanır.
+CREATE TABLE IF NOT EXISTS question_event_counts (
+    question_id INT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    views INT NOT NULL DEFAULT 0,
+    attempts INT NOT NULL DEFAULT 0,
+    bookmarks INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (question_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_event_counts_day ON question_event_counts (day);
+
+-- Son 7 günün ağırlıklı olay toplamı ("bu hafta popüler" sıralaması için)
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS popularity_week INT NOT NULL DEFAULT 0;
+UPDATE questions SET popularity = 0 WHERE popularity IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_questions_popularity_week ON questions (popularity_week DESC);
@@ -0,0 +1,39 @@
+-- Cevap geçmişi: seçilen şık, harcanan süre ve cevabın geldiği oturum
+ALTER TABLE question_attempts ADD COLUMN IF NOT EXISTS chosen_answer TEXT NOT NULL DEFAULT '';
+ALTER TABLE question_attempts ADD COLUMN IF NOT EXISTS time_spent_ms INT;
+ALTER TABLE question_attempts ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'practice';
+ALTER TABLE question_attempts ADD COLUMN IF NOT EXISTS session_id BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_question_attempts_user_question ON question_attempts (user_id, question_id, created_at);
+
+-- Yanlış cevaplar defteri: kullanıcının yanlış cevapladığı ve henüz öğrenmediği sorular
+CREATE TABLE IF NOT EXISTS user_mistakes (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    question_id INT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    wrong_count INT NOT NULL DEFAULT 1,
+    last_answer TEXT NOT NULL DEFAULT '',
+    first_wrong_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_wrong_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    mastered_at TIMESTAMP,
+    PRIMARY KEY (user_id, question_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_mistakes_open ON user_mistakes (user_id, last_wrong_at) WHERE mastered_at IS NULL;
+
+-- Yanlışlardan oluşturulan tekrar setleri
+CREATE TABLE IF NOT EXISTS practice_sets (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source VARCHAR(20) NOT NULL DEFAULT 'mistakes',
+    filters JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS practice_set_items (
+    set_id BIGINT NOT NULL REFERENCES practice_sets(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    question_id INT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    is_correct BOOLEAN,
+    answered_at TIMESTAMP,
+    PRIMARY KEY (set_id, position)
+);
@@ -0,0 +1,19 @@
+-- Aralıklı tekrar kartları: her (kullanıcı, soru) çifti için zamanlayıcı durumu.
+-- Kart ilk yanlış cevapta açılır ve oluşturulduğu algoritmayla devam eder.
+CREATE TABLE IF NOT EXISTS review_cards (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    question_id INT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    algorithm VARCHAR(10) NOT NULL,
+    repetitions INT NOT NULL DEFAULT 0,
+    lapses INT NOT NULL DEFAULT 0,
+    interval_days INT NOT NULL DEFAULT 0,
+    ease_factor DOUBLE PRECISION NOT NULL DEFAULT 0,
+    stability DOUBLE PRECISION NOT NULL DEFAULT 0,
+    difficulty DOUBLE PRECISION NOT NULL DEFAULT 0,
+    last_reviewed_at TIMESTAMP,
+    due_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, question_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_cards_due ON review_cards (user_id, due_at);
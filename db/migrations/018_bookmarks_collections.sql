@@ -0,0 +1,38 @@
+-- Kaydedilen sorular
+CREATE TABLE IF NOT EXISTS question_bookmarks (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    question_id INT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, question_id)
+);
+
+-- Kullanıcının adlandırılmış soru koleksiyonları; share_token doluysa bağlantıyla salt okunur paylaşılır
+CREATE TABLE IF NOT EXISTS question_collections (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    share_token VARCHAR(64) UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS question_collection_items (
+    collection_id INT NOT NULL REFERENCES question_collections(id) ON DELETE CASCADE,
+    question_id INT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    added_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection_id, question_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_collection_items_question ON question_collection_items (question_id);
+
+-- Kullanıcının sorulara aldığı özel notlar
+CREATE TABLE IF NOT EXISTS question_notes (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    question_id INT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    note TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, question_id)
+);
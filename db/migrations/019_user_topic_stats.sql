@@ -0,0 +1,56 @@
+-- Bir sorunun istatistiklerde sayıldığı konular: genel toplam ('all', 0), eski hiyerarşide
+-- kategori, alt kategori ve ana kategori, kategori ağacında ise bağlı düğümler ve tüm ataları
+CREATE OR REPLACE FUNCTION question_stat_scopes(qid INT)
+RETURNS TABLE (stat_level TEXT, stat_node_id INT) AS $$
+    WITH RECURSIVE Ancestors AS (
+        SELECT qcn.node_id AS id FROM question_category_nodes qcn WHERE qcn.question_id = qid
+        UNION
+        SELECT t.parent_id FROM category_tree t JOIN Ancestors a ON t.id = a.id WHERE t.parent_id IS NOT NULL
+    )
+    SELECT 'all', 0
+    UNION
+    SELECT 'category', c.id
+    FROM question_categories qc JOIN categories c ON c.id = qc.category_id AND c.deleted_at IS NULL
+    WHERE qc.question_id = qid
+    UNION
+    SELECT 'sub', c.sub_category_id
+    FROM question_categories qc JOIN categories c ON c.id = qc.category_id AND c.deleted_at IS NULL
+    WHERE qc.question_id = qid
+    UNION
+    SELECT 'main', mcsc.main_category_id
+    FROM question_categories qc
+    JOIN categories c ON c.id = qc.category_id AND c.deleted_at IS NULL
+    JOIN main_category_sub_category mcsc ON mcsc.sub_category_id = c.sub_category_id
+    WHERE qc.question_id = qid
+    UNION
+    SELECT 'node', id FROM Ancestors
+$$ LANGUAGE sql STABLE;
+
+-- Kullanıcı başına günlük konu performansı; her cevapta artımlı güncellenir
+CREATE TABLE IF NOT EXISTS user_topic_stats_daily (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    level VARCHAR(10) NOT NULL,
+    node_id INT NOT NULL,
+    day DATE NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    correct INT NOT NULL DEFAULT 0,
+    timed_attempts INT NOT NULL DEFAULT 0, -- süre bilgisi gönderilen cevaplar
+    time_spent_ms BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, level, node_id, day)
+);
+
+-- Mevcut cevap geçmişinden doldur
+INSERT INTO user_topic_stats_daily (user_id, level, node_id, day, attempts, correct, timed_attempts, time_spent_ms)
+SELECT a.user_id, s.stat_level, s.stat_node_id, a.day,
+       SUM(a.attempts), SUM(a.correct), SUM(a.timed_attempts), SUM(a.time_spent_ms)
+FROM (
+    SELECT user_id, question_id, created_at::date AS day,
+           COUNT(*) AS attempts,
+           COUNT(*) FILTER (WHERE is_correct) AS correct,
+           COUNT(time_spent_ms) AS timed_attempts,
+           COALESCE(SUM(time_spent_ms), 0) AS time_spent_ms
+    FROM question_attempts
+    GROUP BY user_id, question_id, created_at::date
+) a
+CROSS JOIN LATERAL question_stat_scopes(a.question_id) s
+GROUP BY a.user_id, s.stat_level, s.stat_node_id, a.day;
@@ -0,0 +1,36 @@
+-- Süreli deneme sınavları. Süre sunucuda tutulur: deadline_at başlangıç + süre (+ alıştırma
+-- modunda duraklatılan süre) olarak hesaplanır; bu andan sonra gelen cevaplar reddedilir.
+CREATE TABLE IF NOT EXISTS exam_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    mode VARCHAR(10) NOT NULL CHECK (mode IN ('exam', 'practice')),
+    preset VARCHAR(20) NOT NULL DEFAULT '',
+    filters JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'finished')),
+    duration_seconds INT NOT NULL CHECK (duration_seconds > 0),
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deadline_at TIMESTAMP NOT NULL,
+    paused_at TIMESTAMP,
+    paused_seconds INT NOT NULL DEFAULT 0,
+    finished_at TIMESTAMP,
+    finish_reason VARCHAR(20),
+    correct INT,
+    wrong INT,
+    blank INT,
+    net DOUBLE PRECISION
+);
+
+CREATE INDEX IF NOT EXISTS idx_exam_sessions_user ON exam_sessions (user_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_exam_sessions_deadline ON exam_sessions (deadline_at) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS exam_session_items (
+    session_id BIGINT NOT NULL REFERENCES exam_sessions(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    question_id INT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    answer TEXT NOT NULL DEFAULT '',
+    time_spent_ms INT,
+    answered_at TIMESTAMP,
+    is_correct BOOLEAN,
+    PRIMARY KEY (session_id, position),
+    UNIQUE (session_id, question_id)
+);
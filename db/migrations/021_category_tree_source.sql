@@ -0,0 +1,281 @@
+-- Kategori ağacı kategori verisinin asıl kaynağı olur. Eski üç seviyeli tablolar
+-- (main_categories > sub_categories > categories) ağacın ilk üç seviyesinin izdüşümüdür ve
+-- yalnızca yazılan düğümler için uygulama tarafından güncellenir. Bu migration mevcut veriyi
+-- bir kez ağaca taşır; sync-category-tree komutu artık gerekmez.
+ALTER TABLE category_tree ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+ALTER TABLE category_tree ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE category_tree ADD COLUMN IF NOT EXISTS deleted_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+DROP INDEX IF EXISTS uq_category_tree_legacy;
+DROP INDEX IF EXISTS uq_category_tree_sibling_name;
+
+-- 1) Birden fazla ana kategoriye bağlı alt kategoriler her bağlantı için ayrı kayda bölünür;
+-- ağaçta her düğümün tek bir ebeveyni olduğundan eski kayıtla düğüm bire bir eşlenmelidir.
+-- Kategorileri, soru ilişkileri ve yönlendirmeleri kopyaya da aktarılır.
+-- Bölünen alt kategorilerin kullanıcı istatistikleri için: go run . rebuild-user-stats
+DO $$
+DECLARE
+    link RECORD;
+    cat RECORD;
+    new_sub INT;
+    new_cat INT;
+BEGIN
+    FOR link IN
+        SELECT mcsc.main_category_id, mcsc.sub_category_id
+        FROM main_category_sub_category mcsc
+        WHERE mcsc.main_category_id <> (
+            SELECT MIN(o.main_category_id) FROM main_category_sub_category o
+            WHERE o.sub_category_id = mcsc.sub_category_id)
+        ORDER BY mcsc.sub_category_id, mcsc.main_category_id
+    LOOP
+        INSERT INTO sub_categories (name, slug, deleted_at)
+        SELECT name, slug, deleted_at FROM sub_categories WHERE id = link.sub_category_id
+        RETURNING id INTO new_sub;
+
+        FOR cat IN SELECT id FROM categories WHERE sub_category_id = link.sub_category_id ORDER BY id LOOP
+            INSERT INTO categories (sub_category_id, name, position, slug, deleted_at)
+            SELECT new_sub, name, position, slug, deleted_at FROM categories WHERE id = cat.id
+            RETURNING id INTO new_cat;
+
+            INSERT INTO question_categories (question_id, category_id)
+            SELECT question_id, new_cat FROM question_categories WHERE category_id = cat.id;
+
+            INSERT INTO category_slug_redirects (level, parent_id, slug, node_id)
+            SELECT 'category', new_sub, slug, new_cat FROM category_slug_redirects
+            WHERE level = 'category' AND parent_id = link.sub_category_id AND node_id = cat.id;
+
+            -- Eski senkronizasyonun bu ana kategori altında oluşturduğu düğüm kopyaya bağlanır
+            UPDATE category_tree t SET legacy_id = new_cat
+            FROM category_tree s, category_tree m
+            WHERE t.parent_id = s.id AND s.parent_id = m.id
+              AND m.legacy_level = 'main' AND m.legacy_id = link.main_category_id
+              AND s.legacy_level = 'sub' AND s.legacy_id = link.sub_category_id
+              AND t.legacy_level = 'category' AND t.legacy_id = cat.id;
+        END LOOP;
+
+        UPDATE category_tree s SET legacy_id = new_sub
+        FROM category_tree m
+        WHERE s.parent_id = m.id
+          AND m.legacy_level = 'main' AND m.legacy_id = link.main_category_id
+          AND s.legacy_level = 'sub' AND s.legacy_id = link.sub_category_id;
+
+        UPDATE category_slug_redirects SET node_id = new_sub
+        WHERE level = 'sub' AND parent_id = link.main_category_id AND node_id = link.sub_category_id;
+
+        UPDATE main_category_sub_category SET sub_category_id = new_sub
+        WHERE main_category_id = link.main_category_id AND sub_category_id = link.sub_category_id;
+    END LOOP;
+END $$;
+
+-- 2) Eski tablolar şimdiye kadar asıl kaynak olduğundan daha önce aktarılmış düğümler onlara
+-- göre güncellenir. Eski senkronizasyon taşınan kayıtlar için ikinci bir düğüm oluşturmuş
+-- olabilir; aynı kayda karşılık gelen düğümlerden en eskisi tutulur, diğerlerinin soru
+-- ilişkileri ve ağaçta eklenmiş alt düğümleri ona aktarılıp kendileri silinir.
+-- Eski tablolardan kalıcı olarak silinmiş (ör. birleştirilmiş) kayıtların düğümleri kaldırılır;
+-- ağaçta altlarına eklenmiş düğümler bir üst düğüme bağlanır
+CREATE TEMP TABLE category_tree_orphans ON COMMIT DROP AS
+SELECT t.id, t.parent_id
+FROM category_tree t
+WHERE (t.legacy_level = 'main' AND NOT EXISTS (SELECT 1 FROM main_categories WHERE id = t.legacy_id))
+   OR (t.legacy_level = 'sub' AND NOT EXISTS (SELECT 1 FROM sub_categories WHERE id = t.legacy_id))
+   OR (t.legacy_level = 'category' AND NOT EXISTS (SELECT 1 FROM categories WHERE id = t.legacy_id));
+
+UPDATE category_tree c SET parent_id = o.parent_id
+FROM category_tree_orphans o
+WHERE c.parent_id = o.id AND c.legacy_level IS NULL;
+
+DELETE FROM category_tree WHERE id IN (SELECT id FROM category_tree_orphans);
+
+CREATE TEMP TABLE category_tree_duplicates (id INT PRIMARY KEY, keep_id INT NOT NULL) ON COMMIT DROP;
+
+CREATE FUNCTION pg_temp.merge_category_tree_duplicates(level TEXT) RETURNS void AS $$
+    DELETE FROM category_tree_duplicates;
+
+    INSERT INTO category_tree_duplicates (id, keep_id)
+    SELECT id, keep_id FROM (
+        SELECT id, MIN(id) OVER (PARTITION BY legacy_id) AS keep_id
+        FROM category_tree
+        WHERE legacy_level = level
+    ) ranked
+    WHERE id <> keep_id;
+
+    INSERT INTO question_category_nodes (question_id, node_id)
+    SELECT qcn.question_id, d.keep_id
+    FROM question_category_nodes qcn
+    JOIN category_tree_duplicates d ON d.id = qcn.node_id
+    ON CONFLICT DO NOTHING;
+
+    UPDATE category_tree t SET parent_id = d.keep_id
+    FROM category_tree_duplicates d
+    WHERE t.parent_id = d.id;
+
+    DELETE FROM category_tree WHERE id IN (SELECT id FROM category_tree_duplicates);
+$$ LANGUAGE sql;
+
+UPDATE category_tree t
+SET parent_id = NULL, name = mc.name, position = mc.position, deleted_at = mc.deleted_at
+FROM main_categories mc
+WHERE t.legacy_level = 'main' AND t.legacy_id = mc.id;
+
+SELECT pg_temp.merge_category_tree_duplicates('main');
+
+INSERT INTO category_tree (parent_id, name, position, deleted_at, legacy_level, legacy_id)
+SELECT NULL, mc.name, mc.position, mc.deleted_at, 'main', mc.id
+FROM main_categories mc
+WHERE NOT EXISTS (SELECT 1 FROM category_tree t WHERE t.legacy_level = 'main' AND t.legacy_id = mc.id)
+ORDER BY mc.id;
+
+UPDATE category_tree t
+SET parent_id = m.id, name = sc.name, position = mcsc.position, deleted_at = sc.deleted_at
+FROM sub_categories sc
+JOIN main_category_sub_category mcsc ON mcsc.sub_category_id = sc.id
+JOIN category_tree m ON m.legacy_level = 'main' AND m.legacy_id = mcsc.main_category_id
+WHERE t.legacy_level = 'sub' AND t.legacy_id = sc.id;
+
+SELECT pg_temp.merge_category_tree_duplicates('sub');
+
+INSERT INTO category_tree (parent_id, name, position, deleted_at, legacy_level, legacy_id)
+SELECT m.id, sc.name, mcsc.position, sc.deleted_at, 'sub', sc.id
+FROM sub_categories sc
+JOIN main_category_sub_category mcsc ON mcsc.sub_category_id = sc.id
+JOIN category_tree m ON m.legacy_level = 'main' AND m.legacy_id = mcsc.main_category_id
+WHERE NOT EXISTS (SELECT 1 FROM category_tree t WHERE t.legacy_level = 'sub' AND t.legacy_id = sc.id)
+ORDER BY sc.id;
+
+UPDATE category_tree t
+SET parent_id = s.id, name = c.name, position = c.position, deleted_at = c.deleted_at
+FROM categories c
+JOIN category_tree s ON s.legacy_level = 'sub' AND s.legacy_id = c.sub_category_id
+WHERE t.legacy_level = 'category' AND t.legacy_id = c.id;
+
+SELECT pg_temp.merge_category_tree_duplicates('category');
+
+INSERT INTO category_tree (parent_id, name, position, deleted_at, legacy_level, legacy_id)
+SELECT s.id, c.name, c.position, c.deleted_at, 'category', c.id
+FROM categories c
+JOIN category_tree s ON s.legacy_level = 'sub' AND s.legacy_id = c.sub_category_id
+WHERE NOT EXISTS (SELECT 1 FROM category_tree t WHERE t.legacy_level = 'category' AND t.legacy_id = c.id)
+ORDER BY c.id;
+
+-- Silinmiş bir kaydın ağaçta eklenmiş alt düğümleri de silinmiş sayılır
+WITH RECURSIVE Deleted AS (
+    SELECT id, deleted_at FROM category_tree WHERE deleted_at IS NOT NULL
+    UNION ALL
+    SELECT c.id, d.deleted_at FROM category_tree c JOIN Deleted d ON c.parent_id = d.id
+)
+UPDATE category_tree t SET deleted_at = d.deleted_at
+FROM Deleted d
+WHERE t.id = d.id AND t.deleted_at IS NULL;
+
+-- 3) Eski tablolarda aynı ebeveyn altında aynı isimli kayıtlar olabiliyordu; ağaçta canlı
+-- kardeşlerin isimleri tekil olduğundan sonrakiler "(2)", "(3)" ekiyle ayrıştırılır
+UPDATE category_tree t SET name = t.name || ' (' || dup.rn || ')'
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY COALESCE(parent_id, 0), name ORDER BY position, id) AS rn
+    FROM category_tree
+    WHERE deleted_at IS NULL
+) dup
+WHERE dup.id = t.id AND dup.rn > 1;
+
+-- Sıralar her ebeveyn altında 1'den başlayacak şekilde yeniden numaralanır; ağaçta
+-- oluşturulmuş düğümler eski kayıtlardan sonra gelir
+UPDATE category_tree t SET position = o.rn
+FROM (
+    SELECT id, ROW_NUMBER() OVER (
+        PARTITION BY COALESCE(parent_id, 0) ORDER BY legacy_level IS NULL, position, id) AS rn
+    FROM category_tree
+) o
+WHERE t.id = o.id;
+
+-- 4) Ağaçta oluşturulmuş ilk üç seviyedeki düğümler için eski tablolarda karşılık oluşturulur.
+-- Slug'lar uygulama açılışında üretilir.
+DO $$
+DECLARE
+    node RECORD;
+    parent_legacy INT;
+    new_id INT;
+BEGIN
+    FOR node IN
+        WITH RECURSIVE Tree AS (
+            SELECT id, 0 AS depth FROM category_tree WHERE parent_id IS NULL
+            UNION ALL
+            SELECT c.id, t.depth + 1 FROM category_tree c JOIN Tree t ON c.parent_id = t.id
+        )
+        SELECT c.id, c.parent_id, c.name, c.position, c.deleted_at, Tree.depth
+        FROM Tree JOIN category_tree c ON c.id = Tree.id
+        WHERE Tree.depth <= 2 AND c.legacy_level IS NULL
+        ORDER BY Tree.depth, c.id
+    LOOP
+        SELECT legacy_id INTO parent_legacy FROM category_tree WHERE id = node.parent_id;
+
+        IF node.depth = 0 THEN
+            INSERT INTO main_categories (name, position, deleted_at)
+            VALUES (node.name, node.position, node.deleted_at)
+            RETURNING id INTO new_id;
+            UPDATE category_tree SET legacy_level = 'main', legacy_id = new_id WHERE id = node.id;
+        ELSIF node.depth = 1 THEN
+            INSERT INTO sub_categories (name, deleted_at)
+            VALUES (node.name, node.deleted_at)
+            RETURNING id INTO new_id;
+            INSERT INTO main_category_sub_category (main_category_id, sub_category_id, position)
+            VALUES (parent_legacy, new_id, node.position);
+            UPDATE category_tree SET legacy_level = 'sub', legacy_id = new_id WHERE id = node.id;
+        ELSE
+            INSERT INTO categories (sub_category_id, name, position, deleted_at)
+            VALUES (parent_legacy, node.name, node.position, node.deleted_at)
+            RETURNING id INTO new_id;
+            UPDATE category_tree SET legacy_level = 'category', legacy_id = new_id WHERE id = node.id;
+        END IF;
+    END LOOP;
+END $$;
+
+-- Eski kayıtlar ağaçtaki isim, sıra ve silinme bilgisine eşitlenir
+UPDATE main_categories mc SET name = t.name, position = t.position, deleted_at = t.deleted_at
+FROM category_tree t
+WHERE t.legacy_level = 'main' AND t.legacy_id = mc.id;
+
+UPDATE sub_categories sc SET name = t.name, deleted_at = t.deleted_at
+FROM category_tree t
+WHERE t.legacy_level = 'sub' AND t.legacy_id = sc.id;
+
+UPDATE main_category_sub_category mcsc SET position = t.position
+FROM category_tree t
+WHERE t.legacy_level = 'sub' AND t.legacy_id = mcsc.sub_category_id;
+
+UPDATE categories c SET name = t.name, position = t.position, deleted_at = t.deleted_at
+FROM category_tree t
+WHERE t.legacy_level = 'category' AND t.legacy_id = c.id;
+
+-- 5) Soru ilişkileri iki yönde tamamlanır: eski kategori ilişkileri düğümlere, düğüm
+-- ilişkileri ise düğümün bulunduğu üçüncü seviye kategoriye yansıtılır
+INSERT INTO question_category_nodes (question_id, node_id)
+SELECT qc.question_id, t.id
+FROM question_categories qc
+JOIN category_tree t ON t.legacy_level = 'category' AND t.legacy_id = qc.category_id
+ON CONFLICT DO NOTHING;
+
+INSERT INTO question_categories (question_id, category_id)
+WITH RECURSIVE Owner AS (
+    SELECT id, legacy_id AS category_id FROM category_tree WHERE legacy_level = 'category'
+    UNION ALL
+    SELECT c.id, o.category_id FROM category_tree c JOIN Owner o ON c.parent_id = o.id
+)
+SELECT DISTINCT qcn.question_id, o.category_id
+FROM question_category_nodes qcn
+JOIN Owner o ON o.id = qcn.node_id
+WHERE NOT EXISTS (
+    SELECT 1 FROM question_categories qc
+    WHERE qc.question_id = qcn.question_id AND qc.category_id = o.category_id
+);
+
+-- Her eski kayıt en fazla bir düğüme karşılık gelir
+CREATE UNIQUE INDEX IF NOT EXISTS uq_category_tree_legacy
+    ON category_tree (legacy_level, legacy_id)
+    WHERE legacy_level IS NOT NULL;
+
+-- Aynı ebeveyn altında aynı isimde iki canlı düğüm olamaz
+CREATE UNIQUE INDEX IF NOT EXISTS uq_category_tree_sibling_name
+    ON category_tree (COALESCE(parent_id, 0), name)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_category_tree_deleted_at ON category_tree (deleted_at) WHERE deleted_at IS NOT NULL;
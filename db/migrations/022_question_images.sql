@@ -0,0 +1,19 @@
+-- Sorunun kullandığı sunucu resimleri (soru, çözüm ve metne gömülü resimler). Kayıt sırasında
+-- doldurulur; filigran için resmin sahibi olan soru gövde metni taranmadan bulunur.
+CREATE TABLE IF NOT EXISTS question_images (
+    question_id INT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    PRIMARY KEY (question_id, url)
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_images_url ON question_images (url);
+
+INSERT INTO question_images (question_id, url)
+SELECT id, path_url FROM questions WHERE path_url <> ''
+UNION
+SELECT id, solution_url FROM questions WHERE solution_url <> ''
+UNION
+SELECT q.id, m[1]
+FROM questions q, regexp_matches(q.body::text, '!\[[^\]]*\]\(\s*(/images/[^)\s"\\?]+)', 'g') AS m
+WHERE q.body IS NOT NULL
+ON CONFLICT DO NOTHING;
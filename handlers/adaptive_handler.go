@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Uyarlanabilir Test Başlat (aday havuzu GetQuestions filtreleriyle daraltılabilir)
+func StartAdaptiveSession(w http.ResponseWriter, r *http.Request) {
+	userID := currentUserID(r)
+	if userID == 0 {
+		utils.SendError(w, http.StatusForbidden, "Misafir kullanıcılar uyarlanabilir test başlatamaz")
+		return
+	}
+
+	var req models.AdaptiveSessionRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.SendError(w, http.StatusBadRequest, "Geçersiz istek gövdesi")
+			return
+		}
+	}
+
+	session, err := services.StartAdaptiveSession(context.Background(), userID,
+		services.AdaptiveFilters(r.URL.Query()), req)
+	if errors.Is(err, services.ErrAdaptiveSEThreshold) {
+		utils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Uyarlanabilir test başlatılamadı")
+		return
+	}
+
+	sendAdaptiveSession(w, http.StatusCreated, "Uyarlanabilir test başlatıldı", session)
+}
+
+// Uyarlanabilir Test Oturumu ve Yetenek İzi
+func GetAdaptiveSession(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz oturum ID")
+		return
+	}
+
+	session, err := services.GetAdaptiveSession(context.Background(), sessionID, currentUserID(r))
+	if errors.Is(err, services.ErrAdaptiveSessionNotFound) {
+		utils.SendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Oturum alınamadı")
+		return
+	}
+
+	sendAdaptiveSession(w, http.StatusOK, "Oturum getirildi", session)
+}
+
+// Uyarlanabilir Testte Güncel Soruyu Cevapla
+func AnswerAdaptiveQuestion(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz oturum ID")
+		return
+	}
+
+	var req models.AdaptiveAnswerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz istek gövdesi")
+		return
+	}
+
+	result, err := services.AnswerAdaptiveQuestion(context.Background(), currentUserID(r), sessionID, req)
+	switch {
+	case errors.Is(err, services.ErrAdaptiveSessionNotFound):
+		utils.SendError(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, services.ErrAdaptiveSessionFinished), errors.Is(err, services.ErrAdaptiveQuestionMismatch),
+		errors.Is(err, services.ErrExamQuestionLocked):
+		utils.SendError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		utils.SendError(w, http.StatusInternalServerError, "Cevap kaydedilemedi")
+		return
+	}
+
+	result.SolutionURL = services.SignImageURL(result.SolutionURL)
+	if result.Session.CurrentQuestionID != nil {
+		if result.NextQuestion, err = loadAdaptiveQuestion(result.Session.UserID, *result.Session.CurrentQuestionID); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Sıradaki soru alınamadı")
+			return
+		}
+	}
+
+	utils.SendSuccess(w, "Cevap kaydedildi", result)
+}
+
+func sendAdaptiveSession(w http.ResponseWriter, status int, message string, session *models.AdaptiveSession) {
+	data := map[string]interface{}{"session": session, "question": nil}
+	if session.CurrentQuestionID != nil {
+		question, err := loadAdaptiveQuestion(session.UserID, *session.CurrentQuestionID)
+		if err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Güncel soru alınamadı")
+			return
+		}
+		data["question"] = question
+	}
+	utils.SendResponse(w, status, true, message, data, "")
+}
+
+// Öğrenciye gösterilecek soru; cevap ve çözüm, soru cevaplanana kadar gizlenir
+func loadAdaptiveQuestion(userID, questionID int) (*models.Question, error) {
+	var q models.Question
+	err := db.GetPool().QueryRow(context.Background(), `
+        SELECT id, path_url, publisher_id, difficulty_level, status, body, created_at, updated_at
+        FROM questions WHERE id = $1`, questionID).Scan(
+		&q.ID, &q.PathURL, &q.PublisherID, &q.DifficultyLevel, &q.Status, &q.Body,
+		&q.CreatedAt, &q.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Oturum her yenilendiğinde soru yeniden yüklenir; görüntüleme günde bir kez sayılır
+	services.RecordQuestionEvent(services.UserEventActor(userID), q.ID, models.QuestionEventView)
+	signQuestionImages(&q)
+	if q.Body != nil {
+		q.Body.Solution = ""
+	}
+	q.Body = services.SanitizeQuestionBody(q.Body)
+	return &q, nil
+}
@@ -0,0 +1,466 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+const maxPracticeSetSize = 50
+
+// Soruyu Cevapla (cevap geçmişine ve yanlışlar defterine işlenir)
+func SubmitQuestionAttempt(w http.ResponseWriter, r *http.Request) {
+	userID := currentUserID(r)
+	if userID == 0 {
+		utils.SendError(w, http.StatusForbidden, "Misafir kullanıcıların cevapları kaydedilmez")
+		return
+	}
+
+	questionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz soru ID")
+		return
+	}
+
+	var req models.QuestionAttemptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz istek gövdesi")
+		return
+	}
+	if strings.TrimSpace(req.Answer) == "" {
+		utils.SendError(w, http.StatusBadRequest, "Cevap boş olamaz")
+		return
+	}
+	// Uyarlanabilir test cevapları yalnızca kendi endpoint'i üzerinden kaydedilir
+	switch req.Source {
+	case "", models.AttemptSourcePractice, models.AttemptSourceMistakes, models.AttemptSourceReview:
+	default:
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz kaynak (practice, mistakes veya review olmalı)")
+		return
+	}
+	if req.TimeSpentMS != nil && *req.TimeSpentMS < 0 {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz süre")
+		return
+	}
+
+	tx, err := db.GetPool().Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Transaction başlatma hatası")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	// Devam eden sınavdaki sorunun cevabı sınav bitmeden açıklanmaz
+	locked, err := services.OngoingExamQuestionIDs(context.Background(), tx, userID, []int{questionID})
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Sınav durumu kontrol edilemedi")
+		return
+	}
+	if locked[questionID] {
+		utils.SendError(w, http.StatusConflict, services.ErrExamQuestionLocked.Error())
+		return
+	}
+
+	result, err := services.RecordQuestionAttempt(context.Background(), tx, userID, questionID, isQuestionEditor(r), req)
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, http.StatusNotFound, "Soru bulunamadı")
+		return
+	}
+	if errors.Is(err, services.ErrPracticeSetNotFound) {
+		utils.SendError(w, http.StatusNotFound, "Soru bu tekrar setinde bulunamadı")
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Cevap kaydedilemedi")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Transaction commit hatası")
+		return
+	}
+	services.RecordQuestionEvent(questionEventActor(r), questionID, models.QuestionEventAttempt)
+
+	result.SolutionURL = services.SignImageURL(result.SolutionURL)
+	utils.SendSuccess(w, "Cevap kaydedildi", result)
+}
+
+// Cevap Geçmişim (?question_id, ?source, ?limit, ?offset)
+func GetMyAttempts(w http.ResponseWriter, r *http.Request) {
+	query := `
+        SELECT id, user_id, question_id, chosen_answer, is_correct, time_spent_ms, source, session_id, created_at
+        FROM question_attempts
+        WHERE user_id = $1`
+	args := []interface{}{currentUserID(r)}
+
+	if v := r.URL.Query().Get("question_id"); v != "" {
+		args = append(args, v)
+		query += fmt.Sprintf(" AND question_id = $%d", len(args))
+	}
+	if v := r.URL.Query().Get("source"); v != "" {
+		args = append(args, v)
+		query += fmt.Sprintf(" AND source = $%d", len(args))
+	}
+
+	limit, offset := pageParams(r.URL.Query(), 50, 200)
+	args = append(args, limit, offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := db.GetPool().Query(context.Background(), query, args...)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Cevap geçmişi alınamadı")
+		return
+	}
+	defer rows.Close()
+
+	attempts := []models.QuestionAttempt{}
+	for rows.Next() {
+		var a models.QuestionAttempt
+		if err := rows.Scan(&a.ID, &a.UserID, &a.QuestionID, &a.ChosenAnswer, &a.IsCorrect,
+			&a.TimeSpentMS, &a.Source, &a.SessionID, &a.CreatedAt); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Cevap geçmişi okunamadı")
+			return
+		}
+		attempts = append(attempts, a)
+	}
+
+	utils.SendSuccess(w, "Cevap geçmişi getirildi", attempts)
+}
+
+// Yanlışlar Defterim (?main_category_id, ?sub_category_id, ?category_id, ?include_mastered=true)
+func GetMyMistakes(w http.ResponseWriter, r *http.Request) {
+	conditions, args := mistakeFilterConditions(r.URL.Query(), currentUserID(r))
+
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT m.question_id, m.wrong_count, m.last_answer, m.first_wrong_at, m.last_wrong_at, m.mastered_at,
+               q.path_url, q.answer, q.solution_url, q.publisher_id, q.difficulty_level, q.status, q.body,
+               q.created_at, q.updated_at
+        FROM user_mistakes m
+        JOIN questions q ON q.id = m.question_id
+        WHERE `+strings.Join(conditions, " AND ")+`
+        ORDER BY m.last_wrong_at DESC`, args...)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Yanlışlar defteri alınamadı")
+		return
+	}
+	defer rows.Close()
+
+	mistakes := []models.Mistake{}
+	for rows.Next() {
+		var m models.Mistake
+		q := &m.Question
+		if err := rows.Scan(&q.ID, &m.WrongCount, &m.LastAnswer, &m.FirstWrongAt, &m.LastWrongAt, &m.MasteredAt,
+			&q.PathURL, &q.Answer, &q.SolutionURL, &q.PublisherID, &q.DifficultyLevel, &q.Status, &q.Body,
+			&q.CreatedAt, &q.UpdatedAt); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Yanlışlar defteri okunamadı")
+			return
+		}
+		signQuestionImages(q)
+		q.Body = services.SanitizeQuestionBody(q.Body)
+		mistakes = append(mistakes, m)
+	}
+
+	questions := make([]*models.Question, len(mistakes))
+	for i := range mistakes {
+		questions[i] = &mistakes[i].Question
+	}
+	if err := withholdOngoingExamAnswers(currentUserID(r), questions); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Sınav durumu kontrol edilemedi")
+		return
+	}
+
+	utils.SendSuccess(w, "Yanlışlar defteri getirildi", mistakes)
+}
+
+// Yanlışı Öğrenildi Olarak İşaretle
+func MarkMistakeMastered(w http.ResponseWriter, r *http.Request) {
+	setMistakeMastered(w, r, true)
+}
+
+// Öğrenildi İşaretini Kaldır
+func UnmarkMistakeMastered(w http.ResponseWriter, r *http.Request) {
+	setMistakeMastered(w, r, false)
+}
+
+func setMistakeMastered(w http.ResponseWriter, r *http.Request, mastered bool) {
+	result, err := db.GetPool().Exec(context.Background(), `
+        UPDATE user_mistakes
+        SET mastered_at = CASE WHEN $3 THEN CURRENT_TIMESTAMP END
+        WHERE user_id = $1 AND question_id = $2`,
+		currentUserID(r), chi.URLParam(r, "questionId"), mastered)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Yanlış güncellenemedi")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		utils.SendError(w, http.StatusNotFound, "Soru yanlışlar defterinde bulunamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "Yanlış güncellendi", map[string]interface{}{
+		"question_id": chi.URLParam(r, "questionId"),
+		"mastered":    mastered,
+	})
+}
+
+// Yanlışlardan Tekrar Seti Oluştur (defterle aynı filtreler; ?limit)
+func CreateMistakePracticeSet(w http.ResponseWriter, r *http.Request) {
+	userID := currentUserID(r)
+	if userID == 0 {
+		utils.SendError(w, http.StatusForbidden, "Misafir kullanıcılar tekrar seti oluşturamaz")
+		return
+	}
+
+	query := r.URL.Query()
+	query.Del("include_mastered")
+	conditions, args := mistakeFilterConditions(query, userID)
+	limit, _ := pageParams(query, 20, maxPracticeSetSize)
+
+	filters := make(map[string]string)
+	for _, key := range []string{"main_category_id", "sub_category_id", "category_id"} {
+		if v := query.Get(key); v != "" {
+			filters[key] = v
+		}
+	}
+	filtersJSON, _ := json.Marshal(filters)
+
+	tx, err := db.GetPool().Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Transaction başlatma hatası")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	// En çok yanlış yapılan ve en uzun süredir tekrar edilmeyen sorular önce gelir
+	args = append(args, limit)
+	rows, err := tx.Query(context.Background(), `
+        SELECT m.question_id
+        FROM user_mistakes m
+        JOIN questions q ON q.id = m.question_id
+        WHERE `+strings.Join(conditions, " AND ")+fmt.Sprintf(`
+        ORDER BY m.wrong_count DESC, m.last_wrong_at ASC
+        LIMIT $%d`, len(args)), args...)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Yanlışlar alınamadı")
+		return
+	}
+	var questionIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			utils.SendError(w, http.StatusInternalServerError, "Yanlışlar okunamadı")
+			return
+		}
+		questionIDs = append(questionIDs, id)
+	}
+	rows.Close()
+
+	if len(questionIDs) == 0 {
+		utils.SendError(w, http.StatusNotFound, "Tekrar edilecek yanlış bulunamadı")
+		return
+	}
+
+	var setID int64
+	if err := tx.QueryRow(context.Background(), `
+        INSERT INTO practice_sets (user_id, source, filters) VALUES ($1, $2, $3) RETURNING id`,
+		userID, models.AttemptSourceMistakes, filtersJSON).Scan(&setID); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Tekrar seti oluşturulamadı")
+		return
+	}
+	for i, questionID := range questionIDs {
+		if _, err := tx.Exec(context.Background(),
+			"INSERT INTO practice_set_items (set_id, position, question_id) VALUES ($1, $2, $3)",
+			setID, i+1, questionID); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Tekrar seti oluşturulamadı")
+			return
+		}
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Transaction commit hatası")
+		return
+	}
+
+	set, err := loadPracticeSet(setID, userID)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Tekrar seti alınamadı")
+		return
+	}
+	utils.SendResponse(w, http.StatusCreated, true, "Tekrar seti oluşturuldu", set, "")
+}
+
+// Tekrar Seti ve İlerlemesi
+func GetPracticeSet(w http.ResponseWriter, r *http.Request) {
+	setID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz set ID")
+		return
+	}
+
+	set, err := loadPracticeSet(setID, currentUserID(r))
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, http.StatusNotFound, "Tekrar seti bulunamadı")
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Tekrar seti alınamadı")
+		return
+	}
+	utils.SendSuccess(w, "Tekrar seti getirildi", set)
+}
+
+func loadPracticeSet(setID int64, userID int) (*models.PracticeSet, error) {
+	pool := db.GetPool()
+	var set models.PracticeSet
+	var filters []byte
+	if err := pool.QueryRow(context.Background(), `
+        SELECT id, source, filters, created_at FROM practice_sets WHERE id = $1 AND user_id = $2`,
+		setID, userID).Scan(&set.ID, &set.Source, &filters, &set.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filters, &set.Filters); err != nil {
+		return nil, err
+	}
+
+	rows, err := pool.Query(context.Background(), `
+        SELECT position, question_id, is_correct, answered_at
+        FROM practice_set_items WHERE set_id = $1 ORDER BY position`, setID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	set.Items = []models.PracticeSetItem{}
+	var questionIDs []int
+	for rows.Next() {
+		var item models.PracticeSetItem
+		if err := rows.Scan(&item.Position, &item.QuestionID, &item.IsCorrect, &item.AnsweredAt); err != nil {
+			return nil, err
+		}
+		set.Items = append(set.Items, item)
+		questionIDs = append(questionIDs, item.QuestionID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	set.Questions, err = loadPracticeQuestions(questionIDs, false, "")
+	return &set, err
+}
+
+// Tekrar için sorular; withSolutions false ise cevap ve çözüm gizlenir, sıralama korunur
+// status boş değilse yalnızca o durumdaki sorular döner
+func loadPracticeQuestions(ids []int, withSolutions bool, status string) ([]models.Question, error) {
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT q.id, q.path_url, q.answer, q.solution_url, q.publisher_id, q.difficulty_level, q.status, q.body,
+               q.created_at, q.updated_at
+        FROM unnest($1::int[]) WITH ORDINALITY AS ids(id, ord)
+        JOIN questions q ON q.id = ids.id AND q.deleted_at IS NULL AND ($2 = '' OR q.status = $2)
+        ORDER BY ids.ord`, ids, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []models.Question{}
+	for rows.Next() {
+		var q models.Question
+		if err := rows.Scan(&q.ID, &q.PathURL, &q.Answer, &q.SolutionURL, &q.PublisherID, &q.DifficultyLevel,
+			&q.Status, &q.Body, &q.CreatedAt, &q.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if !withSolutions {
+			hideQuestionSolution(&q)
+		}
+		signQuestionImages(&q)
+		q.Body = services.SanitizeQuestionBody(q.Body)
+		questions = append(questions, q)
+	}
+	return questions, rows.Err()
+}
+
+func hideQuestionSolution(q *models.Question) {
+	q.Answer, q.SolutionURL = "", ""
+	if q.Body != nil {
+		q.Body.Solution = ""
+	}
+}
+
+// Kullanıcının devam eden sınavındaki soruların cevap ve çözümlerini gizler
+func withholdOngoingExamAnswers(userID int, questions []*models.Question) error {
+	ids := make([]int, len(questions))
+	for i, q := range questions {
+		ids[i] = q.ID
+	}
+	locked, err := services.OngoingExamQuestionIDs(context.Background(), db.GetPool(), userID, ids)
+	if err != nil {
+		return err
+	}
+	for _, q := range questions {
+		if locked[q.ID] {
+			hideQuestionSolution(q)
+		}
+	}
+	return nil
+}
+
+// Yanlışlar defteri koşulları; kategori filtreleri eski kategori hiyerarşisine göre uygulanır
+func mistakeFilterConditions(query url.Values, userID int) ([]string, []interface{}) {
+	conditions := []string{"m.user_id = $1", "q.deleted_at IS NULL"}
+	args := []interface{}{userID}
+
+	if query.Get("include_mastered") != "true" {
+		conditions = append(conditions, "m.mastered_at IS NULL")
+	}
+	if v := query.Get("category_id"); v != "" {
+		args = append(args, v)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+            SELECT 1 FROM question_categories qc
+            WHERE qc.question_id = m.question_id AND qc.category_id = $%d)`, len(args)))
+	}
+	if v := query.Get("sub_category_id"); v != "" {
+		args = append(args, v)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+            SELECT 1 FROM question_categories qc
+            JOIN categories c ON c.id = qc.category_id AND c.deleted_at IS NULL
+            WHERE qc.question_id = m.question_id AND c.sub_category_id = $%d)`, len(args)))
+	}
+	if v := query.Get("main_category_id"); v != "" {
+		args = append(args, v)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+            SELECT 1 FROM question_categories qc
+            JOIN categories c ON c.id = qc.category_id AND c.deleted_at IS NULL
+            JOIN main_category_sub_category mcsc ON mcsc.sub_category_id = c.sub_category_id
+            WHERE qc.question_id = m.question_id AND mcsc.main_category_id = $%d)`, len(args)))
+	}
+	return conditions, args
+}
+
+// ?limit ve ?offset parametreleri
+func pageParams(query url.Values, defaultLimit, maxLimit int) (int, int) {
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+const maxQuestionNoteLength = 5000
+
+// Soruyu Kaydet
+func BookmarkQuestion(w http.ResponseWriter, r *http.Request) {
+	questionID, ok := visibleQuestionParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	result, err := db.GetPool().Exec(context.Background(), `
+        INSERT INTO question_bookmarks (user_id, question_id) VALUES ($1, $2)
+        ON CONFLICT (user_id, question_id) DO NOTHING`, currentUserID(r), questionID)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Soru kaydedilemedi")
+		return
+	}
+	// Popülerlik yalnızca yeni kayıtlarda artar
+	if result.RowsAffected() > 0 {
+		services.RecordQuestionEvent(questionEventActor(r), questionID, models.QuestionEventBookmark)
+	}
+
+	utils.SendSuccess(w, "Soru kaydedildi", map[string]interface{}{
+		"question_id": questionID,
+		"bookmarked":  true,
+	})
+}
+
+// Kaydı Kaldır
+func UnbookmarkQuestion(w http.ResponseWriter, r *http.Request) {
+	if _, err := db.GetPool().Exec(context.Background(),
+		"DELETE FROM question_bookmarks WHERE user_id = $1 AND question_id = $2",
+		currentUserID(r), chi.URLParam(r, "id")); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Kayıt kaldırılamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "Kayıt kaldırıldı", map[string]interface{}{
+		"question_id": chi.URLParam(r, "id"),
+		"bookmarked":  false,
+	})
+}
+
+// Kaydettiğim Sorular (?limit, ?offset); cevaplar gizlidir
+func GetMyBookmarks(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pageParams(r.URL.Query(), 50, 200)
+
+	// Yayından kaldırılan veya incelemedeki sorular yalnızca editörlere gösterilir
+	status := models.QuestionStatusPublished
+	if isQuestionEditor(r) {
+		status = ""
+	}
+
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT b.question_id, b.created_at
+        FROM question_bookmarks b
+        JOIN questions q ON q.id = b.question_id AND q.deleted_at IS NULL AND ($4 = '' OR q.status = $4)
+        WHERE b.user_id = $1
+        ORDER BY b.created_at DESC, b.question_id DESC
+        LIMIT $2 OFFSET $3`, currentUserID(r), limit, offset, status)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Kaydedilen sorular alınamadı")
+		return
+	}
+
+	bookmarks := []models.QuestionBookmark{}
+	var ids []int
+	for rows.Next() {
+		var b models.QuestionBookmark
+		if err := rows.Scan(&b.QuestionID, &b.BookmarkedAt); err != nil {
+			rows.Close()
+			utils.SendError(w, http.StatusInternalServerError, "Kaydedilen soru okunamadı")
+			return
+		}
+		bookmarks = append(bookmarks, b)
+		ids = append(ids, b.QuestionID)
+	}
+	rows.Close()
+
+	questions, err := loadPracticeQuestions(ids, false, status)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Kaydedilen sorular alınamadı")
+		return
+	}
+	byID := make(map[int]*models.Question, len(questions))
+	for i := range questions {
+		byID[questions[i].ID] = &questions[i]
+	}
+	for i := range bookmarks {
+		bookmarks[i].Question = byID[bookmarks[i].QuestionID]
+	}
+
+	utils.SendSuccess(w, "Kaydedilen sorular getirildi", bookmarks)
+}
+
+// Soru Notumu Kaydet (yalnızca kullanıcının kendisi görür)
+func SaveQuestionNote(w http.ResponseWriter, r *http.Request) {
+	questionID, ok := visibleQuestionParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req models.QuestionNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz istek gövdesi")
+		return
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	if req.Note == "" {
+		utils.SendError(w, http.StatusBadRequest, "Not boş olamaz")
+		return
+	}
+	if utf8.RuneCountInString(req.Note) > maxQuestionNoteLength {
+		utils.SendError(w, http.StatusBadRequest, "Not en fazla 5000 karakter olabilir")
+		return
+	}
+
+	var note models.QuestionNote
+	if err := db.GetPool().QueryRow(context.Background(), `
+        INSERT INTO question_notes (user_id, question_id, note) VALUES ($1, $2, $3)
+        ON CONFLICT (user_id, question_id) DO UPDATE
+        SET note = EXCLUDED.note, updated_at = CURRENT_TIMESTAMP
+        RETURNING question_id, note, created_at, updated_at`,
+		currentUserID(r), questionID, req.Note).Scan(
+		&note.QuestionID, &note.Note, &note.CreatedAt, &note.UpdatedAt); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Not kaydedilemedi")
+		return
+	}
+
+	utils.SendSuccess(w, "Not kaydedildi", note)
+}
+
+// Soru Notum
+func GetQuestionNote(w http.ResponseWriter, r *http.Request) {
+	var note models.QuestionNote
+	err := db.GetPool().QueryRow(context.Background(), `
+        SELECT question_id, note, created_at, updated_at
+        FROM question_notes WHERE user_id = $1 AND question_id = $2`,
+		currentUserID(r), chi.URLParam(r, "id")).Scan(
+		&note.QuestionID, &note.Note, &note.CreatedAt, &note.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, http.StatusNotFound, "Not bulunamadı")
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Not alınamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "Not getirildi", note)
+}
+
+// Soru Notumu Sil
+func DeleteQuestionNote(w http.ResponseWriter, r *http.Request) {
+	result, err := db.GetPool().Exec(context.Background(),
+		"DELETE FROM question_notes WHERE user_id = $1 AND question_id = $2",
+		currentUserID(r), chi.URLParam(r, "id"))
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Not silinemedi")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		utils.SendError(w, http.StatusNotFound, "Not bulunamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "Not silindi", nil)
+}
+
+// Notlarım (?limit, ?offset)
+func GetMyNotes(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pageParams(r.URL.Query(), 50, 200)
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT n.question_id, n.note, n.created_at, n.updated_at
+        FROM question_notes n
+        JOIN questions q ON q.id = n.question_id AND q.deleted_at IS NULL
+        WHERE n.user_id = $1
+        ORDER BY n.updated_at DESC, n.question_id DESC
+        LIMIT $2 OFFSET $3`, currentUserID(r), limit, offset)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Notlar alınamadı")
+		return
+	}
+	defer rows.Close()
+
+	notes := []models.QuestionNote{}
+	for rows.Next() {
+		var note models.QuestionNote
+		if err := rows.Scan(&note.QuestionID, &note.Note, &note.CreatedAt, &note.UpdatedAt); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Not okunamadı")
+			return
+		}
+		notes = append(notes, note)
+	}
+
+	utils.SendSuccess(w, "Notlar getirildi", notes)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/utils"
+	"time"
+)
+
+// Katalog yanıtlarının istemci ve ara sunucularda önbellekte tutulma süresi
+const catalogMaxAge = 5 * time.Minute
+
+// Katalog Kategori Ağacı
+func GetCatalogTree(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT mc.id, mc.name, mc.slug, sc.id, sc.name, sc.slug, c.id, c.name, c.slug
+        FROM main_categories mc
+        LEFT JOIN main_category_sub_category mcsc ON mc.id = mcsc.main_category_id
+        LEFT JOIN sub_categories sc ON mcsc.sub_category_id = sc.id AND sc.deleted_at IS NULL
+        LEFT JOIN categories c ON sc.id = c.sub_category_id AND c.deleted_at IS NULL
+        WHERE mc.deleted_at IS NULL
+        ORDER BY mc.position, mc.id, mcsc.position, sc.id, c.position, c.id`)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error fetching catalog")
+		return
+	}
+	defer rows.Close()
+
+	tree := []models.CatalogMainCategory{}
+	for rows.Next() {
+		var main models.CategoryRef
+		var subID, categoryID *int
+		var subName, subSlug, categoryName, categorySlug *string
+		if err := rows.Scan(&main.ID, &main.Name, &main.Slug, &subID, &subName, &subSlug,
+			&categoryID, &categoryName, &categorySlug); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Error scanning catalog")
+			return
+		}
+
+		if n := len(tree); n == 0 || tree[n-1].ID != main.ID {
+			tree = append(tree, models.CatalogMainCategory{
+				ID: main.ID, Name: main.Name, Slug: main.Slug,
+				SubCategories: []models.CatalogSubCategory{},
+			})
+		}
+		mainNode := &tree[len(tree)-1]
+		if subID == nil {
+			continue
+		}
+
+		if n := len(mainNode.SubCategories); n == 0 || mainNode.SubCategories[n-1].ID != *subID {
+			mainNode.SubCategories = append(mainNode.SubCategories, models.CatalogSubCategory{
+				ID: *subID, Name: *subName, Slug: *subSlug,
+				Categories: []models.CategoryRef{},
+			})
+		}
+		if categoryID != nil {
+			sub := &mainNode.SubCategories[len(mainNode.SubCategories)-1]
+			sub.Categories = append(sub.Categories, models.CategoryRef{ID: *categoryID, Name: *categoryName, Slug: *categorySlug})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error reading catalog")
+		return
+	}
+
+	utils.SendCacheable(w, r, catalogMaxAge, "Catalog fetched successfully", tree)
+}
+
+// Katalog Kategori Detayı (/catalog/categories/tyt/matematik)
+func GetCatalogCategory(w http.ResponseWriter, r *http.Request) {
+	node, ok := resolveCategorySlugNode(w, r, "/catalog/categories/")
+	if !ok {
+		return
+	}
+
+	utils.SendCacheable(w, r, catalogMaxAge, "Category fetched successfully", node)
+}
+
+// Katalog Yayıncı Listesi
+func GetCatalogPublishers(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.GetPool().Query(context.Background(),
+		`SELECT id, name, COALESCE(website_url, ''), COALESCE(logo_url, '')
+         FROM publishers
+         ORDER BY name, id`)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error fetching publishers")
+		return
+	}
+	defer rows.Close()
+
+	publishers := []models.CatalogPublisher{}
+	for rows.Next() {
+		var p models.CatalogPublisher
+		if err := rows.Scan(&p.ID, &p.Name, &p.WebsiteURL, &p.LogoURL); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Error scanning publisher")
+			return
+		}
+		publishers = append(publishers, p)
+	}
+
+	utils.SendCacheable(w, r, catalogMaxAge, "Publishers fetched successfully", publishers)
+}
@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// Silme işleminden etkilenecek düğüm ve silinmemiş alt düğümleri
+type deletionScope struct {
+	Level   string
+	ID      int
+	NodeID  int
+	NodeIDs []int
+}
+
+// Ana Kategori Silme Önizlemesi
+func PreviewMainCategoryDeletion(w http.ResponseWriter, r *http.Request) {
+	previewDeletion(w, r, "main")
+}
+
+// Alt Kategori Silme Önizlemesi
+func PreviewSubCategoryDeletion(w http.ResponseWriter, r *http.Request) {
+	previewDeletion(w, r, "sub")
+}
+
+// Kategori Silme Önizlemesi
+func PreviewCategoryDeletion(w http.ResponseWriter, r *http.Request) {
+	previewDeletion(w, r, "category")
+}
+
+// Ana Kategori Silme
+func DeleteMainCategory(w http.ResponseWriter, r *http.Request) {
+	deleteCategoryLevel(w, r, "main")
+}
+
+// Alt Kategori Silme
+func DeleteSubCategory(w http.ResponseWriter, r *http.Request) {
+	deleteCategoryLevel(w, r, "sub")
+}
+
+// Kategori Silme
+func DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	deleteCategoryLevel(w, r, "category")
+}
+
+// Ana Kategoriyi Geri Yükleme
+func RestoreMainCategory(w http.ResponseWriter, r *http.Request) {
+	restoreCategoryLevel(w, r, "main")
+}
+
+// Alt Kategoriyi Geri Yükleme
+func RestoreSubCategory(w http.ResponseWriter, r *http.Request) {
+	restoreCategoryLevel(w, r, "sub")
+}
+
+// Kategoriyi Geri Yükleme
+func RestoreCategory(w http.ResponseWriter, r *http.Request) {
+	restoreCategoryLevel(w, r, "category")
+}
+
+// Silinmiş Kategorileri Listele
+func GetDeletedCategories(w http.ResponseWriter, r *http.Request) {
+	pool := db.GetPool()
+	rows, err := pool.Query(context.Background(), `
+        SELECT COALESCE(legacy_level, 'node'), COALESCE(legacy_id, id), id, name, deleted_at, deleted_by
+        FROM category_tree
+        WHERE deleted_at IS NOT NULL
+        ORDER BY deleted_at DESC, id`)
+
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error fetching deleted categories")
+		return
+	}
+	defer rows.Close()
+
+	items := []models.DeletedCategory{}
+	for rows.Next() {
+		var item models.DeletedCategory
+		if err := rows.Scan(&item.Level, &item.ID, &item.NodeID, &item.Name, &item.DeletedAt, &item.DeletedBy); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Error scanning deleted category")
+			return
+		}
+		item.RestorableUntil = item.DeletedAt.Add(services.CategoryRetention)
+		items = append(items, item)
+	}
+
+	utils.SendSuccess(w, "Deleted categories fetched successfully", items)
+}
+
+func previewDeletion(w http.ResponseWriter, r *http.Request, level string) {
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	scope, ok := resolveDeletionScope(w, r, tx, level)
+	if !ok {
+		return
+	}
+
+	impact, err := deletionImpact(tx, scope)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error calculating deletion impact")
+		return
+	}
+
+	utils.SendSuccess(w, "Deletion impact calculated successfully", impact)
+}
+
+// Silme; reassign_to ile sorular hedef kategoriye taşınır, force=true ile
+// sorular silinen kategoriye bağlı bırakılır ve geri yüklemede geri gelir
+func deleteCategoryLevel(w http.ResponseWriter, r *http.Request, level string) {
+	force := r.URL.Query().Get("force") == "true"
+	reassignTo := 0
+	if v := r.URL.Query().Get("reassign_to"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			utils.SendError(w, http.StatusBadRequest, "Invalid reassign_to")
+			return
+		}
+		reassignTo = id
+	}
+
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	scope, ok := resolveDeletionScope(w, r, tx, level)
+	if !ok {
+		return
+	}
+
+	impact, err := deletionImpact(tx, scope)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error calculating deletion impact")
+		return
+	}
+
+	if reassignTo == 0 && !force && impact.Questions > 0 {
+		utils.SendResponse(w, http.StatusConflict, false, "", impact,
+			"Questions are linked to this category; pass reassign_to=<category_id> or force=true")
+		return
+	}
+
+	var reassigned int64
+	if reassignTo != 0 {
+		targetNodeID, err := services.CategoryNodeForLegacy(context.Background(), tx, "category", reassignTo)
+		if errors.Is(err, services.ErrCategoryNodeNotFound) {
+			utils.SendError(w, http.StatusNotFound, "Target category not found")
+			return
+		}
+		if err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Error checking target category")
+			return
+		}
+		for _, id := range scope.NodeIDs {
+			if id == targetNodeID {
+				utils.SendError(w, http.StatusBadRequest, "reassign_to cannot be a category that is being deleted")
+				return
+			}
+		}
+
+		var live bool
+		err = tx.QueryRow(context.Background(),
+			"SELECT deleted_at IS NULL FROM category_tree WHERE id = $1", targetNodeID).Scan(&live)
+		if err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Error checking target category")
+			return
+		}
+		if !live {
+			utils.SendError(w, http.StatusNotFound, "Target category not found")
+			return
+		}
+
+		reassigned, err = services.ReassignCategoryQuestions(context.Background(), tx, scope.NodeIDs, targetNodeID)
+		if err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Error reassigning questions")
+			return
+		}
+	}
+
+	// Alt ağaç aynı zaman damgasıyla silinir; geri yüklemede birlikte silinenler bulunur
+	deletedAt, err := services.DeleteCategoryNode(context.Background(), tx, scope.NodeID, currentUserID(r))
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error deleting category")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
+	utils.SendSuccess(w, "Category deleted successfully; it can be restored until "+
+		deletedAt.Add(services.CategoryRetention).Format(time.RFC3339), map[string]interface{}{
+		"impact":               impact,
+		"reassigned_questions": reassigned,
+		"restorable_until":     deletedAt.Add(services.CategoryRetention),
+	})
+}
+
+func restoreCategoryLevel(w http.ResponseWriter, r *http.Request, level string) {
+	id, ok := deletionTargetID(w, r, level)
+	if !ok {
+		return
+	}
+
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	nodeID, ok := deletionTargetNode(w, r, tx, level, id, false)
+	if !ok {
+		return
+	}
+
+	// Öğeyi ve onunla aynı anda silinen alt öğeleri geri yükle
+	err = services.RestoreCategoryNode(context.Background(), tx, nodeID)
+	switch {
+	case errors.Is(err, services.ErrCategoryNodeNotDeleted):
+		utils.SendError(w, http.StatusBadRequest, "Category is not deleted")
+		return
+	case errors.Is(err, services.ErrCategoryRestoreExpired):
+		utils.SendError(w, http.StatusGone, "Restore period has expired")
+		return
+	case errors.Is(err, services.ErrCategoryParentDeleted):
+		utils.SendError(w, http.StatusConflict, "Restore the parent category first")
+		return
+	case err != nil:
+		sendCategoryNodeError(w, err, "Error restoring category")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
+	utils.SendSuccess(w, "Category restored successfully", nil)
+}
+
+func deletionTargetID(w http.ResponseWriter, r *http.Request, level string) (int, bool) {
+	param := map[string]string{"main": "mainId", "sub": "subId", "category": "categoryId"}[level]
+	id, err := strconv.Atoi(chi.URLParam(r, param))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid category id")
+		return 0, false
+	}
+	return id, true
+}
+
+// Öğenin ağaçtaki düğümünü bulur; kategoriler URL'deki alt kategoriye ait olmalıdır
+func deletionTargetNode(w http.ResponseWriter, r *http.Request, tx pgx.Tx, level string, id int, live bool) (int, bool) {
+	if level == "category" {
+		var exists bool
+		err := tx.QueryRow(context.Background(),
+			"SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1 AND sub_category_id = $2)",
+			id, chi.URLParam(r, "subId")).Scan(&exists)
+		if err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Error checking category")
+			return 0, false
+		}
+		if !exists {
+			utils.SendError(w, http.StatusNotFound, "Category not found")
+			return 0, false
+		}
+	}
+
+	nodeID, err := services.CategoryNodeForLegacy(context.Background(), tx, level, id)
+	if err == nil && live {
+		err = tx.QueryRow(context.Background(),
+			"SELECT id FROM category_tree WHERE id = $1 AND deleted_at IS NULL", nodeID).Scan(&nodeID)
+	}
+	if errors.Is(err, services.ErrCategoryNodeNotFound) || errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, http.StatusNotFound, "Category not found")
+		return 0, false
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error checking category")
+		return 0, false
+	}
+	return nodeID, true
+}
+
+// Silinecek öğeyi bulur ve ağaçta altında kalan silinmemiş düğümleri toplar
+func resolveDeletionScope(w http.ResponseWriter, r *http.Request, tx pgx.Tx, level string) (*deletionScope, bool) {
+	id, ok := deletionTargetID(w, r, level)
+	if !ok {
+		return nil, false
+	}
+
+	nodeID, ok := deletionTargetNode(w, r, tx, level, id, true)
+	if !ok {
+		return nil, false
+	}
+
+	nodeIDs, err := services.LiveCategorySubtree(context.Background(), tx, nodeID)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error fetching categories")
+		return nil, false
+	}
+
+	return &deletionScope{Level: level, ID: id, NodeID: nodeID, NodeIDs: nodeIDs}, true
+}
+
+func deletionImpact(tx pgx.Tx, scope *deletionScope) (*models.CategoryDeletionImpact, error) {
+	impact := &models.CategoryDeletionImpact{Level: scope.Level, ID: scope.ID}
+
+	// Alt kategori ve kategori sayıları düğümün kendisi hariç sayılır; daha derin düğümler
+	// kategori olarak sayılır. Kalan başka bir (silinmemiş) düğümü olmayan sorular yetim kalır.
+	err := tx.QueryRow(context.Background(), `
+        SELECT COUNT(*) FILTER (WHERE legacy_level = 'sub'),
+               COUNT(*) FILTER (WHERE legacy_level = 'category' OR legacy_level IS NULL)
+        FROM category_tree
+        WHERE id = ANY($1) AND id <> $2`,
+		scope.NodeIDs, scope.NodeID).Scan(&impact.SubCategories, &impact.Categories)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(context.Background(), `
+        SELECT COUNT(DISTINCT qcn.question_id),
+               COUNT(DISTINCT qcn.question_id) FILTER (WHERE NOT EXISTS (
+                   SELECT 1
+                   FROM question_category_nodes other
+                   JOIN category_tree t ON t.id = other.node_id AND t.deleted_at IS NULL
+                   WHERE other.question_id = qcn.question_id AND other.node_id <> ALL($1)
+               ))
+        FROM question_category_nodes qcn
+        WHERE qcn.node_id = ANY($1)`,
+		scope.NodeIDs).Scan(&impact.Questions, &impact.OrphanedQuestions)
+	if err != nil {
+		return nil, err
+	}
+	return impact, nil
+}
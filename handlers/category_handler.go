@@ -3,12 +3,16 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"osymapp/db"
 	"osymapp/models"
+	"osymapp/services"
 	"osymapp/utils"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 )
 
 // Kategori Hiyerarşisi Oluşturma
@@ -34,12 +38,9 @@ func CreateCategoryHierarchy(w http.ResponseWriter, r *http.Request) {
 	defer tx.Rollback(context.Background())
 
 	// Ana kategori oluştur
-	var mainCatID int
-	err = tx.QueryRow(context.Background(),
-		"INSERT INTO main_categories (name) VALUES ($1) RETURNING id",
-		req.MainCategory).Scan(&mainCatID)
+	mainNode, err := services.CreateCategoryNode(context.Background(), tx, nil, req.MainCategory)
 	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error creating main category")
+		sendCategoryNodeError(w, err, "Error creating main category")
 		return
 	}
 
@@ -55,35 +56,9 @@ func CreateCategoryHierarchy(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		// Alt kategori oluştur
-		var subCatID int
-		err = tx.QueryRow(context.Background(),
-			"INSERT INTO sub_categories (name) VALUES ($1) RETURNING id",
-			sub.SubCategory).Scan(&subCatID)
-		if err != nil {
-			utils.SendError(w, http.StatusInternalServerError, "Error creating sub category")
-			return
-		}
-
-		// İlişki tablosuna ekle
-		_, err = tx.Exec(context.Background(),
-			"INSERT INTO main_category_sub_category (main_category_id, sub_category_id) VALUES ($1, $2)",
-			mainCatID, subCatID)
-		if err != nil {
-			utils.SendError(w, http.StatusInternalServerError, "Error creating relation")
+		if !createSubCategoryNodes(w, tx, mainNode.ID, sub) {
 			return
 		}
-
-		// Kategorileri ekle
-		for _, catName := range sub.Categories {
-			_, err = tx.Exec(context.Background(),
-				"INSERT INTO categories (sub_category_id, name) VALUES ($1, $2)",
-				subCatID, catName)
-			if err != nil {
-				utils.SendError(w, http.StatusInternalServerError, "Error creating category")
-				return
-			}
-		}
 	}
 
 	if err := tx.Commit(context.Background()); err != nil {
@@ -111,36 +86,15 @@ func AddSubCategory(w http.ResponseWriter, r *http.Request) {
 	}
 	defer tx.Rollback(context.Background())
 
-	// Alt kategori oluştur
-	var subCatID int
-	err = tx.QueryRow(context.Background(),
-		"INSERT INTO sub_categories (name) VALUES ($1) RETURNING id",
-		subCat.SubCategory).Scan(&subCatID)
-	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error creating sub category")
+	mainNodeID, ok := legacyCategoryNode(w, tx, "main", mainCategoryID, "Main category not found")
+	if !ok {
 		return
 	}
 
-	// İlişki tablosuna ekle
-	_, err = tx.Exec(context.Background(),
-		"INSERT INTO main_category_sub_category (main_category_id, sub_category_id) VALUES ($1, $2)",
-		mainCategoryID, subCatID)
-	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error creating relation")
+	if !createSubCategoryNodes(w, tx, mainNodeID, subCat) {
 		return
 	}
 
-	// Kategorileri ekle
-	for _, catName := range subCat.Categories {
-		_, err = tx.Exec(context.Background(),
-			"INSERT INTO categories (sub_category_id, name) VALUES ($1, $2)",
-			subCatID, catName)
-		if err != nil {
-			utils.SendError(w, http.StatusInternalServerError, "Error creating category")
-			return
-		}
-	}
-
 	if err := tx.Commit(context.Background()); err != nil {
 		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
 		return
@@ -157,51 +111,67 @@ func GetCategoryHierarchy(w http.ResponseWriter, r *http.Request) {
             SELECT 
                 mc.id as main_id,
                 mc.name as main_name,
+                mc.slug as main_slug,
+                mc.position as main_position,
                 sc.id as sub_id,
                 sc.name as sub_name,
+                sc.slug as sub_slug,
+                mcsc.position as sub_position,
                 c.id as category_id,
-                c.name as category_name
+                c.name as category_name,
+                c.slug as category_slug,
+                c.position as category_position
             FROM main_categories mc
             LEFT JOIN main_category_sub_category mcsc ON mc.id = mcsc.main_category_id
-            LEFT JOIN sub_categories sc ON mcsc.sub_category_id = sc.id
-            LEFT JOIN categories c ON sc.id = c.sub_category_id
+            LEFT JOIN sub_categories sc ON mcsc.sub_category_id = sc.id AND sc.deleted_at IS NULL
+            LEFT JOIN categories c ON sc.id = c.sub_category_id AND c.deleted_at IS NULL
+            WHERE mc.deleted_at IS NULL
         ),
         SubCategories AS (
             SELECT 
                 main_id,
                 main_name,
+                main_slug,
+                main_position,
                 sub_id,
                 sub_name,
+                sub_slug,
+                sub_position,
                 json_agg(
                     json_build_object(
                         'id', category_id,
-                        'name', category_name
-                    ) ORDER BY category_id
+                        'name', category_name,
+                        'slug', category_slug
+                    ) ORDER BY category_position, category_id
                 ) FILTER (WHERE category_id IS NOT NULL) as categories
             FROM CategoryData
-            GROUP BY main_id, main_name, sub_id, sub_name
+            GROUP BY main_id, main_name, main_slug, main_position, sub_id, sub_name, sub_slug, sub_position
         ),
         MainCategories AS (
             SELECT 
                 main_id,
                 main_name,
+                main_slug,
+                main_position,
                 json_agg(
                     json_build_object(
                         'id', sub_id,
                         'name', sub_name,
+                        'slug', sub_slug,
                         'categories', COALESCE(categories, '[]'::json)
-                    ) ORDER BY sub_id
+                    ) ORDER BY sub_position, sub_id
                 ) FILTER (WHERE sub_id IS NOT NULL) as sub_categories
             FROM SubCategories
-            GROUP BY main_id, main_name
+            GROUP BY main_id, main_name, main_slug, main_position
         )
         SELECT COALESCE(
             json_agg(
                 json_build_object(
                     'id', main_id,
                     'name', main_name,
+                    'slug', main_slug,
                     'sub_categories', COALESCE(sub_categories, '[]'::json)
-                ) ORDER BY main_id
+                ) ORDER BY main_position, main_id
             ),
             '[]'::json
         ) as hierarchy
@@ -257,13 +227,15 @@ func AddCategoriesToSub(w http.ResponseWriter, r *http.Request) {
 	}
 	defer tx.Rollback(context.Background())
 
+	subNodeID, ok := legacyCategoryNode(w, tx, "sub", subCategoryID, "Sub category not found")
+	if !ok {
+		return
+	}
+
 	// Kategorileri ekle
 	for _, catName := range request.Categories {
-		_, err = tx.Exec(context.Background(),
-			"INSERT INTO categories (sub_category_id, name) VALUES ($1, $2)",
-			subCategoryID, catName)
-		if err != nil {
-			utils.SendError(w, http.StatusInternalServerError, "Error creating category")
+		if _, err := services.CreateCategoryNode(context.Background(), tx, &subNodeID, catName); err != nil {
+			sendCategoryNodeError(w, err, "Error creating category")
 			return
 		}
 	}
@@ -278,6 +250,7 @@ func AddCategoriesToSub(w http.ResponseWriter, r *http.Request) {
 
 // Ana Kategoriye Göre Detayları Getir
 func GetMainCategoryDetails(w http.ResponseWriter, r *http.Request) {
+	// Slug veya (geriye dönük uyumluluk için) tam ad kabul edilir
 	mainCategoryName := chi.URLParam(r, "name")
 
 	pool := db.GetPool()
@@ -288,13 +261,14 @@ func GetMainCategoryDetails(w http.ResponseWriter, r *http.Request) {
                 mc.name as main_name,
                 sc.id as sub_id,
                 sc.name as sub_name,
-                COALESCE(json_agg(c.name ORDER BY c.id) FILTER (WHERE c.id IS NOT NULL), '[]'::json) as categories
+                mcsc.position as sub_position,
+                COALESCE(json_agg(c.name ORDER BY c.position, c.id) FILTER (WHERE c.id IS NOT NULL), '[]'::json) as categories
             FROM main_categories mc
             LEFT JOIN main_category_sub_category mcsc ON mc.id = mcsc.main_category_id
-            LEFT JOIN sub_categories sc ON mcsc.sub_category_id = sc.id
-            LEFT JOIN categories c ON sc.id = c.sub_category_id
-            WHERE mc.name = $1
-            GROUP BY mc.id, mc.name, sc.id, sc.name
+            LEFT JOIN sub_categories sc ON mcsc.sub_category_id = sc.id AND sc.deleted_at IS NULL
+            LEFT JOIN categories c ON sc.id = c.sub_category_id AND c.deleted_at IS NULL
+            WHERE (mc.slug = $1 OR mc.name = $1) AND mc.deleted_at IS NULL
+            GROUP BY mc.id, mc.name, sc.id, sc.name, mcsc.position
         )
         SELECT 
             main_id,
@@ -303,7 +277,7 @@ func GetMainCategoryDetails(w http.ResponseWriter, r *http.Request) {
                 json_build_object(
                     'sub_category', sub_name,
                     'categories', categories
-                ) ORDER BY sub_id
+                ) ORDER BY sub_position, sub_id
             ) FILTER (WHERE sub_name IS NOT NULL), '[]'::json) as sub_categories
         FROM CategoryGroups
         GROUP BY main_id, main_name
@@ -353,40 +327,44 @@ func GetAllCategories(w http.ResponseWriter, r *http.Request) {
             SELECT 
                 mc.id as main_id,
                 mc.name as main_name,
+                mc.position as main_position,
                 sc.id as sub_id,
                 sc.name as sub_name,
+                mcsc.position as sub_position,
                 COALESCE(json_agg(
                     json_build_object(
                         'id', c.id,
                         'name', c.name
-                    ) ORDER BY c.id
+                    ) ORDER BY c.position, c.id
                 ) FILTER (WHERE c.id IS NOT NULL), '[]'::json) as categories
             FROM main_categories mc
             LEFT JOIN main_category_sub_category mcsc ON mc.id = mcsc.main_category_id
-            LEFT JOIN sub_categories sc ON mcsc.sub_category_id = sc.id
-            LEFT JOIN categories c ON sc.id = c.sub_category_id
-            GROUP BY mc.id, mc.name, sc.id, sc.name
+            LEFT JOIN sub_categories sc ON mcsc.sub_category_id = sc.id AND sc.deleted_at IS NULL
+            LEFT JOIN categories c ON sc.id = c.sub_category_id AND c.deleted_at IS NULL
+            WHERE mc.deleted_at IS NULL
+            GROUP BY mc.id, mc.name, mc.position, sc.id, sc.name, mcsc.position
         ),
         SubCategoryGroups AS (
             SELECT 
                 main_id,
                 main_name,
+                main_position,
                 json_agg(
                     json_build_object(
                         'id', sub_id,
                         'sub_category', sub_name,
                         'categories', categories
-                    ) ORDER BY sub_id
+                    ) ORDER BY sub_position, sub_id
                 ) FILTER (WHERE sub_id IS NOT NULL) as sub_categories
             FROM CategoryGroups
-            GROUP BY main_id, main_name
+            GROUP BY main_id, main_name, main_position
         )
         SELECT json_agg(
             json_build_object(
                 'id', main_id,
                 'main_category', main_name,
                 'sub_categories', COALESCE(sub_categories, '[]'::json)
-            ) ORDER BY main_id
+            ) ORDER BY main_position, main_id
         ) as all_categories
         FROM SubCategoryGroups`)
 
@@ -420,61 +398,6 @@ func GetAllCategories(w http.ResponseWriter, r *http.Request) {
 	utils.SendSuccess(w, "All categories fetched successfully", categories)
 }
 
-// Ana Kategori Silme
-func DeleteMainCategory(w http.ResponseWriter, r *http.Request) {
-	mainCategoryID := chi.URLParam(r, "mainId")
-
-	pool := db.GetPool()
-	tx, err := pool.Begin(context.Background())
-	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
-		return
-	}
-	defer tx.Rollback(context.Background())
-
-	// İlişkili alt kategorileri bul
-	rows, err := tx.Query(context.Background(),
-		"SELECT sub_category_id FROM main_category_sub_category WHERE main_category_id = $1",
-		mainCategoryID)
-	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error fetching related sub categories")
-		return
-	}
-	defer rows.Close()
-
-	// Alt kategorilerin ID'lerini topla
-	var subCategoryIDs []string
-	for rows.Next() {
-		var subID string
-		if err := rows.Scan(&subID); err != nil {
-			utils.SendError(w, http.StatusInternalServerError, "Error scanning sub category ID")
-			return
-		}
-		subCategoryIDs = append(subCategoryIDs, subID)
-	}
-
-	// Ana kategoriyi sil (cascade ile ilişkiler otomatik silinecek)
-	result, err := tx.Exec(context.Background(),
-		"DELETE FROM main_categories WHERE id = $1",
-		mainCategoryID)
-	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error deleting main category")
-		return
-	}
-
-	if rowsAffected := result.RowsAffected(); rowsAffected == 0 {
-		utils.SendError(w, http.StatusNotFound, "Main category not found")
-		return
-	}
-
-	if err := tx.Commit(context.Background()); err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
-		return
-	}
-
-	utils.SendSuccess(w, "Main category and all related items deleted successfully", nil)
-}
-
 // Ana Kategori Güncelleme
 func UpdateMainCategory(w http.ResponseWriter, r *http.Request) {
 	mainCategoryID := chi.URLParam(r, "mainId")
@@ -493,28 +416,6 @@ func UpdateMainCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pool := db.GetPool()
-	result, err := pool.Exec(context.Background(),
-		"UPDATE main_categories SET name = $1 WHERE id = $2",
-		request.Name, mainCategoryID)
-
-	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error updating main category")
-		return
-	}
-
-	if rowsAffected := result.RowsAffected(); rowsAffected == 0 {
-		utils.SendError(w, http.StatusNotFound, "Main category not found")
-		return
-	}
-
-	utils.SendSuccess(w, "Main category updated successfully", request)
-}
-
-// Alt Kategori Silme
-func DeleteSubCategory(w http.ResponseWriter, r *http.Request) {
-	subCategoryID := chi.URLParam(r, "subId")
-
 	pool := db.GetPool()
 	tx, err := pool.Begin(context.Background())
 	if err != nil {
@@ -523,26 +424,8 @@ func DeleteSubCategory(w http.ResponseWriter, r *http.Request) {
 	}
 	defer tx.Rollback(context.Background())
 
-	// İlişki tablosundan kaydı sil
-	_, err = tx.Exec(context.Background(),
-		"DELETE FROM main_category_sub_category WHERE sub_category_id = $1",
-		subCategoryID)
-	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error deleting relation")
-		return
-	}
-
-	// Alt kategoriyi sil (cascade ile kategoriler otomatik silinecek)
-	result, err := tx.Exec(context.Background(),
-		"DELETE FROM sub_categories WHERE id = $1",
-		subCategoryID)
-	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error deleting sub category")
-		return
-	}
-
-	if rowsAffected := result.RowsAffected(); rowsAffected == 0 {
-		utils.SendError(w, http.StatusNotFound, "Sub category not found")
+	slug, ok := renameLegacyCategory(w, tx, "main", mainCategoryID, request.Name, "Main category not found")
+	if !ok {
 		return
 	}
 
@@ -551,7 +434,10 @@ func DeleteSubCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	utils.SendSuccess(w, "Sub category and all related items deleted successfully", nil)
+	utils.SendSuccess(w, "Main category updated successfully", map[string]interface{}{
+		"name": request.Name,
+		"slug": slug,
+	})
 }
 
 // Alt Kategori Güncelleme
@@ -573,62 +459,27 @@ func UpdateSubCategory(w http.ResponseWriter, r *http.Request) {
 	}
 
 	pool := db.GetPool()
-	result, err := pool.Exec(context.Background(),
-		"UPDATE sub_categories SET name = $1 WHERE id = $2",
-		request.Name, subCategoryID)
-
-	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error updating sub category")
-		return
-	}
-
-	if rowsAffected := result.RowsAffected(); rowsAffected == 0 {
-		utils.SendError(w, http.StatusNotFound, "Sub category not found")
-		return
-	}
-
-	utils.SendSuccess(w, "Sub category updated successfully", request)
-}
-
-// Kategori Silme
-func DeleteCategory(w http.ResponseWriter, r *http.Request) {
-	categoryID := chi.URLParam(r, "categoryId")
-	subCategoryID := chi.URLParam(r, "subId")
-
-	pool := db.GetPool()
-
-	// Önce kategorinin belirtilen alt kategoriye ait olduğunu kontrol et
-	var exists bool
-	err := pool.QueryRow(context.Background(),
-		"SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1 AND sub_category_id = $2)",
-		categoryID, subCategoryID).Scan(&exists)
-
+	tx, err := pool.Begin(context.Background())
 	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error checking category")
-		return
-	}
-
-	if !exists {
-		utils.SendError(w, http.StatusNotFound, "Category not found in specified sub category")
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
 		return
 	}
+	defer tx.Rollback(context.Background())
 
-	// Kategoriyi sil
-	result, err := pool.Exec(context.Background(),
-		"DELETE FROM categories WHERE id = $1 AND sub_category_id = $2",
-		categoryID, subCategoryID)
-
-	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error deleting category")
+	slug, ok := renameLegacyCategory(w, tx, "sub", subCategoryID, request.Name, "Sub category not found")
+	if !ok {
 		return
 	}
 
-	if rowsAffected := result.RowsAffected(); rowsAffected == 0 {
-		utils.SendError(w, http.StatusNotFound, "Category not found")
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
 		return
 	}
 
-	utils.SendSuccess(w, "Category deleted successfully", nil)
+	utils.SendSuccess(w, "Sub category updated successfully", map[string]interface{}{
+		"name": request.Name,
+		"slug": slug,
+	})
 }
 
 // Kategori Güncelleme
@@ -668,22 +519,27 @@ func UpdateCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Kategoriyi güncelle
-	result, err := pool.Exec(context.Background(),
-		"UPDATE categories SET name = $1 WHERE id = $2 AND sub_category_id = $3",
-		request.Name, categoryID, subCategoryID)
-
+	tx, err := pool.Begin(context.Background())
 	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Error updating category")
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
 		return
 	}
+	defer tx.Rollback(context.Background())
 
-	if rowsAffected := result.RowsAffected(); rowsAffected == 0 {
-		utils.SendError(w, http.StatusNotFound, "Category not found")
+	slug, ok := renameLegacyCategory(w, tx, "category", categoryID, request.Name, "Category not found")
+	if !ok {
 		return
 	}
 
-	utils.SendSuccess(w, "Category updated successfully", request)
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
+	utils.SendSuccess(w, "Category updated successfully", map[string]interface{}{
+		"name": request.Name,
+		"slug": slug,
+	})
 }
 
 // Alt Kategorinin Kategorilerini Getir
@@ -694,10 +550,11 @@ func GetSubCategoryCategories(w http.ResponseWriter, r *http.Request) {
 	rows, err := pool.Query(context.Background(), `
         SELECT 
             c.id,
-            c.name
+            c.name,
+            c.slug
         FROM categories c
-        WHERE c.sub_category_id = $1
-        ORDER BY c.id`, subCategoryID)
+        WHERE c.sub_category_id = $1 AND c.deleted_at IS NULL
+        ORDER BY c.position, c.id`, subCategoryID)
 
 	if err != nil {
 		utils.SendError(w, http.StatusInternalServerError, "Error fetching categories")
@@ -705,22 +562,78 @@ func GetSubCategoryCategories(w http.ResponseWriter, r *http.Request) {
 	}
 	defer rows.Close()
 
-	var categories []struct {
-		ID   int    `json:"id"`
-		Name string `json:"name"`
-	}
+	categories := []models.CategoryRef{}
 
 	for rows.Next() {
-		var category struct {
-			ID   int    `json:"id"`
-			Name string `json:"name"`
-		}
-		if err := rows.Scan(&category.ID, &category.Name); err != nil {
+		var category models.CategoryRef
+		if err := rows.Scan(&category.ID, &category.Name, &category.Slug); err != nil {
 			utils.SendError(w, http.StatusInternalServerError, "Error scanning category")
 			return
 		}
 		categories = append(categories, category)
 	}
 
-	utils.SendSuccess(w, "Categories fetched successfully", categories)
+	utils.SendCacheable(w, r, catalogMaxAge, "Categories fetched successfully", categories)
+}
+
+// Alt kategoriyi ve kategorilerini ana kategori düğümünün altına ekler
+func createSubCategoryNodes(w http.ResponseWriter, tx pgx.Tx, mainNodeID int, sub models.SubCategoryGroup) bool {
+	subNode, err := services.CreateCategoryNode(context.Background(), tx, &mainNodeID, sub.SubCategory)
+	if err != nil {
+		sendCategoryNodeError(w, err, "Error creating sub category")
+		return false
+	}
+
+	for _, catName := range sub.Categories {
+		if _, err := services.CreateCategoryNode(context.Background(), tx, &subNode.ID, catName); err != nil {
+			sendCategoryNodeError(w, err, "Error creating category")
+			return false
+		}
+	}
+	return true
+}
+
+// Eski seviyedeki kaydın ağaç düğümünü bulur; bulunamazsa 404 gönderir
+func legacyCategoryNode(w http.ResponseWriter, q services.DBTX, level, id, notFound string) (int, bool) {
+	legacyID, err := strconv.Atoi(id)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid category id")
+		return 0, false
+	}
+
+	nodeID, err := services.CategoryNodeForLegacy(context.Background(), q, level, legacyID)
+	if errors.Is(err, services.ErrCategoryNodeNotFound) {
+		utils.SendError(w, http.StatusNotFound, notFound)
+		return 0, false
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error fetching category node")
+		return 0, false
+	}
+	return nodeID, true
+}
+
+// Kaydı ağaçtaki düğümü üzerinden yeniden adlandırır ve yeni slug'ı döndürür.
+// Ad değiştiyse slug yenilenir, eski slug yönlendirme olarak kalır.
+func renameLegacyCategory(w http.ResponseWriter, tx pgx.Tx, level, id, name, notFound string) (string, bool) {
+	nodeID, ok := legacyCategoryNode(w, tx, level, id, notFound)
+	if !ok {
+		return "", false
+	}
+
+	if err := services.RenameCategoryNode(context.Background(), tx, nodeID, name); err != nil {
+		if errors.Is(err, services.ErrCategoryNodeNotFound) {
+			utils.SendError(w, http.StatusNotFound, notFound)
+			return "", false
+		}
+		sendCategoryNodeError(w, err, "Error updating category")
+		return "", false
+	}
+
+	slug, err := services.LegacyCategorySlug(context.Background(), tx, nodeID)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error generating slug")
+		return "", false
+	}
+	return slug, true
 }
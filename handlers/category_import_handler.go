@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	taxonomyImportUpsert = "upsert" // var olanları isimle eşle, eksikleri ekle
+	taxonomyImportStrict = "strict" // herhangi bir düğüm zaten varsa iptal et
+)
+
+// Taksonomi Dosyası İçe Aktarma
+func ImportCategories(w http.ResponseWriter, r *http.Request) {
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = taxonomyImportUpsert
+	}
+	if mode != taxonomyImportUpsert && mode != taxonomyImportStrict {
+		utils.SendError(w, http.StatusBadRequest, "Mode must be upsert or strict")
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	// Dosya multipart "file" alanında veya doğrudan gövdede gönderilebilir
+	var body io.Reader = http.MaxBytesReader(w, r.Body, 5<<20) // 5 MB limit
+	formatParam := r.URL.Query().Get("format")
+	contentType := r.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "multipart/form-data") {
+		if err := r.ParseMultipartForm(5 << 20); err != nil {
+			utils.SendError(w, http.StatusBadRequest, "Invalid form data")
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			utils.SendError(w, http.StatusBadRequest, "Taxonomy file is required")
+			return
+		}
+		defer file.Close()
+		body = file
+		if formatParam == "" {
+			formatParam = strings.TrimPrefix(filepath.Ext(header.Filename), ".")
+		}
+	}
+
+	format, err := services.TaxonomyFormat(formatParam, contentType)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	taxonomy, err := services.DecodeTaxonomy(body, format)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	// Dry-run'da değişiklikler aynı transaction içinde uygulanıp geri alınır
+	defer tx.Rollback(context.Background())
+
+	result := models.TaxonomyImportResult{DryRun: dryRun, Mode: mode, Changes: []models.TaxonomyChange{}}
+	inFile := make(map[string]bool)
+
+	record := func(created bool, level, path string) {
+		inFile[level+"|"+path] = true
+		if created {
+			result.Created++
+			result.Changes = append(result.Changes, models.TaxonomyChange{Action: "create", Level: level, Path: path})
+		} else {
+			result.Existing++
+			result.Changes = append(result.Changes, models.TaxonomyChange{Action: "exists", Level: level, Path: path})
+		}
+	}
+
+	for _, main := range taxonomy {
+		mainID, created, err := upsertCategoryNode(tx, nil, main.MainCategory)
+		if err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Error importing main category: "+main.MainCategory)
+			return
+		}
+		record(created, "main", main.MainCategory)
+
+		for _, sub := range main.SubCategories {
+			subPath := main.MainCategory + " > " + sub.SubCategory
+			subID, created, err := upsertCategoryNode(tx, &mainID, sub.SubCategory)
+			if err != nil {
+				utils.SendError(w, http.StatusInternalServerError, "Error importing sub category: "+subPath)
+				return
+			}
+			record(created, "sub", subPath)
+
+			for _, category := range sub.Categories {
+				categoryPath := subPath + " > " + category
+				_, created, err := upsertCategoryNode(tx, &subID, category)
+				if err != nil {
+					utils.SendError(w, http.StatusInternalServerError, "Error importing category: "+categoryPath)
+					return
+				}
+				record(created, "category", categoryPath)
+			}
+		}
+	}
+
+	if mode == taxonomyImportStrict && result.Existing > 0 {
+		utils.SendResponse(w, http.StatusConflict, false, "", result,
+			fmt.Sprintf("%d item(s) already exist, nothing was imported", result.Existing))
+		return
+	}
+
+	// Veritabanında olup dosyada bulunmayanları raporla (silinmez)
+	current, err := loadTaxonomy(tx)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error comparing taxonomy: "+err.Error())
+		return
+	}
+	for _, main := range current {
+		if !inFile["main|"+main.MainCategory] {
+			result.Changes = append(result.Changes, models.TaxonomyChange{Action: "missing_in_file", Level: "main", Path: main.MainCategory})
+		}
+		for _, sub := range main.SubCategories {
+			subPath := main.MainCategory + " > " + sub.SubCategory
+			if !inFile["sub|"+subPath] {
+				result.Changes = append(result.Changes, models.TaxonomyChange{Action: "missing_in_file", Level: "sub", Path: subPath})
+			}
+			for _, category := range sub.Categories {
+				if categoryPath := subPath + " > " + category; !inFile["category|"+categoryPath] {
+					result.Changes = append(result.Changes, models.TaxonomyChange{Action: "missing_in_file", Level: "category", Path: categoryPath})
+				}
+			}
+		}
+	}
+
+	if dryRun {
+		utils.SendSuccess(w, "Taxonomy import preview generated", result)
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
+	utils.SendSuccess(w, "Taxonomy imported successfully", result)
+}
+
+// Taksonomiyi Dışa Aktarma
+func ExportCategories(w http.ResponseWriter, r *http.Request) {
+	format, err := services.TaxonomyFormat(r.URL.Query().Get("format"), "")
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	taxonomy, err := loadTaxonomy(db.GetPool())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error fetching categories: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", services.TaxonomyContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="taxonomy.%s"`, format))
+	if err := services.EncodeTaxonomy(w, format, taxonomy); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error encoding taxonomy: "+err.Error())
+		return
+	}
+}
+
+// Mevcut hiyerarşiyi görüntüleme sırasına göre içe aktarma formatında yükler
+func loadTaxonomy(q services.DBTX) ([]models.CategoryRequest, error) {
+	rows, err := q.Query(context.Background(), `
+        SELECT mc.name, sc.name, c.name
+        FROM main_categories mc
+        LEFT JOIN main_category_sub_category mcsc ON mc.id = mcsc.main_category_id
+        LEFT JOIN sub_categories sc ON mcsc.sub_category_id = sc.id AND sc.deleted_at IS NULL
+        LEFT JOIN categories c ON sc.id = c.sub_category_id AND c.deleted_at IS NULL
+        WHERE mc.deleted_at IS NULL
+        ORDER BY mc.position, mc.id, mcsc.position, sc.id, c.position, c.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	taxonomy := []models.CategoryRequest{}
+	for rows.Next() {
+		var mainName string
+		var subName, categoryName *string
+		if err := rows.Scan(&mainName, &subName, &categoryName); err != nil {
+			return nil, err
+		}
+
+		if n := len(taxonomy); n == 0 || taxonomy[n-1].MainCategory != mainName {
+			taxonomy = append(taxonomy, models.CategoryRequest{MainCategory: mainName, SubCategories: []models.SubCategoryGroup{}})
+		}
+		main := &taxonomy[len(taxonomy)-1]
+		if subName == nil {
+			continue
+		}
+
+		if n := len(main.SubCategories); n == 0 || main.SubCategories[n-1].SubCategory != *subName {
+			main.SubCategories = append(main.SubCategories, models.SubCategoryGroup{SubCategory: *subName, Categories: []string{}})
+		}
+		if categoryName != nil {
+			sub := &main.SubCategories[len(main.SubCategories)-1]
+			sub.Categories = append(sub.Categories, *categoryName)
+		}
+	}
+	return taxonomy, rows.Err()
+}
+
+// Ebeveyn altında aynı isimli canlı düğüm varsa onu döndürür, yoksa ağaca ekler
+func upsertCategoryNode(tx pgx.Tx, parentID *int, name string) (int, bool, error) {
+	var id int
+	err := tx.QueryRow(context.Background(),
+		"SELECT id FROM category_tree WHERE parent_id IS NOT DISTINCT FROM $1 AND name = $2 AND deleted_at IS NULL",
+		parentID, name).Scan(&id)
+	if err == nil {
+		return id, false, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, err
+	}
+
+	node, err := services.CreateCategoryNode(context.Background(), tx, parentID, name)
+	if err != nil {
+		return 0, false, err
+	}
+	return node.ID, true, nil
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"osymapp/db"
+	"osymapp/services"
+	"osymapp/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Kategoriyi Başka Bir Alt Kategoriye Taşıma
+func MoveCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID := chi.URLParam(r, "categoryId")
+	subCategoryID := chi.URLParam(r, "subId")
+
+	var request struct {
+		TargetSubCategoryID int `json:"target_sub_category_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if request.TargetSubCategoryID == 0 {
+		utils.SendError(w, http.StatusBadRequest, "target_sub_category_id is required")
+		return
+	}
+
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	// Kategorinin belirtilen alt kategoriye ait olduğunu kontrol et
+	var exists bool
+	err = tx.QueryRow(context.Background(),
+		"SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1 AND sub_category_id = $2 AND deleted_at IS NULL)",
+		categoryID, subCategoryID).Scan(&exists)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error checking category")
+		return
+	}
+	if !exists {
+		utils.SendError(w, http.StatusNotFound, "Category not found in specified sub category")
+		return
+	}
+
+	nodeID, ok := legacyCategoryNode(w, tx, "category", categoryID, "Category not found in specified sub category")
+	if !ok {
+		return
+	}
+	targetNodeID, ok := legacyCategoryNode(w, tx, "sub", strconv.Itoa(request.TargetSubCategoryID), "Target sub category not found")
+	if !ok {
+		return
+	}
+
+	// Kategori hedef alt kategorinin sonuna taşınır; soru ilişkileri korunur.
+	// Eski adres yeni konuma yönlendirilir, slug hedefteki kardeşlere göre yeniden üretilir.
+	if err := services.MoveCategoryNode(context.Background(), tx, nodeID, &targetNodeID); err != nil {
+		if errors.Is(err, services.ErrCategoryParentNotFound) {
+			utils.SendError(w, http.StatusNotFound, "Target sub category not found")
+			return
+		}
+		sendCategoryNodeError(w, err, "Error moving category")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
+	utils.SendSuccess(w, "Category moved successfully", request)
+}
+
+// İki Kategoriyi Birleştirme
+func MergeCategories(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		SourceCategoryID int `json:"source_category_id"`
+		TargetCategoryID int `json:"target_category_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if request.SourceCategoryID == 0 || request.TargetCategoryID == 0 {
+		utils.SendError(w, http.StatusBadRequest, "source_category_id and target_category_id are required")
+		return
+	}
+
+	if request.SourceCategoryID == request.TargetCategoryID {
+		utils.SendError(w, http.StatusBadRequest, "Source and target categories must be different")
+		return
+	}
+
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	sourceNodeID, err := services.CategoryNodeForLegacy(context.Background(), tx, "category", request.SourceCategoryID)
+	var targetNodeID int
+	if err == nil {
+		targetNodeID, err = services.CategoryNodeForLegacy(context.Background(), tx, "category", request.TargetCategoryID)
+	}
+	if errors.Is(err, services.ErrCategoryNodeNotFound) {
+		utils.SendError(w, http.StatusNotFound, "Source or target category not found")
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error checking categories")
+		return
+	}
+
+	// Kaynaktaki sorular hedefe bağlanır, kaynak geri alınabilir şekilde silinir
+	movedQuestions, err := services.MergeCategoryNodes(context.Background(), tx, sourceNodeID, targetNodeID, currentUserID(r))
+	if errors.Is(err, services.ErrCategoryNodeNotFound) {
+		utils.SendError(w, http.StatusNotFound, "Source or target category not found")
+		return
+	}
+	if err != nil {
+		sendCategoryNodeError(w, err, "Error merging categories")
+		return
+	}
+
+	// Kaynağın adresleri (eski yönlendirmeleri dahil) hedefe yönlendirilir
+	_, err = tx.Exec(context.Background(),
+		`INSERT INTO category_slug_redirects (level, parent_id, slug, node_id)
+         SELECT 'category', sub_category_id, slug, $2 FROM categories WHERE id = $1 AND slug IS NOT NULL`,
+		request.SourceCategoryID, request.TargetCategoryID)
+	if err == nil {
+		_, err = tx.Exec(context.Background(),
+			"UPDATE category_slug_redirects SET node_id = $2 WHERE level = 'category' AND node_id = $1",
+			request.SourceCategoryID, request.TargetCategoryID)
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error recording slug redirect")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
+	utils.SendSuccess(w, "Categories merged successfully", map[string]interface{}{
+		"source_category_id": request.SourceCategoryID,
+		"target_category_id": request.TargetCategoryID,
+		"moved_questions":    movedQuestions,
+	})
+}
+
+// Ana Kategorilerin Sırasını Belirleme
+func ReorderMainCategories(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		MainCategoryIDs []int `json:"main_category_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	reorderSiblings(w, "main", nil, request.MainCategoryIDs)
+}
+
+// Ana Kategori Altındaki Alt Kategorilerin Sırasını Belirleme
+func ReorderSubCategories(w http.ResponseWriter, r *http.Request) {
+	mainCategoryID := chi.URLParam(r, "mainId")
+
+	var request struct {
+		SubCategoryIDs []int `json:"sub_category_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	reorderSiblings(w, "sub", &legacyCategoryParent{"main", mainCategoryID, "Main category not found"}, request.SubCategoryIDs)
+}
+
+// Alt Kategori Altındaki Kategorilerin Sırasını Belirleme
+func ReorderCategories(w http.ResponseWriter, r *http.Request) {
+	subCategoryID := chi.URLParam(r, "subId")
+
+	var request struct {
+		CategoryIDs []int `json:"category_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	reorderSiblings(w, "category", &legacyCategoryParent{"sub", subCategoryID, "Sub category not found"}, request.CategoryIDs)
+}
+
+type legacyCategoryParent struct {
+	level    string
+	id       string
+	notFound string
+}
+
+// Kardeşlerin sırasını verilen ID listesine göre kategori ağacında günceller.
+// Liste, ebeveyn altındaki tüm kardeşleri tam olarak bir kez içermelidir.
+func reorderSiblings(w http.ResponseWriter, level string, parent *legacyCategoryParent, ids []int) {
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	var parentNodeID *int
+	if parent != nil {
+		nodeID, ok := legacyCategoryNode(w, tx, parent.level, parent.id, parent.notFound)
+		if !ok {
+			return
+		}
+		parentNodeID = &nodeID
+	}
+
+	nodeIDs, err := services.CategoryNodesForLegacy(context.Background(), tx, level, ids)
+	if errors.Is(err, services.ErrCategoryNodeNotFound) {
+		utils.SendError(w, http.StatusBadRequest, "Order must list every sibling exactly once")
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error fetching siblings")
+		return
+	}
+
+	err = services.ReorderCategoryNodes(context.Background(), tx, parentNodeID, nodeIDs)
+	if errors.Is(err, services.ErrCategoryNodeNotFound) {
+		utils.SendError(w, http.StatusNotFound, "No items found to reorder")
+		return
+	}
+	if err != nil {
+		sendCategoryNodeError(w, err, "Error updating order")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
+	utils.SendSuccess(w, "Order updated successfully", ids)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Slug Yolu ile Kategori Getir (/categories/tyt/matematik/problemler)
+func GetCategoryBySlugPath(w http.ResponseWriter, r *http.Request) {
+	node, ok := resolveCategorySlugNode(w, r, "/categories/")
+	if !ok {
+		return
+	}
+
+	utils.SendSuccess(w, "Category fetched successfully", node)
+}
+
+// URL'deki slug yolunu düğüme çözer. Eski veya taşınmış adresler basePath altındaki
+// güncel adrese yönlendirilir; bu durumda ve hatalarda yanıt yazılmış olur ve false döner.
+func resolveCategorySlugNode(w http.ResponseWriter, r *http.Request, basePath string) (*models.CategorySlugNode, bool) {
+	segments := strings.Split(strings.Trim(chi.URLParam(r, "*"), "/"), "/")
+
+	path, redirected, err := services.ResolveCategorySlugPath(context.Background(), segments)
+	if errors.Is(err, services.ErrCategorySlugNotFound) {
+		utils.SendError(w, http.StatusNotFound, "Category not found")
+		return nil, false
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error resolving category path: "+err.Error())
+		return nil, false
+	}
+
+	if redirected {
+		slugs := make([]string, len(path))
+		for i, ref := range path {
+			slugs[i] = ref.Slug
+		}
+		target := basePath + strings.Join(slugs, "/")
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+		return nil, false
+	}
+
+	current := path[len(path)-1]
+	node := &models.CategorySlugNode{
+		Level:    []string{"main", "sub", "category"}[len(path)-1],
+		ID:       current.ID,
+		Name:     current.Name,
+		Slug:     current.Slug,
+		Path:     path,
+		Children: []models.CategoryRef{},
+	}
+
+	var childQuery string
+	switch node.Level {
+	case "main":
+		childQuery = `SELECT sc.id, sc.name, sc.slug
+                      FROM main_category_sub_category mcsc
+                      JOIN sub_categories sc ON sc.id = mcsc.sub_category_id AND sc.deleted_at IS NULL
+                      WHERE mcsc.main_category_id = $1
+                      ORDER BY mcsc.position, sc.id`
+	case "sub":
+		childQuery = `SELECT id, name, slug
+                      FROM categories
+                      WHERE sub_category_id = $1 AND deleted_at IS NULL
+                      ORDER BY position, id`
+	}
+
+	if childQuery != "" {
+		rows, err := db.GetPool().Query(context.Background(), childQuery, node.ID)
+		if err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Error fetching child categories")
+			return nil, false
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var child models.CategoryRef
+			if err := rows.Scan(&child.ID, &child.Name, &child.Slug); err != nil {
+				utils.SendError(w, http.StatusInternalServerError, "Error scanning child category")
+				return nil, false
+			}
+			node.Children = append(node.Children, child)
+		}
+	}
+
+	return node, true
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/utils"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultCategoryStatsTTL = 5 * time.Minute
+
+var categoryStatsCache = struct {
+	sync.RWMutex
+	data      []*models.CategoryStatsNode
+	expiresAt time.Time
+}{}
+
+// Kategori İstatistiklerini Getir
+func GetCategoryStats(w http.ResponseWriter, r *http.Request) {
+	refresh := r.URL.Query().Get("refresh") == "true"
+
+	if !refresh {
+		categoryStatsCache.RLock()
+		data, expiresAt := categoryStatsCache.data, categoryStatsCache.expiresAt
+		categoryStatsCache.RUnlock()
+
+		if data != nil && time.Now().Before(expiresAt) {
+			utils.SendSuccess(w, "Category stats fetched successfully", data)
+			return
+		}
+	}
+
+	data, err := computeCategoryStats(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error computing category stats: "+err.Error())
+		return
+	}
+
+	if ttl := categoryStatsTTL(); ttl > 0 {
+		categoryStatsCache.Lock()
+		categoryStatsCache.data = data
+		categoryStatsCache.expiresAt = time.Now().Add(ttl)
+		categoryStatsCache.Unlock()
+	}
+
+	utils.SendSuccess(w, "Category stats fetched successfully", data)
+}
+
+// CATEGORY_STATS_CACHE_TTL "0" ise önbellek kapalıdır
+func categoryStatsTTL() time.Duration {
+	v := os.Getenv("CATEGORY_STATS_CACHE_TTL")
+	if v == "" {
+		return defaultCategoryStatsTTL
+	}
+	ttl, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("Uyarı: CATEGORY_STATS_CACHE_TTL geçersiz (%s), varsayılan kullanılıyor", v)
+		return defaultCategoryStatsTTL
+	}
+	return ttl
+}
+
+func computeCategoryStats(ctx context.Context) ([]*models.CategoryStatsNode, error) {
+	pool := db.GetPool()
+
+	// Her seviye için düğüm-soru çiftleri tekilleştirilir; böylece aynı alt kategoride
+	// birden fazla kategoriye bağlı bir soru üst seviyelerde bir kez sayılır
+	rows, err := pool.Query(ctx, `
+        WITH NodeQuestionsAll AS (
+            SELECT 'main' AS level, mcsc.main_category_id AS node_id, qc.question_id
+            FROM main_category_sub_category mcsc
+            JOIN sub_categories sc ON sc.id = mcsc.sub_category_id AND sc.deleted_at IS NULL
+            JOIN categories c ON c.sub_category_id = sc.id AND c.deleted_at IS NULL
+            JOIN question_categories qc ON qc.category_id = c.id
+            UNION
+            SELECT 'sub', c.sub_category_id, qc.question_id
+            FROM categories c
+            JOIN sub_categories sc ON sc.id = c.sub_category_id AND sc.deleted_at IS NULL
+            JOIN question_categories qc ON qc.category_id = c.id
+            WHERE c.deleted_at IS NULL
+            UNION
+            SELECT 'category', qc.category_id, qc.question_id
+            FROM question_categories qc
+            JOIN categories c ON c.id = qc.category_id AND c.deleted_at IS NULL
+        ),
+        NodeQuestions AS (
+            SELECT nq.level, nq.node_id, nq.question_id
+            FROM NodeQuestionsAll nq
+            JOIN questions q ON q.id = nq.question_id AND q.deleted_at IS NULL
+        ),
+        AttemptTotals AS (
+            SELECT question_id,
+                   COUNT(*) AS attempts,
+                   COUNT(*) FILTER (WHERE is_correct) AS correct
+            FROM question_attempts
+            GROUP BY question_id
+        ),
+        Totals AS (
+            SELECT nq.level, nq.node_id,
+                   COUNT(*) AS question_count,
+                   COALESCE(SUM(a.attempts), 0) AS attempts,
+                   COALESCE(SUM(a.correct), 0) AS correct
+            FROM NodeQuestions nq
+            LEFT JOIN AttemptTotals a ON a.question_id = nq.question_id
+            GROUP BY nq.level, nq.node_id
+        ),
+        ByDifficulty AS (
+            SELECT nq.level, nq.node_id, COALESCE(q.difficulty_level, '') AS difficulty, COUNT(*) AS n
+            FROM NodeQuestions nq
+            JOIN questions q ON q.id = nq.question_id
+            GROUP BY nq.level, nq.node_id, COALESCE(q.difficulty_level, '')
+        ),
+        ByPublisher AS (
+            SELECT nq.level, nq.node_id, q.publisher_id, COALESCE(p.name, '') AS name, COUNT(*) AS n
+            FROM NodeQuestions nq
+            JOIN questions q ON q.id = nq.question_id
+            LEFT JOIN publishers p ON p.id = q.publisher_id
+            WHERE q.publisher_id IS NOT NULL
+            GROUP BY nq.level, nq.node_id, q.publisher_id, p.name
+        )
+        SELECT t.level, t.node_id, t.question_count, t.attempts, t.correct,
+               COALESCE((SELECT json_object_agg(d.difficulty, d.n)
+                         FROM ByDifficulty d
+                         WHERE d.level = t.level AND d.node_id = t.node_id), '{}'::json),
+               COALESCE((SELECT json_agg(json_build_object(
+                             'publisher_id', p.publisher_id, 'name', p.name, 'count', p.n
+                         ) ORDER BY p.n DESC, p.publisher_id)
+                         FROM ByPublisher p
+                         WHERE p.level = t.level AND p.node_id = t.node_id), '[]'::json)
+        FROM Totals t`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[string]models.CategoryStats)
+	for rows.Next() {
+		var level string
+		var nodeID int
+		var s models.CategoryStats
+		var difficultyJSON, publisherJSON []byte
+		if err := rows.Scan(&level, &nodeID, &s.QuestionCount, &s.Attempts, &s.CorrectAttempts,
+			&difficultyJSON, &publisherJSON); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(difficultyJSON, &s.ByDifficulty); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(publisherJSON, &s.ByPublisher); err != nil {
+			return nil, err
+		}
+		if s.Attempts > 0 {
+			rate := float64(s.CorrectAttempts) / float64(s.Attempts)
+			s.SuccessRate = &rate
+		}
+		stats[statsKey(level, nodeID)] = s
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// İstatistikleri hiyerarşiye yerleştir (sorusu olmayan düğümler de listelenir)
+	rows, err = pool.Query(ctx, `
+        SELECT mc.id, mc.name, sc.id, sc.name, c.id, c.name
+        FROM main_categories mc
+        LEFT JOIN main_category_sub_category mcsc ON mc.id = mcsc.main_category_id
+        LEFT JOIN sub_categories sc ON mcsc.sub_category_id = sc.id AND sc.deleted_at IS NULL
+        LEFT JOIN categories c ON sc.id = c.sub_category_id AND c.deleted_at IS NULL
+        WHERE mc.deleted_at IS NULL
+        ORDER BY mc.position, mc.id, mcsc.position, sc.id, c.position, c.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tree := []*models.CategoryStatsNode{}
+	for rows.Next() {
+		var mainID int
+		var mainName string
+		var subID, categoryID *int
+		var subName, categoryName *string
+		if err := rows.Scan(&mainID, &mainName, &subID, &subName, &categoryID, &categoryName); err != nil {
+			return nil, err
+		}
+
+		if n := len(tree); n == 0 || tree[n-1].ID != mainID {
+			tree = append(tree, &models.CategoryStatsNode{ID: mainID, Name: mainName, Stats: nodeStats(stats, "main", mainID)})
+		}
+		main := tree[len(tree)-1]
+		if subID == nil {
+			continue
+		}
+
+		if n := len(main.SubCategories); n == 0 || main.SubCategories[n-1].ID != *subID {
+			main.SubCategories = append(main.SubCategories, &models.CategoryStatsNode{ID: *subID, Name: *subName, Stats: nodeStats(stats, "sub", *subID)})
+		}
+		if categoryID != nil {
+			sub := main.SubCategories[len(main.SubCategories)-1]
+			sub.Categories = append(sub.Categories, &models.CategoryStatsNode{ID: *categoryID, Name: *categoryName, Stats: nodeStats(stats, "category", *categoryID)})
+		}
+	}
+	return tree, rows.Err()
+}
+
+func statsKey(level string, id int) string {
+	return level + ":" + strconv.Itoa(id)
+}
+
+func nodeStats(stats map[string]models.CategoryStats, level string, id int) models.CategoryStats {
+	if s, ok := stats[statsKey(level, id)]; ok {
+		return s
+	}
+	return models.CategoryStats{ByDifficulty: map[string]int{}, ByPublisher: []models.PublisherCount{}}
+}
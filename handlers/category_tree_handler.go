@@ -0,0 +1,404 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Kategori Ağacına Düğüm Ekleme
+func CreateCategoryNode(w http.ResponseWriter, r *http.Request) {
+	var req models.CategoryNodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Name == "" {
+		utils.SendError(w, http.StatusBadRequest, "Name is required")
+		return
+	}
+
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	node, err := services.CreateCategoryNode(context.Background(), tx, req.ParentID, req.Name)
+	if err != nil {
+		sendCategoryNodeError(w, err, "Error creating category node")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
+	utils.SendSuccess(w, "Category node created successfully", node)
+}
+
+// Kategori Ağacının Tamamını Getir
+func GetCategoryTree(w http.ResponseWriter, r *http.Request) {
+	pool := db.GetPool()
+	rows, err := pool.Query(context.Background(), `
+        WITH RECURSIVE Tree AS (
+            SELECT id, parent_id, name, position, 0 AS depth
+            FROM category_tree
+            WHERE parent_id IS NULL AND deleted_at IS NULL
+            UNION ALL
+            SELECT c.id, c.parent_id, c.name, c.position, t.depth + 1
+            FROM category_tree c
+            JOIN Tree t ON c.parent_id = t.id
+            WHERE c.deleted_at IS NULL
+        )
+        SELECT id, parent_id, name, position, depth FROM Tree ORDER BY depth, position, id`)
+
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error fetching category tree: "+err.Error())
+		return
+	}
+
+	nodes, err := scanCategoryNodes(rows)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error scanning category tree: "+err.Error())
+		return
+	}
+
+	utils.SendSuccess(w, "Category tree fetched successfully", buildCategoryTree(nodes))
+}
+
+// Düğümü Alt Ağacı ve Üst Düğümleriyle Getir
+func GetCategoryNode(w http.ResponseWriter, r *http.Request) {
+	nodeID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid node id")
+		return
+	}
+
+	pool := db.GetPool()
+	rows, err := pool.Query(context.Background(), `
+        WITH RECURSIVE Subtree AS (
+            SELECT id, parent_id, name, position, 0 AS depth
+            FROM category_tree
+            WHERE id = $1 AND deleted_at IS NULL
+            UNION ALL
+            SELECT c.id, c.parent_id, c.name, c.position, s.depth + 1
+            FROM category_tree c
+            JOIN Subtree s ON c.parent_id = s.id
+            WHERE c.deleted_at IS NULL
+        )
+        SELECT id, parent_id, name, position, depth FROM Subtree ORDER BY depth, position, id`, nodeID)
+
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error fetching category node: "+err.Error())
+		return
+	}
+
+	nodes, err := scanCategoryNodes(rows)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error scanning category node: "+err.Error())
+		return
+	}
+
+	if len(nodes) == 0 {
+		utils.SendError(w, http.StatusNotFound, "Category node not found")
+		return
+	}
+
+	// Kökten düğüme kadar olan yol (breadcrumb)
+	rows, err = pool.Query(context.Background(), `
+        WITH RECURSIVE Ancestors AS (
+            SELECT id, parent_id, name, position, 0 AS depth
+            FROM category_tree
+            WHERE id = $1
+            UNION ALL
+            SELECT c.id, c.parent_id, c.name, c.position, a.depth + 1
+            FROM category_tree c
+            JOIN Ancestors a ON c.id = a.parent_id
+        )
+        SELECT id, parent_id, name, position, depth FROM Ancestors ORDER BY depth DESC`, nodeID)
+
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error fetching category path: "+err.Error())
+		return
+	}
+
+	path, err := scanCategoryNodes(rows)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error scanning category path: "+err.Error())
+		return
+	}
+
+	// Derinlikleri kökten itibaren say
+	for i := range path {
+		path[i].Depth = i
+	}
+	for _, n := range nodes {
+		n.Depth += len(path) - 1
+	}
+
+	utils.SendSuccess(w, "Category node fetched successfully", map[string]interface{}{
+		"node": buildCategoryTree(nodes)[0],
+		"path": path,
+	})
+}
+
+// Düğüm Adını Güncelleme
+func UpdateCategoryNode(w http.ResponseWriter, r *http.Request) {
+	nodeID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid node id")
+		return
+	}
+
+	var request struct {
+		Name string `json:"name"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if request.Name == "" {
+		utils.SendError(w, http.StatusBadRequest, "Name is required")
+		return
+	}
+
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	if err := services.RenameCategoryNode(context.Background(), tx, nodeID, request.Name); err != nil {
+		sendCategoryNodeError(w, err, "Error updating category node")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
+	utils.SendSuccess(w, "Category node updated successfully", request)
+}
+
+// Düğümü Başka Bir Düğümün Altına Taşıma
+func MoveCategoryNode(w http.ResponseWriter, r *http.Request) {
+	nodeID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid node id")
+		return
+	}
+
+	var req models.CategoryNodeMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	if err := services.MoveCategoryNode(context.Background(), tx, nodeID, req.ParentID); err != nil {
+		sendCategoryNodeError(w, err, "Error moving category node")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
+	utils.SendSuccess(w, "Category node moved successfully", map[string]interface{}{
+		"id":        nodeID,
+		"parent_id": req.ParentID,
+	})
+}
+
+// Düğüm Silme (alt ağaçla birlikte geri alınabilir şekilde silinir)
+func DeleteCategoryNode(w http.ResponseWriter, r *http.Request) {
+	nodeID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid node id")
+		return
+	}
+
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	deletedAt, err := services.DeleteCategoryNode(context.Background(), tx, nodeID, currentUserID(r))
+	if err != nil {
+		sendCategoryNodeError(w, err, "Error deleting category node")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
+	utils.SendSuccess(w, "Category node and its subtree deleted successfully", map[string]interface{}{
+		"id":               nodeID,
+		"deleted_at":       deletedAt,
+		"restorable_until": deletedAt.Add(services.CategoryRetention),
+	})
+}
+
+// Silinen Düğümü Geri Yükleme
+func RestoreCategoryNode(w http.ResponseWriter, r *http.Request) {
+	nodeID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid node id")
+		return
+	}
+
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	if err := services.RestoreCategoryNode(context.Background(), tx, nodeID); err != nil {
+		sendCategoryNodeError(w, err, "Error restoring category node")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
+	utils.SendSuccess(w, "Category node restored successfully", map[string]interface{}{"id": nodeID})
+}
+
+// Kardeş Düğümlerin Sırasını Belirleme
+func ReorderCategoryNodes(w http.ResponseWriter, r *http.Request) {
+	var req models.CategoryNodeOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	if err := services.ReorderCategoryNodes(context.Background(), tx, req.ParentID, req.NodeIDs); err != nil {
+		sendCategoryNodeError(w, err, "Error updating order")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
+	utils.SendSuccess(w, "Order updated successfully", req)
+}
+
+func scanCategoryNodes(rows pgx.Rows) ([]*models.CategoryNode, error) {
+	defer rows.Close()
+
+	var nodes []*models.CategoryNode
+	for rows.Next() {
+		var n models.CategoryNode
+		if err := rows.Scan(&n.ID, &n.ParentID, &n.Name, &n.Position, &n.Depth); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, &n)
+	}
+	return nodes, rows.Err()
+}
+
+// Düz listeyi iç içe ağaca çevirir; ebeveyni listede olmayan düğümler kök kabul edilir
+func buildCategoryTree(nodes []*models.CategoryNode) []*models.CategoryNode {
+	byID := make(map[int]*models.CategoryNode, len(nodes))
+	for _, n := range nodes {
+		byID[n.ID] = n
+	}
+
+	roots := []*models.CategoryNode{}
+	for _, n := range nodes {
+		if n.ParentID != nil {
+			if parent, ok := byID[*n.ParentID]; ok {
+				parent.Children = append(parent.Children, n)
+				continue
+			}
+		}
+		roots = append(roots, n)
+	}
+	return roots
+}
+
+func sendCategoryNodeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrCategoryNodeNotFound):
+		utils.SendError(w, http.StatusNotFound, "Category node not found")
+		return
+	case errors.Is(err, services.ErrCategoryParentNotFound):
+		utils.SendError(w, http.StatusBadRequest, "Parent node not found")
+		return
+	case errors.Is(err, services.ErrCategoryNodeCycle):
+		utils.SendError(w, http.StatusBadRequest, "A node cannot be moved under itself or its descendants")
+		return
+	case errors.Is(err, services.ErrCategoryNodeOrder):
+		utils.SendError(w, http.StatusBadRequest, "Order must list every sibling exactly once")
+		return
+	case errors.Is(err, services.ErrCategoryNodeNotDeleted):
+		utils.SendError(w, http.StatusConflict, "Category node is not deleted")
+		return
+	case errors.Is(err, services.ErrCategoryParentDeleted):
+		utils.SendError(w, http.StatusConflict, "Restore the parent node first")
+		return
+	case errors.Is(err, services.ErrCategoryRestoreExpired):
+		utils.SendError(w, http.StatusGone, "Restore period has expired")
+		return
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation
+			utils.SendError(w, http.StatusConflict, "A sibling with the same name already exists")
+			return
+		case "23503": // foreign_key_violation
+			utils.SendError(w, http.StatusBadRequest, "Parent node not found")
+			return
+		}
+	}
+	utils.SendError(w, http.StatusInternalServerError, message)
+}
@@ -0,0 +1,416 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/utils"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	maxCollectionNameLength = 100
+	maxCollectionsPerUser   = 100
+)
+
+// Koleksiyonlarım
+func GetMyCollections(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT c.id, c.name, c.description, c.share_token, c.created_at, c.updated_at,
+               (SELECT COUNT(*) FROM question_collection_items i
+                JOIN questions q ON q.id = i.question_id AND q.deleted_at IS NULL
+                WHERE i.collection_id = c.id)
+        FROM question_collections c
+        WHERE c.user_id = $1
+        ORDER BY c.name`, currentUserID(r))
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Koleksiyonlar alınamadı")
+		return
+	}
+	defer rows.Close()
+
+	collections := []models.QuestionCollection{}
+	for rows.Next() {
+		var c models.QuestionCollection
+		if err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.ShareToken, &c.CreatedAt, &c.UpdatedAt,
+			&c.QuestionCount); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Koleksiyon okunamadı")
+			return
+		}
+		collections = append(collections, c)
+	}
+
+	utils.SendSuccess(w, "Koleksiyonlar getirildi", collections)
+}
+
+// Koleksiyon Oluştur
+func CreateCollection(w http.ResponseWriter, r *http.Request) {
+	userID := currentUserID(r)
+	if userID == 0 {
+		utils.SendError(w, http.StatusForbidden, "Misafir kullanıcılar koleksiyon oluşturamaz")
+		return
+	}
+
+	req, ok := decodeCollectionRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var count int
+	if err := db.GetPool().QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM question_collections WHERE user_id = $1", userID).Scan(&count); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Koleksiyon oluşturulamadı")
+		return
+	}
+	if count >= maxCollectionsPerUser {
+		utils.SendError(w, http.StatusBadRequest, "Koleksiyon sayısı sınırına ulaşıldı")
+		return
+	}
+
+	var c models.QuestionCollection
+	err := db.GetPool().QueryRow(context.Background(), `
+        INSERT INTO question_collections (user_id, name, description)
+        VALUES ($1, $2, $3)
+        RETURNING id, name, description, share_token, created_at, updated_at`,
+		userID, req.Name, req.Description).Scan(
+		&c.ID, &c.Name, &c.Description, &c.ShareToken, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		sendCollectionError(w, err, "Koleksiyon oluşturulamadı")
+		return
+	}
+
+	utils.SendResponse(w, http.StatusCreated, true, "Koleksiyon oluşturuldu", c, "")
+}
+
+// Koleksiyon Detayı (sorular GET /questions?collection_id= ile filtrelenebilir)
+func GetCollection(w http.ResponseWriter, r *http.Request) {
+	var c models.QuestionCollection
+	err := db.GetPool().QueryRow(context.Background(), `
+        SELECT id, name, description, share_token, created_at, updated_at
+        FROM question_collections WHERE id = $1 AND user_id = $2`,
+		chi.URLParam(r, "id"), currentUserID(r)).Scan(
+		&c.ID, &c.Name, &c.Description, &c.ShareToken, &c.CreatedAt, &c.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, http.StatusNotFound, "Koleksiyon bulunamadı")
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Koleksiyon alınamadı")
+		return
+	}
+
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT i.question_id FROM question_collection_items i
+        JOIN questions q ON q.id = i.question_id AND q.deleted_at IS NULL
+        WHERE i.collection_id = $1
+        ORDER BY i.added_at, i.question_id`, c.ID)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Koleksiyon soruları alınamadı")
+		return
+	}
+	defer rows.Close()
+
+	c.QuestionIDs = []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Koleksiyon sorusu okunamadı")
+			return
+		}
+		c.QuestionIDs = append(c.QuestionIDs, id)
+	}
+	c.QuestionCount = len(c.QuestionIDs)
+
+	utils.SendSuccess(w, "Koleksiyon getirildi", c)
+}
+
+// Koleksiyonu Güncelle
+func UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeCollectionRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var c models.QuestionCollection
+	err := db.GetPool().QueryRow(context.Background(), `
+        UPDATE question_collections
+        SET name = $3, description = $4, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND user_id = $2
+        RETURNING id, name, description, share_token, created_at, updated_at`,
+		chi.URLParam(r, "id"), currentUserID(r), req.Name, req.Description).Scan(
+		&c.ID, &c.Name, &c.Description, &c.ShareToken, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		sendCollectionError(w, err, "Koleksiyon güncellenemedi")
+		return
+	}
+
+	utils.SendSuccess(w, "Koleksiyon güncellendi", c)
+}
+
+// Koleksiyonu Sil (sorular silinmez, yalnızca koleksiyondan çıkar)
+func DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	result, err := db.GetPool().Exec(context.Background(),
+		"DELETE FROM question_collections WHERE id = $1 AND user_id = $2",
+		chi.URLParam(r, "id"), currentUserID(r))
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Koleksiyon silinemedi")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		utils.SendError(w, http.StatusNotFound, "Koleksiyon bulunamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "Koleksiyon silindi", nil)
+}
+
+// Koleksiyona Soru Ekle
+func AddQuestionToCollection(w http.ResponseWriter, r *http.Request) {
+	questionID, ok := visibleQuestionParam(w, r, "questionId")
+	if !ok {
+		return
+	}
+
+	result, err := db.GetPool().Exec(context.Background(), `
+        WITH Owned AS (
+            UPDATE question_collections SET updated_at = CURRENT_TIMESTAMP
+            WHERE id = $1 AND user_id = $2
+            RETURNING id
+        )
+        INSERT INTO question_collection_items (collection_id, question_id)
+        SELECT id, $3 FROM Owned
+        ON CONFLICT (collection_id, question_id) DO NOTHING`,
+		chi.URLParam(r, "id"), currentUserID(r), questionID)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Soru koleksiyona eklenemedi")
+		return
+	}
+	if result.RowsAffected() == 0 && !ownsCollection(r) {
+		utils.SendError(w, http.StatusNotFound, "Koleksiyon bulunamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "Soru koleksiyona eklendi", map[string]interface{}{
+		"collection_id": chi.URLParam(r, "id"),
+		"question_id":   questionID,
+	})
+}
+
+// Koleksiyondan Soru Çıkar
+func RemoveQuestionFromCollection(w http.ResponseWriter, r *http.Request) {
+	result, err := db.GetPool().Exec(context.Background(), `
+        WITH Removed AS (
+            DELETE FROM question_collection_items i
+            USING question_collections c
+            WHERE c.id = i.collection_id AND c.id = $1 AND c.user_id = $2 AND i.question_id = $3
+            RETURNING i.collection_id
+        )
+        UPDATE question_collections SET updated_at = CURRENT_TIMESTAMP
+        WHERE id IN (SELECT collection_id FROM Removed)`,
+		chi.URLParam(r, "id"), currentUserID(r), chi.URLParam(r, "questionId"))
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Soru koleksiyondan çıkarılamadı")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		utils.SendError(w, http.StatusNotFound, "Soru koleksiyonda bulunamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "Soru koleksiyondan çıkarıldı", nil)
+}
+
+// Paylaşım Bağlantısı Oluştur (varsa mevcut bağlantı döner)
+func ShareCollection(w http.ResponseWriter, r *http.Request) {
+	token, err := newShareToken()
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Paylaşım bağlantısı oluşturulamadı")
+		return
+	}
+
+	err = db.GetPool().QueryRow(context.Background(), `
+        UPDATE question_collections SET share_token = COALESCE(share_token, $3)
+        WHERE id = $1 AND user_id = $2
+        RETURNING share_token`,
+		chi.URLParam(r, "id"), currentUserID(r), token).Scan(&token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, http.StatusNotFound, "Koleksiyon bulunamadı")
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Paylaşım bağlantısı oluşturulamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "Paylaşım bağlantısı oluşturuldu", map[string]string{
+		"share_token": token,
+		"url":         "/shared/collections/" + token,
+	})
+}
+
+// Paylaşımı Kapat (eski bağlantı geçersiz olur)
+func UnshareCollection(w http.ResponseWriter, r *http.Request) {
+	result, err := db.GetPool().Exec(context.Background(),
+		"UPDATE question_collections SET share_token = NULL WHERE id = $1 AND user_id = $2",
+		chi.URLParam(r, "id"), currentUserID(r))
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Paylaşım kapatılamadı")
+		return
+	}
+	if result.RowsAffected() == 0 {
+		utils.SendError(w, http.StatusNotFound, "Koleksiyon bulunamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "Paylaşım kapatıldı", nil)
+}
+
+// Paylaşılan Koleksiyon (herkese açık, salt okunur; yalnızca yayındaki sorular)
+func GetSharedCollection(w http.ResponseWriter, r *http.Request) {
+	var c models.SharedQuestionCollection
+	var collectionID int
+	err := db.GetPool().QueryRow(context.Background(), `
+        SELECT c.id, c.name, c.description, u.username, c.updated_at
+        FROM question_collections c
+        JOIN users u ON u.id = c.user_id
+        WHERE c.share_token = $1`, chi.URLParam(r, "token")).Scan(
+		&collectionID, &c.Name, &c.Description, &c.Owner, &c.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, http.StatusNotFound, "Koleksiyon bulunamadı")
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Koleksiyon alınamadı")
+		return
+	}
+
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT i.question_id FROM question_collection_items i
+        JOIN questions q ON q.id = i.question_id AND q.deleted_at IS NULL AND q.status = $2
+        WHERE i.collection_id = $1
+        ORDER BY i.added_at, i.question_id`, collectionID, models.QuestionStatusPublished)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Koleksiyon soruları alınamadı")
+		return
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			utils.SendError(w, http.StatusInternalServerError, "Koleksiyon sorusu okunamadı")
+			return
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	c.Questions, err = loadPracticeQuestions(ids, false, models.QuestionStatusPublished)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Koleksiyon soruları alınamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "Koleksiyon getirildi", c)
+}
+
+func decodeCollectionRequest(w http.ResponseWriter, r *http.Request) (models.QuestionCollectionRequest, bool) {
+	var req models.QuestionCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz istek gövdesi")
+		return req, false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.Description = strings.TrimSpace(req.Description)
+	if req.Name == "" {
+		utils.SendError(w, http.StatusBadRequest, "Koleksiyon adı boş olamaz")
+		return req, false
+	}
+	if utf8.RuneCountInString(req.Name) > maxCollectionNameLength {
+		utils.SendError(w, http.StatusBadRequest, "Koleksiyon adı en fazla 100 karakter olabilir")
+		return req, false
+	}
+	return req, true
+}
+
+func sendCollectionError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, http.StatusNotFound, "Koleksiyon bulunamadı")
+		return
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		utils.SendError(w, http.StatusConflict, "Bu isimde bir koleksiyonunuz zaten var")
+		return
+	}
+	utils.SendError(w, http.StatusInternalServerError, message)
+}
+
+func ownsCollection(r *http.Request) bool {
+	var exists bool
+	err := db.GetPool().QueryRow(context.Background(),
+		"SELECT EXISTS(SELECT 1 FROM question_collections WHERE id = $1 AND user_id = $2)",
+		chi.URLParam(r, "id"), currentUserID(r)).Scan(&exists)
+	return err == nil && exists
+}
+
+// Kullanıcının görebildiği (silinmemiş; öğrenciler için yayında) sorunun ID'si.
+// Bulunamazsa yanıtı yazar ve false döner.
+func visibleQuestionParam(w http.ResponseWriter, r *http.Request, param string) (int, bool) {
+	if currentUserID(r) == 0 {
+		utils.SendError(w, http.StatusForbidden, "Misafir kullanıcılar soru kaydedemez")
+		return 0, false
+	}
+
+	var questionID int
+	err := db.GetPool().QueryRow(context.Background(), `
+        SELECT id FROM questions
+        WHERE id = $1 AND deleted_at IS NULL AND (status = $2 OR $3)`,
+		chi.URLParam(r, param), models.QuestionStatusPublished, isQuestionEditor(r)).Scan(&questionID)
+	if err != nil {
+		utils.SendError(w, http.StatusNotFound, "Soru bulunamadı")
+		return 0, false
+	}
+	return questionID, true
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Koleksiyon filtresi yalnızca kullanıcının kendi koleksiyonları için kullanılabilir
+func checkCollectionFilter(w http.ResponseWriter, r *http.Request) bool {
+	v := r.URL.Query().Get("collection_id")
+	if v == "" {
+		return true
+	}
+	collectionID, err := strconv.Atoi(v)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz koleksiyon ID")
+		return false
+	}
+	var exists bool
+	err = db.GetPool().QueryRow(context.Background(),
+		"SELECT EXISTS(SELECT 1 FROM question_collections WHERE id = $1 AND user_id = $2)",
+		collectionID, currentUserID(r)).Scan(&exists)
+	if err != nil || !exists {
+		utils.SendError(w, http.StatusNotFound, "Koleksiyon bulunamadı")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Süreli Sınav Başlat (soru havuzu GetQuestions filtreleriyle daraltılabilir)
+func StartExamSession(w http.ResponseWriter, r *http.Request) {
+	userID := currentUserID(r)
+	if userID == 0 {
+		utils.SendError(w, http.StatusForbidden, "Misafir kullanıcılar sınav başlatamaz")
+		return
+	}
+
+	var req models.ExamSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz istek gövdesi")
+		return
+	}
+
+	session, err := services.StartExamSession(context.Background(), userID, services.ExamFilters(r.URL.Query()), req)
+	if err != nil {
+		sendExamError(w, err, "Sınav başlatılamadı")
+		return
+	}
+
+	sendExamSession(w, http.StatusCreated, "Sınav başlatıldı", session)
+}
+
+// Sınavlarım (?limit, ?offset)
+func GetMyExamSessions(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pageParams(r.URL.Query(), 20, 100)
+	sessions, err := services.ListExamSessions(context.Background(), currentUserID(r), limit, offset)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Sınavlar alınamadı")
+		return
+	}
+	utils.SendSuccess(w, "Sınavlar getirildi", sessions)
+}
+
+// Sınav Oturumu (sorular, verilen cevaplar ve kalan süre)
+func GetExamSession(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := examSessionParam(w, r)
+	if !ok {
+		return
+	}
+
+	session, err := services.GetExamSession(context.Background(), sessionID, currentUserID(r))
+	if err != nil {
+		sendExamError(w, err, "Sınav alınamadı")
+		return
+	}
+
+	sendExamSession(w, http.StatusOK, "Sınav getirildi", session)
+}
+
+// Kalan Süre (sunucu saatine göre)
+func GetExamClock(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := examSessionParam(w, r)
+	if !ok {
+		return
+	}
+
+	clock, err := services.GetExamClock(context.Background(), currentUserID(r), sessionID)
+	if err != nil {
+		sendExamError(w, err, "Kalan süre alınamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "Kalan süre getirildi", clock)
+}
+
+// Sınavda Soruyu Cevapla (boş cevap soruyu boş bırakır)
+func AnswerExamQuestion(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := examSessionParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.ExamAnswerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz istek gövdesi")
+		return
+	}
+	if req.TimeSpentMS != nil && *req.TimeSpentMS < 0 {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz süre")
+		return
+	}
+
+	session, err := services.AnswerExamQuestion(context.Background(), currentUserID(r), sessionID, req)
+	if err != nil {
+		sendExamError(w, err, "Cevap kaydedilemedi")
+		return
+	}
+
+	utils.SendSuccess(w, "Cevap kaydedildi", session)
+}
+
+// Sınavı Duraklat (yalnızca alıştırma modu)
+func PauseExamSession(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := examSessionParam(w, r)
+	if !ok {
+		return
+	}
+
+	session, err := services.PauseExamSession(context.Background(), currentUserID(r), sessionID)
+	if err != nil {
+		sendExamError(w, err, "Sınav duraklatılamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "Sınav duraklatıldı", session)
+}
+
+// Sınavı Sürdür
+func ResumeExamSession(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := examSessionParam(w, r)
+	if !ok {
+		return
+	}
+
+	session, err := services.ResumeExamSession(context.Background(), currentUserID(r), sessionID)
+	if err != nil {
+		sendExamError(w, err, "Sınav sürdürülemedi")
+		return
+	}
+
+	utils.SendSuccess(w, "Sınav sürdürüldü", session)
+}
+
+// Sınavı Teslim Et
+func SubmitExamSession(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := examSessionParam(w, r)
+	if !ok {
+		return
+	}
+
+	session, err := services.SubmitExamSession(context.Background(), currentUserID(r), sessionID)
+	if err != nil {
+		sendExamError(w, err, "Sınav teslim edilemedi")
+		return
+	}
+
+	sendExamSession(w, http.StatusOK, "Sınav teslim edildi", session)
+}
+
+func examSessionParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	sessionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz sınav ID")
+		return 0, false
+	}
+	return sessionID, true
+}
+
+func sendExamError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrExamSessionNotFound), errors.Is(err, services.ErrExamQuestionNotInExam):
+		utils.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrExamInvalidRequest), errors.Is(err, services.ErrExamNoQuestions):
+		utils.SendError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrExamSessionFinished), errors.Is(err, services.ErrExamTimeExpired),
+		errors.Is(err, services.ErrExamSessionPaused), errors.Is(err, services.ErrExamNotPaused),
+		errors.Is(err, services.ErrExamPauseNotAllowed):
+		utils.SendError(w, http.StatusConflict, err.Error())
+	default:
+		utils.SendError(w, http.StatusInternalServerError, message)
+	}
+}
+
+// Oturumu sorularıyla gönderir; cevaplar ve çözümler sınav bitince açılır
+func sendExamSession(w http.ResponseWriter, status int, message string, session *models.ExamSession) {
+	ids := make([]int, len(session.Items))
+	for i, item := range session.Items {
+		ids[i] = item.QuestionID
+	}
+	questions, err := loadPracticeQuestions(ids, session.Status == models.ExamStatusFinished, "")
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Sınav soruları alınamadı")
+		return
+	}
+	byID := make(map[int]*models.Question, len(questions))
+	for i := range questions {
+		byID[questions[i].ID] = &questions[i]
+	}
+	for i := range session.Items {
+		session.Items[i].Question = byID[session.Items[i].QuestionID]
+	}
+
+	utils.SendResponse(w, status, true, message, session, "")
+}
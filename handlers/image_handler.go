@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// İmzalı resim sunucusu
+func ServeImage(w http.ResponseWriter, r *http.Request) {
+	// "../" ile images dizini dışına çıkılmasını engelle
+	urlPath := "/images" + path.Clean("/"+chi.URLParam(r, "*"))
+
+	if services.IsPublicImage(urlPath) {
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+	} else {
+		expiry, err := services.VerifyImageURL(urlPath, r.URL.Query().Get("expires"), r.URL.Query().Get("sig"))
+		if err != nil {
+			utils.SendError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		maxAge := int(time.Until(expiry).Seconds())
+		w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", maxAge))
+	}
+
+	filePath := filepath.FromSlash(urlPath[1:])
+	info, err := os.Stat(filePath)
+	if err != nil || info.IsDir() {
+		utils.SendError(w, http.StatusNotFound, "Resim bulunamadı")
+		return
+	}
+
+	// Yayıncı filigranı varsa önbellekteki filigranlı kopyayı sun; filigran uygulanamazsa
+	// resim erişilemez kalmasın diye orijinal dosya sunulur
+	if isQuestionImage(urlPath) {
+		settings, err := services.PublisherWatermarkForImage(r.Context(), urlPath)
+		if err != nil {
+			log.Printf("Filigran ayarları alınırken hata: %v", err)
+		}
+		if settings != nil {
+			if watermarked, err := services.WatermarkedImagePath(filePath, *settings); err != nil {
+				log.Printf("Filigran uygulanırken hata: %v", err)
+			} else {
+				filePath = watermarked
+			}
+		}
+	}
+
+	http.ServeFile(w, r, filePath)
+}
+
+func isQuestionImage(urlPath string) bool {
+	return strings.HasPrefix(urlPath, "/"+services.QuestionImagesPath+"/") ||
+		strings.HasPrefix(urlPath, "/"+services.SolutionImagesPath+"/")
+}
+
+// Soru yanıtındaki resim yollarını imzalı URL'lere çevirir
+func signQuestionImages(q *models.Question) {
+	q.PathURL = services.SignImageURL(q.PathURL)
+	q.SolutionURL = services.SignImageURL(q.SolutionURL)
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/utils"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// questions tablosundaki NULL olabilen IRT sütunları
+type irtColumns struct {
+	model        *string
+	a, b, c      *float64
+	responses    *int
+	calibratedAt *time.Time
+}
+
+func (c irtColumns) toModel() *models.QuestionIRT {
+	if c.model == nil || c.a == nil || c.b == nil || c.c == nil {
+		return nil
+	}
+	irt := &models.QuestionIRT{Model: *c.model, A: *c.a, B: *c.b, C: *c.c}
+	if c.responses != nil {
+		irt.Responses = *c.responses
+	}
+	if c.calibratedAt != nil {
+		irt.CalibratedAt = *c.calibratedAt
+	}
+	return irt
+}
+
+// Kalibre Edilmiş Soru Parametreleri
+func GetQuestionIRTParams(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT id, COALESCE(difficulty_level, ''),
+               irt_model, irt_a, irt_b, irt_c, irt_responses, irt_calibrated_at
+        FROM questions
+        WHERE deleted_at IS NULL AND irt_model IS NOT NULL
+        ORDER BY irt_b`)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "IRT parametreleri alınamadı")
+		return
+	}
+	defer rows.Close()
+
+	params := []models.QuestionIRTParams{}
+	for rows.Next() {
+		var p models.QuestionIRTParams
+		var irt irtColumns
+		if err := rows.Scan(&p.QuestionID, &p.DifficultyLevel,
+			&irt.model, &irt.a, &irt.b, &irt.c, &irt.responses, &irt.calibratedAt); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "IRT parametreleri okunamadı")
+			return
+		}
+		if m := irt.toModel(); m != nil {
+			p.IRT = *m
+			params = append(params, p)
+		}
+	}
+
+	utils.SendSuccess(w, "IRT parametreleri getirildi", params)
+}
+
+// Kullanıcının Ana Kategori Bazında Yetenek Kestirimleri
+func GetUserAbilities(w http.ResponseWriter, r *http.Request) {
+	abilities, err := loadUserAbilities(chi.URLParam(r, "userId"))
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Yetenek kestirimleri alınamadı")
+		return
+	}
+	utils.SendSuccess(w, "Yetenek kestirimleri getirildi", abilities)
+}
+
+func loadUserAbilities(userID interface{}) ([]models.UserAbility, error) {
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT ua.user_id, ua.main_category_id, mc.name,
+               ua.theta, ua.standard_error, ua.responses, ua.updated_at
+        FROM user_abilities ua
+        JOIN main_categories mc ON mc.id = ua.main_category_id AND mc.deleted_at IS NULL
+        WHERE ua.user_id = $1
+        ORDER BY mc.position, mc.id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	abilities := []models.UserAbility{}
+	for rows.Next() {
+		var a models.UserAbility
+		if err := rows.Scan(&a.UserID, &a.MainCategoryID, &a.MainCategoryName,
+			&a.Theta, &a.StandardError, &a.Responses, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		abilities = append(abilities, a)
+	}
+	return abilities, rows.Err()
+}
@@ -3,12 +3,20 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"image"
+	"io"
+	"log"
 	"net/http"
 	"osymapp/db"
 	"osymapp/models"
+	"osymapp/services"
 	"osymapp/utils"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 )
 
 // Yayıncı Ekleme
@@ -43,7 +51,9 @@ func CreatePublisher(w http.ResponseWriter, r *http.Request) {
 func GetAllPublishers(w http.ResponseWriter, r *http.Request) {
 	pool := db.GetPool()
 	rows, err := pool.Query(context.Background(),
-		`SELECT id, name, COALESCE(website_url, '') as website_url 
+		`SELECT id, name, COALESCE(website_url, '') as website_url,
+                COALESCE(logo_url, '') as logo_url, watermark_enabled,
+                watermark_position, watermark_opacity
          FROM publishers 
          ORDER BY id`)
 
@@ -54,18 +64,27 @@ func GetAllPublishers(w http.ResponseWriter, r *http.Request) {
 	defer rows.Close()
 
 	var publishers []struct {
-		ID         int    `json:"id"`
-		Name       string `json:"name"`
-		WebsiteURL string `json:"website_url,omitempty"`
+		ID                int     `json:"id"`
+		Name              string  `json:"name"`
+		WebsiteURL        string  `json:"website_url,omitempty"`
+		LogoURL           string  `json:"logo_url,omitempty"`
+		WatermarkEnabled  bool    `json:"watermark_enabled"`
+		WatermarkPosition string  `json:"watermark_position"`
+		WatermarkOpacity  float64 `json:"watermark_opacity"`
 	}
 
 	for rows.Next() {
 		var p struct {
-			ID         int    `json:"id"`
-			Name       string `json:"name"`
-			WebsiteURL string `json:"website_url,omitempty"`
+			ID                int     `json:"id"`
+			Name              string  `json:"name"`
+			WebsiteURL        string  `json:"website_url,omitempty"`
+			LogoURL           string  `json:"logo_url,omitempty"`
+			WatermarkEnabled  bool    `json:"watermark_enabled"`
+			WatermarkPosition string  `json:"watermark_position"`
+			WatermarkOpacity  float64 `json:"watermark_opacity"`
 		}
-		if err := rows.Scan(&p.ID, &p.Name, &p.WebsiteURL); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.WebsiteURL, &p.LogoURL,
+			&p.WatermarkEnabled, &p.WatermarkPosition, &p.WatermarkOpacity); err != nil {
 			utils.SendError(w, http.StatusInternalServerError, "Error scanning publisher: "+err.Error())
 			return
 		}
@@ -131,3 +150,128 @@ func DeletePublisher(w http.ResponseWriter, r *http.Request) {
 
 	utils.SendSuccess(w, "Publisher deleted successfully", nil)
 }
+
+// Yayıncı Logosu Yükleme
+func UploadPublisherLogo(w http.ResponseWriter, r *http.Request) {
+	publisherID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid publisher id")
+		return
+	}
+
+	if err := r.ParseMultipartForm(2 << 20); err != nil { // 2 MB limit
+		utils.SendError(w, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	file, header, err := r.FormFile("logo")
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Logo file is required")
+		return
+	}
+	defer file.Close()
+
+	// Dosyanın gerçekten resim olduğunu kontrol et
+	if _, _, err := image.DecodeConfig(file); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Logo must be a PNG, JPEG or GIF image")
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error reading logo")
+		return
+	}
+
+	logoURL, err := services.UploadImage(file, header, services.PublisherLogosPath)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error uploading logo: "+err.Error())
+		return
+	}
+
+	pool := db.GetPool()
+
+	// Eski logoyu sonradan silmek için al
+	var oldLogoURL string
+	err = pool.QueryRow(context.Background(),
+		"SELECT COALESCE(logo_url, '') FROM publishers WHERE id = $1",
+		publisherID).Scan(&oldLogoURL)
+	if errors.Is(err, pgx.ErrNoRows) {
+		services.DeleteImage(logoURL)
+		utils.SendError(w, http.StatusNotFound, "Publisher not found")
+		return
+	}
+	if err != nil {
+		services.DeleteImage(logoURL)
+		utils.SendError(w, http.StatusInternalServerError, "Error fetching publisher")
+		return
+	}
+
+	_, err = pool.Exec(context.Background(),
+		"UPDATE publishers SET logo_url = $1 WHERE id = $2",
+		logoURL, publisherID)
+	if err != nil {
+		services.DeleteImage(logoURL)
+		utils.SendError(w, http.StatusInternalServerError, "Error updating publisher logo")
+		return
+	}
+
+	if err := services.DeleteImage(oldLogoURL); err != nil {
+		log.Printf("Eski yayıncı logosu silinirken hata: %v", err)
+	}
+	if err := services.PurgeWatermarkCache(publisherID); err != nil {
+		log.Printf("Filigran önbelleği temizlenirken hata: %v", err)
+	}
+
+	utils.SendSuccess(w, "Publisher logo uploaded successfully", map[string]interface{}{
+		"id":       publisherID,
+		"logo_url": logoURL,
+	})
+}
+
+// Yayıncı Filigran Ayarlarını Güncelleme
+func UpdatePublisherWatermark(w http.ResponseWriter, r *http.Request) {
+	publisherID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid publisher id")
+		return
+	}
+
+	var req models.WatermarkSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if !services.IsValidWatermarkPosition(req.Position) {
+		utils.SendError(w, http.StatusBadRequest, "Position must be one of: "+strings.Join(services.WatermarkPositions, ", "))
+		return
+	}
+
+	if req.Opacity <= 0 || req.Opacity > 1 {
+		utils.SendError(w, http.StatusBadRequest, "Opacity must be between 0 and 1")
+		return
+	}
+
+	pool := db.GetPool()
+	result, err := pool.Exec(context.Background(),
+		`UPDATE publishers 
+         SET watermark_enabled = $1, watermark_position = $2, watermark_opacity = $3 
+         WHERE id = $4`,
+		req.Enabled, req.Position, req.Opacity, publisherID)
+
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error updating watermark settings")
+		return
+	}
+
+	if rowsAffected := result.RowsAffected(); rowsAffected == 0 {
+		utils.SendError(w, http.StatusNotFound, "Publisher not found")
+		return
+	}
+
+	// Eski ayarlarla üretilmiş varyantlar artık kullanılmayacak
+	if err := services.PurgeWatermarkCache(publisherID); err != nil {
+		log.Printf("Filigran önbelleği temizlenirken hata: %v", err)
+	}
+
+	utils.SendSuccess(w, "Watermark settings updated successfully", req)
+}
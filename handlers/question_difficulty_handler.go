@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/utils"
+)
+
+// Zorluk Seviyeleri (ölçek ve etiketleri)
+func GetDifficultyLevels(w http.ResponseWriter, r *http.Request) {
+	utils.SendSuccess(w, "Zorluk seviyeleri getirildi", models.DifficultyLabels)
+}
+
+// Gözlenen Zorluk Raporu (?flagged=true yalnızca etiketiyle çelişen soruları listeler)
+func GetDifficultyReport(w http.ResponseWriter, r *http.Request) {
+	query := `
+        SELECT s.question_id, s.attempts, s.p_value, s.discrimination,
+               s.observed_level, COALESCE(q.difficulty_level, ''), s.flags, s.computed_at
+        FROM question_difficulty_stats s
+        JOIN questions q ON q.id = s.question_id AND q.deleted_at IS NULL`
+	if r.URL.Query().Get("flagged") == "true" {
+		query += " WHERE cardinality(s.flags) > 0"
+	}
+	query += " ORDER BY cardinality(s.flags) DESC, s.attempts DESC"
+
+	rows, err := db.GetPool().Query(context.Background(), query)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Zorluk raporu alınamadı")
+		return
+	}
+	defer rows.Close()
+
+	stats := []models.QuestionDifficultyStats{}
+	for rows.Next() {
+		var s models.QuestionDifficultyStats
+		if err := rows.Scan(&s.QuestionID, &s.Attempts, &s.PValue, &s.Discrimination,
+			&s.ObservedLevel, &s.LabelledLevel, &s.Flags, &s.ComputedAt); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Zorluk raporu okunamadı")
+			return
+		}
+		stats = append(stats, s)
+	}
+
+	utils.SendSuccess(w, "Zorluk raporu getirildi", stats)
+}
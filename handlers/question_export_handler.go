@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"strings"
+)
+
+// Soru Bankasını Dışa Aktar (GetQuestions ile aynı filtreler; format=jsonl|csv|qti)
+func ExportQuestions(w http.ResponseWriter, r *http.Request) {
+	format, err := services.QuestionExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := `
+        SELECT q.id, q.path_url, q.solution_url, q.answer, q.difficulty_level, q.status,
+               q.publisher_id, COALESCE(p.name, ''),
+               COALESCE(
+                   (SELECT json_agg(paths.path ORDER BY paths.path)
+                    FROM (
+                        SELECT DISTINCT ON (c.id) mc.slug || '/' || sc.slug || '/' || c.slug AS path
+                        FROM question_categories qc
+                        JOIN categories c ON c.id = qc.category_id AND c.deleted_at IS NULL
+                        JOIN sub_categories sc ON sc.id = c.sub_category_id AND sc.deleted_at IS NULL
+                        JOIN main_category_sub_category mcsc ON mcsc.sub_category_id = sc.id
+                        JOIN main_categories mc ON mc.id = mcsc.main_category_id AND mc.deleted_at IS NULL
+                        WHERE qc.question_id = q.id
+                        ORDER BY c.id, mcsc.position, mc.id
+                    ) paths),
+                   '[]'::json
+               ) AS categories,
+               q.body, q.created_at, q.updated_at
+        FROM questions q
+        LEFT JOIN publishers p ON p.id = q.publisher_id
+        WHERE q.deleted_at IS NULL`
+
+	conditions, args := services.QuestionFilterConditions(r.URL.Query(), isQuestionEditor(r))
+	if len(conditions) > 0 {
+		query += " AND " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY q.id"
+
+	rows, err := db.GetPool().Query(context.Background(), query, args...)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error fetching questions: "+err.Error())
+		return
+	}
+	defer rows.Close()
+
+	w.Header().Set("Content-Type", services.QuestionExportContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, services.QuestionExportFileName(format)))
+
+	// Yanıt akış halinde yazılır; başlıklar gönderildikten sonra hatalar yalnızca loglanabilir
+	exporter, err := services.NewQuestionExporter(w, format)
+	if err != nil {
+		log.Printf("Soru dışa aktarma başlatılamadı: %v", err)
+		return
+	}
+
+	for rows.Next() {
+		var rec models.QuestionExportRecord
+		var categoriesJSON []byte
+		if err := rows.Scan(&rec.ID, &rec.PathURL, &rec.SolutionURL, &rec.Answer,
+			&rec.DifficultyLevel, &rec.Status, &rec.PublisherID, &rec.PublisherName,
+			&categoriesJSON, &rec.Body, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+			log.Printf("Soru dışa aktarma okunamadı: %v", err)
+			return
+		}
+		if err := json.Unmarshal(categoriesJSON, &rec.Categories); err != nil {
+			log.Printf("Soru %d kategorileri çözülemedi: %v", rec.ID, err)
+			return
+		}
+
+		if err := exporter.Write(rec); err != nil {
+			log.Printf("Soru %d dışa aktarılamadı: %v", rec.ID, err)
+			return
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Soru dışa aktarma yarıda kaldı: %v", err)
+		return
+	}
+
+	if err := exporter.Close(); err != nil {
+		log.Printf("Soru dışa aktarma tamamlanamadı: %v", err)
+	}
+}
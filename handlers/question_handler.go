@@ -3,17 +3,18 @@ package handlers
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"log"
+	"errors"
 	"net/http"
-	"os"
 	"osymapp/db"
 	"osymapp/models"
 	"osymapp/services"
 	"osymapp/utils"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 )
 
 func CreateQuestion(w http.ResponseWriter, r *http.Request) {
@@ -29,6 +30,21 @@ func CreateQuestion(w http.ResponseWriter, r *http.Request) {
 		utils.SendError(w, http.StatusBadRequest, "JSON parse hatası")
 		return
 	}
+	q.PathURL = services.StripImageSignature(q.PathURL)
+	q.SolutionURL = services.StripImageSignature(q.SolutionURL)
+
+	body, ok := prepareQuestionBody(w, q.Body)
+	if !ok {
+		return
+	}
+	q.Body = body
+
+	difficulty, err := services.NormalizeDifficulty(q.DifficultyLevel)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	q.DifficultyLevel = difficulty
 
 	// Soru resmini yükle
 	if file, header, err := r.FormFile("question_image"); err == nil {
@@ -53,97 +69,88 @@ func CreateQuestion(w http.ResponseWriter, r *http.Request) {
 	}
 
 	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Transaction başlatma hatası")
+		return
+	}
+	defer tx.Rollback(context.Background())
 
 	// Soruyu ekle
 	query := `
 		INSERT INTO public.questions (
 			path_url, answer, popularity, created_user_id, 
 			updated_user_id, solution_url, publisher_id, 
-			difficulty_level, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		RETURNING id, created_at, updated_at`
+			difficulty_level, body, created_at, updated_at
+		) VALUES ($1, $2, 0, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id, status, popularity, created_at, updated_at`
 
-	err := pool.QueryRow(context.Background(), query,
-		q.PathURL, q.Answer, q.Popularity, q.CreatedUserID,
+	// Popülerlik istemciden alınmaz; olay akışından hesaplanır
+	err = tx.QueryRow(context.Background(), query,
+		q.PathURL, q.Answer, q.CreatedUserID,
 		q.UpdatedUserID, q.SolutionURL, q.PublisherID,
-		q.DifficultyLevel).Scan(&q.ID, &q.CreatedAt, &q.UpdatedAt)
+		q.DifficultyLevel, q.Body).Scan(&q.ID, &q.Status, &q.Popularity, &q.CreatedAt, &q.UpdatedAt)
 
 	if err != nil {
 		utils.SendError(w, http.StatusInternalServerError, "Soru oluşturma hatası: "+err.Error())
 		return
 	}
 
+	if _, err := services.RecordQuestionRevision(context.Background(), tx, q.ID, currentUserID(r),
+		models.RevisionReasonCreate, nil); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Revizyon kaydetme hatası")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Transaction commit hatası")
+		return
+	}
+
+	signQuestionImages(&q)
+	q.Body = services.SanitizeQuestionBody(q.Body)
 	utils.SendSuccess(w, "Soru başarıyla oluşturuldu", q)
 }
 
 func GetQuestions(w http.ResponseWriter, r *http.Request) {
-	// Query parametrelerini al
-	subCategoryID := r.URL.Query().Get("sub_category_id")
-	categoryID := r.URL.Query().Get("category_id")
-	difficulty := r.URL.Query().Get("difficulty")
-	search := r.URL.Query().Get("search")
-
 	baseQuery := `
 		SELECT q.id, q.path_url, q.answer, q.popularity, 
 			   q.created_user_id, q.updated_user_id, q.solution_url, 
-			   q.publisher_id, q.difficulty_level,
+			   q.publisher_id, q.difficulty_level, q.status, q.body,
+			   q.irt_model, q.irt_a, q.irt_b, q.irt_c, q.irt_responses, q.irt_calibrated_at,
 			   q.created_at, q.updated_at,
 			   COALESCE(
 				   (SELECT json_agg(qc.category_id)
 					FROM question_categories qc
 					WHERE qc.question_id = q.id), 
 				   '[]'::json
-			   ) as categories
+			   ) as categories,
+			   COALESCE(
+				   (SELECT json_agg(qcn.node_id)
+					FROM question_category_nodes qcn
+					WHERE qcn.question_id = q.id), 
+				   '[]'::json
+			   ) as nodes
 		FROM public.questions q
-		WHERE 1=1`
-
-	var conditions []string
-	var args []interface{}
-	argCount := 1
-
-	// Arama filtresi
-	if search != "" {
-		conditions = append(conditions, fmt.Sprintf("(q.path_url ILIKE $%d OR q.solution_url ILIKE $%d)", argCount, argCount))
-		args = append(args, "%"+search+"%")
-		argCount++
-	}
-
-	// Alt kategori filtresi
-	if subCategoryID != "" {
-		conditions = append(conditions, fmt.Sprintf(`
-			EXISTS (
-				SELECT 1 FROM question_categories qc 
-				WHERE qc.question_id = q.id AND qc.category_id IN (
-					SELECT id FROM categories WHERE sub_category_id = $%d
-				)
-			)`, argCount))
-		args = append(args, subCategoryID)
-		argCount++
-	}
-
-	// Kategori filtresi
-	if categoryID != "" {
-		conditions = append(conditions, fmt.Sprintf(`
-			EXISTS (
-				SELECT 1 FROM question_categories qc 
-				WHERE qc.question_id = q.id AND qc.category_id = $%d
-			)`, argCount))
-		args = append(args, categoryID)
-		argCount++
-	}
-
-	// Zorluk seviyesi filtresi
-	if difficulty != "" {
-		conditions = append(conditions, fmt.Sprintf("q.difficulty_level = $%d", argCount))
-		args = append(args, difficulty)
-		argCount++
-	}
+		WHERE q.deleted_at IS NULL`
 
+	if !checkCollectionFilter(w, r) {
+		return
+	}
+	conditions, args := services.QuestionFilterConditions(r.URL.Query(), isQuestionEditor(r))
 	if len(conditions) > 0 {
 		baseQuery += " AND " + strings.Join(conditions, " AND ")
 	}
 
-	baseQuery += " ORDER BY q.id DESC"
+	// Sıralama: popular (zamanla azalan toplam skor), popular_week (son 7 gün)
+	switch r.URL.Query().Get("sort") {
+	case "popular":
+		baseQuery += " ORDER BY q.popularity DESC, q.id DESC"
+	case "popular_week":
+		baseQuery += " ORDER BY q.popularity_week DESC, q.popularity DESC, q.id DESC"
+	default:
+		baseQuery += " ORDER BY q.id DESC"
+	}
 
 	// Soruları getir
 	pool := db.GetPool()
@@ -154,16 +161,19 @@ func GetQuestions(w http.ResponseWriter, r *http.Request) {
 	}
 	defer rows.Close()
 
+	editor := isQuestionEditor(r)
 	var questions []models.Question
 	for rows.Next() {
 		var q models.Question
-		var categoriesJSON []byte
+		var categoriesJSON, nodesJSON []byte
+		var irt irtColumns
 		err := rows.Scan(
 			&q.ID, &q.PathURL, &q.Answer, &q.Popularity,
 			&q.CreatedUserID, &q.UpdatedUserID, &q.SolutionURL,
-			&q.PublisherID, &q.DifficultyLevel,
+			&q.PublisherID, &q.DifficultyLevel, &q.Status, &q.Body,
+			&irt.model, &irt.a, &irt.b, &irt.c, &irt.responses, &irt.calibratedAt,
 			&q.CreatedAt, &q.UpdatedAt,
-			&categoriesJSON)
+			&categoriesJSON, &nodesJSON)
 		if err != nil {
 			utils.SendError(w, http.StatusInternalServerError, "Error scanning question: "+err.Error())
 			return
@@ -174,10 +184,29 @@ func GetQuestions(w http.ResponseWriter, r *http.Request) {
 			utils.SendError(w, http.StatusInternalServerError, "Error parsing categories: "+err.Error())
 			return
 		}
+		if err := json.Unmarshal(nodesJSON, &q.Nodes); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Error parsing category nodes: "+err.Error())
+			return
+		}
 
+		if editor {
+			q.IRT = irt.toModel()
+		}
+		signQuestionImages(&q)
+		q.Body = services.SanitizeQuestionBody(q.Body)
 		questions = append(questions, q)
 	}
 
+	// Devam eden sınavdaki soruların cevapları sınav bitene kadar gizlenir
+	refs := make([]*models.Question, len(questions))
+	for i := range questions {
+		refs[i] = &questions[i]
+	}
+	if err := withholdOngoingExamAnswers(currentUserID(r), refs); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error checking exam sessions: "+err.Error())
+		return
+	}
+
 	utils.SendSuccess(w, "Questions fetched successfully", questions)
 }
 
@@ -188,28 +217,94 @@ func UpdateQuestion(w http.ResponseWriter, r *http.Request) {
 		utils.SendError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	q.PathURL = services.StripImageSignature(q.PathURL)
+	q.SolutionURL = services.StripImageSignature(q.SolutionURL)
+
+	body, ok := prepareQuestionBody(w, q.Body)
+	if !ok {
+		return
+	}
+
+	difficulty, err := services.NormalizeDifficulty(q.DifficultyLevel)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	q.DifficultyLevel = difficulty
+
+	questionID, err := strconv.Atoi(id)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
 
 	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error starting transaction")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	// Geçmişi olmayan sorunun mevcut hali önce saklanır
+	if err := services.EnsureQuestionBaselineRevision(context.Background(), tx, questionID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.SendError(w, http.StatusNotFound, "Question not found")
+			return
+		}
+		utils.SendError(w, http.StatusInternalServerError, "Error recording revision: "+err.Error())
+		return
+	}
+	before, err := services.LoadQuestionSnapshot(context.Background(), tx, questionID)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error loading question: "+err.Error())
+		return
+	}
+
 	query := `
 		UPDATE public.questions 
-		SET path_url=$1, answer=$2, popularity=$3, 
-			created_user_id=$4, updated_user_id=$5, 
-			solution_url=$6, publisher_id=$7, 
-			difficulty_level=$8,
+		SET path_url=$1, answer=$2,
+			created_user_id=$3, updated_user_id=$4, 
+			solution_url=$5, publisher_id=$6, 
+			difficulty_level=$7, body=$8,
 			updated_at=CURRENT_TIMESTAMP 
 		WHERE id=$9`
 
-	_, err := pool.Exec(context.Background(), query,
-		q.PathURL, q.Answer, q.Popularity,
+	// Popülerlik olay akışından hesaplandığı için istemci tarafından değiştirilemez
+	_, err = tx.Exec(context.Background(), query,
+		q.PathURL, q.Answer,
 		q.CreatedUserID, q.UpdatedUserID, q.SolutionURL,
-		q.PublisherID, q.DifficultyLevel,
-		id)
+		q.PublisherID, q.DifficultyLevel, body,
+		questionID)
 
 	if err != nil {
 		utils.SendError(w, http.StatusInternalServerError, "Error updating question: "+err.Error())
 		return
 	}
 
+	if err := services.SetQuestionImages(context.Background(), tx, questionID); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error updating question images: "+err.Error())
+		return
+	}
+
+	// Yayındaki sorunun içeriği değiştiyse soru yeniden incelemeye alınır
+	if err := services.ReturnEditedQuestionToReview(context.Background(), tx, questionID, currentUserID(r),
+		services.QuestionActionEdit, before); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error updating question status: "+err.Error())
+		return
+	}
+
+	if _, err := services.RecordQuestionRevision(context.Background(), tx, questionID, currentUserID(r),
+		models.RevisionReasonUpdate, nil); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error recording revision: "+err.Error())
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Error committing transaction")
+		return
+	}
+
 	utils.SendSuccess(w, "Question updated successfully", nil)
 }
 
@@ -227,6 +322,20 @@ func CreateQuestionWithCategories(w http.ResponseWriter, r *http.Request) {
 		utils.SendError(w, http.StatusBadRequest, "JSON parse hatası")
 		return
 	}
+	req.PathURL = services.StripImageSignature(req.PathURL)
+	req.SolutionURL = services.StripImageSignature(req.SolutionURL)
+
+	body, ok := prepareQuestionBody(w, req.Body)
+	if !ok {
+		return
+	}
+
+	difficulty, err := services.NormalizeDifficulty(req.DifficultyLevel)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.DifficultyLevel = difficulty
 
 	// Kullanıcı ID'sini al
 	userID := r.Context().Value("userID").(int)
@@ -267,26 +376,32 @@ func CreateQuestionWithCategories(w http.ResponseWriter, r *http.Request) {
 		`INSERT INTO questions (
 			path_url, answer, popularity, created_user_id, 
 			updated_user_id, solution_url, publisher_id, 
-			difficulty_level, created_at, updated_at
-		) VALUES ($1, $2, 0, $3, $3, $4, $5, $6, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+			difficulty_level, body, created_at, updated_at
+		) VALUES ($1, $2, 0, $3, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		RETURNING id`,
 		req.PathURL, req.Answer, userID, req.SolutionURL,
-		req.PublisherID, req.DifficultyLevel).Scan(&questionID)
+		req.PublisherID, req.DifficultyLevel, body).Scan(&questionID)
 
 	if err != nil {
 		utils.SendError(w, http.StatusInternalServerError, "Soru ekleme hatası: "+err.Error())
 		return
 	}
 
-	// Kategori ilişkilerini ekle
-	for _, categoryID := range req.CategoryIDs {
-		_, err = tx.Exec(context.Background(),
-			"INSERT INTO question_categories (question_id, category_id) VALUES ($1, $2)",
-			questionID, categoryID)
-		if err != nil {
-			utils.SendError(w, http.StatusInternalServerError, "Kategori ilişkisi ekleme hatası: "+err.Error())
-			return
-		}
+	// Kategori ilişkilerini ağaç düğümleri üzerinden ekle
+	if err := services.SetQuestionCategories(context.Background(), tx, questionID, req.CategoryIDs, req.NodeIDs); err != nil {
+		sendQuestionCategoryError(w, err)
+		return
+	}
+
+	if err := services.SetQuestionImages(context.Background(), tx, questionID); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Soru resimleri kaydedilemedi")
+		return
+	}
+
+	if _, err := services.RecordQuestionRevision(context.Background(), tx, questionID, userID,
+		models.RevisionReasonCreate, nil); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Revizyon kaydetme hatası")
+		return
 	}
 
 	// Soruyu getir
@@ -294,11 +409,12 @@ func CreateQuestionWithCategories(w http.ResponseWriter, r *http.Request) {
 	err = tx.QueryRow(context.Background(), `
 		SELECT id, path_url, answer, popularity, created_user_id, 
 			   updated_user_id, solution_url, publisher_id, 
-			   difficulty_level, created_at, updated_at
+			   difficulty_level, status, body, created_at, updated_at
 		FROM questions WHERE id = $1`, questionID).Scan(
 		&question.ID, &question.PathURL, &question.Answer, &question.Popularity,
 		&question.CreatedUserID, &question.UpdatedUserID, &question.SolutionURL,
-		&question.PublisherID, &question.DifficultyLevel, &question.CreatedAt, &question.UpdatedAt)
+		&question.PublisherID, &question.DifficultyLevel, &question.Status, &question.Body,
+		&question.CreatedAt, &question.UpdatedAt)
 
 	if err != nil {
 		utils.SendError(w, http.StatusInternalServerError, "Soru bilgilerini getirme hatası: "+err.Error())
@@ -306,11 +422,14 @@ func CreateQuestionWithCategories(w http.ResponseWriter, r *http.Request) {
 	}
 
 	question.Categories = req.CategoryIDs
+	question.Nodes = req.NodeIDs
 	if err := tx.Commit(context.Background()); err != nil {
 		utils.SendError(w, http.StatusInternalServerError, "Transaction commit hatası")
 		return
 	}
 
+	signQuestionImages(&question)
+	question.Body = services.SanitizeQuestionBody(question.Body)
 	utils.SendSuccess(w, "Soru başarıyla oluşturuldu", question)
 }
 
@@ -329,6 +448,20 @@ func UpdateQuestionWithCategories(w http.ResponseWriter, r *http.Request) {
 		utils.SendError(w, http.StatusBadRequest, "JSON parse hatası")
 		return
 	}
+	req.PathURL = services.StripImageSignature(req.PathURL)
+	req.SolutionURL = services.StripImageSignature(req.SolutionURL)
+
+	body, ok := prepareQuestionBody(w, req.Body)
+	if !ok {
+		return
+	}
+
+	difficulty, err := services.NormalizeDifficulty(req.DifficultyLevel)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.DifficultyLevel = difficulty
 
 	pool := db.GetPool()
 	tx, err := pool.Begin(context.Background())
@@ -359,6 +492,26 @@ func UpdateQuestionWithCategories(w http.ResponseWriter, r *http.Request) {
 		req.SolutionURL = path
 	}
 
+	// Geçmişi olmayan sorunun mevcut hali önce saklanır
+	id, err := strconv.Atoi(questionID)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz soru ID")
+		return
+	}
+	if err := services.EnsureQuestionBaselineRevision(context.Background(), tx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.SendError(w, http.StatusNotFound, "Soru bulunamadı")
+			return
+		}
+		utils.SendError(w, http.StatusInternalServerError, "Revizyon kaydetme hatası")
+		return
+	}
+	before, err := services.LoadQuestionSnapshot(context.Background(), tx, id)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Soru bilgilerini getirme hatası")
+		return
+	}
+
 	// Soruyu güncelle
 	result, err := tx.Exec(context.Background(),
 		`UPDATE questions SET 
@@ -368,10 +521,11 @@ func UpdateQuestionWithCategories(w http.ResponseWriter, r *http.Request) {
 			solution_url = COALESCE($4, solution_url),
 			publisher_id = COALESCE($5, publisher_id),
 			difficulty_level = COALESCE($6, difficulty_level),
+			body = COALESCE($7, body),
 			updated_at = CURRENT_TIMESTAMP
-		WHERE id = $7`,
+		WHERE id = $8`,
 		req.PathURL, req.Answer, userID, req.SolutionURL,
-		req.PublisherID, req.DifficultyLevel, questionID)
+		req.PublisherID, req.DifficultyLevel, body, questionID)
 
 	if err != nil {
 		utils.SendError(w, http.StatusInternalServerError, "Soru güncelleme hatası")
@@ -383,23 +537,28 @@ func UpdateQuestionWithCategories(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Kategori ilişkilerini güncelle
-	_, err = tx.Exec(context.Background(),
-		"DELETE FROM question_categories WHERE question_id = $1",
-		questionID)
-	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Kategori ilişkilerini silme hatası")
+	// Kategori ilişkilerini ağaç düğümleri üzerinden güncelle
+	if err := services.SetQuestionCategories(context.Background(), tx, id, req.CategoryIDs, req.NodeIDs); err != nil {
+		sendQuestionCategoryError(w, err)
 		return
 	}
 
-	for _, categoryID := range req.CategoryIDs {
-		_, err = tx.Exec(context.Background(),
-			"INSERT INTO question_categories (question_id, category_id) VALUES ($1, $2)",
-			questionID, categoryID)
-		if err != nil {
-			utils.SendError(w, http.StatusInternalServerError, "Kategori ilişkisi ekleme hatası")
-			return
-		}
+	if err := services.SetQuestionImages(context.Background(), tx, id); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Soru resimleri kaydedilemedi")
+		return
+	}
+
+	// Yayındaki sorunun içeriği değiştiyse soru yeniden incelemeye alınır
+	if err := services.ReturnEditedQuestionToReview(context.Background(), tx, id, userID,
+		services.QuestionActionEdit, before); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Soru durumu güncellenemedi")
+		return
+	}
+
+	if _, err := services.RecordQuestionRevision(context.Background(), tx, id, userID,
+		models.RevisionReasonUpdate, nil); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Revizyon kaydetme hatası")
+		return
 	}
 
 	if err := tx.Commit(context.Background()); err != nil {
@@ -410,73 +569,65 @@ func UpdateQuestionWithCategories(w http.ResponseWriter, r *http.Request) {
 	utils.SendSuccess(w, "Soru başarıyla güncellendi", map[string]interface{}{
 		"question_id": questionID,
 		"categories":  req.CategoryIDs,
+		"nodes":       req.NodeIDs,
 	})
 }
 
-// Soru Silme
-func DeleteQuestion(w http.ResponseWriter, r *http.Request) {
-	questionID := chi.URLParam(r, "id")
-
-	pool := db.GetPool()
-	tx, err := pool.Begin(context.Background())
+// Soru Görüntülendi (popülerlik için olay kaydı; toplu ve asenkron işlenir)
+func RecordQuestionView(w http.ResponseWriter, r *http.Request) {
+	questionID, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Transaction başlatma hatası")
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz soru ID")
 		return
 	}
-	defer tx.Rollback(context.Background())
 
-	// Önce resim yollarını al
-	var pathURL, solutionURL string
-	err = tx.QueryRow(context.Background(),
-		"SELECT path_url, solution_url FROM questions WHERE id = $1",
-		questionID).Scan(&pathURL, &solutionURL)
-	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Soru bilgilerini alma hatası")
-		return
-	}
+	services.RecordQuestionEvent(questionEventActor(r), questionID, models.QuestionEventView)
+	utils.SendResponse(w, http.StatusAccepted, true, "Görüntüleme kaydedildi", nil, "")
+}
 
-	// Kategori ilişkilerini sil
-	_, err = tx.Exec(context.Background(),
-		"DELETE FROM question_categories WHERE question_id = $1",
-		questionID)
-	if err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Kategori ilişkilerini silme hatası")
+// Soru Silme (çöp kutusuna taşır; saklama süresi sonunda kalıcı olarak silinir)
+func DeleteQuestion(w http.ResponseWriter, r *http.Request) {
+	questionID := chi.URLParam(r, "id")
+
+	pool := db.GetPool()
+	var deletedAt time.Time
+	err := pool.QueryRow(context.Background(),
+		`UPDATE questions
+         SET deleted_at = CURRENT_TIMESTAMP, deleted_by = NULLIF($2, 0)
+         WHERE id = $1 AND deleted_at IS NULL
+         RETURNING deleted_at`,
+		questionID, currentUserID(r)).Scan(&deletedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, http.StatusNotFound, "Soru bulunamadı")
 		return
 	}
-
-	// Soruyu sil
-	result, err := tx.Exec(context.Background(),
-		"DELETE FROM questions WHERE id = $1",
-		questionID)
 	if err != nil {
 		utils.SendError(w, http.StatusInternalServerError, "Soru silme hatası")
 		return
 	}
 
-	if rowsAffected := result.RowsAffected(); rowsAffected == 0 {
-		utils.SendError(w, http.StatusNotFound, "Soru bulunamadı")
-		return
-	}
-
-	if err := tx.Commit(context.Background()); err != nil {
-		utils.SendError(w, http.StatusInternalServerError, "Transaction commit hatası")
-		return
-	}
+	utils.SendSuccess(w, "Soru çöp kutusuna taşındı", map[string]interface{}{
+		"question_id": questionID,
+		"deleted_at":  deletedAt,
+		"purge_after": deletedAt.Add(services.QuestionTrashRetention()),
+	})
+}
 
-	// Resimleri dosya sisteminden sil
-	if pathURL != "" {
-		filePath := strings.TrimPrefix(pathURL, "/")
-		if err := os.Remove(filePath); err != nil {
-			log.Printf("Soru resmi silinirken hata: %v", err)
-		}
+// Soru metnini normalize edip doğrular; hata varsa doğrulama hatalarıyla 400 döner
+func prepareQuestionBody(w http.ResponseWriter, body *models.QuestionBody) (*models.QuestionBody, bool) {
+	body = services.NormalizeQuestionBody(body)
+	if errs := services.ValidateQuestionBody(body); len(errs) > 0 {
+		utils.SendResponse(w, http.StatusBadRequest, false, "", errs, "Soru metni geçersiz")
+		return nil, false
 	}
+	return body, true
+}
 
-	if solutionURL != "" {
-		filePath := strings.TrimPrefix(solutionURL, "/")
-		if err := os.Remove(filePath); err != nil {
-			log.Printf("Çözüm resmi silinirken hata: %v", err)
-		}
+func sendQuestionCategoryError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrCategoryNodeNotFound) {
+		utils.SendError(w, http.StatusBadRequest, "Kategori bulunamadı")
+		return
 	}
-
-	utils.SendSuccess(w, "Soru ve ilgili resimler başarıyla silindi", nil)
+	utils.SendError(w, http.StatusInternalServerError, "Kategori ilişkisi ekleme hatası")
 }
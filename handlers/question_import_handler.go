@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// Toplu soru içe aktarmada kabul edilen en büyük ZIP boyutu
+const maxQuestionImportUpload = 512 << 20
+
+// ZIP + manifest ile Toplu Soru İçe Aktarma (arka plan işi başlatır)
+func ImportQuestions(w http.ResponseWriter, r *http.Request) {
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = models.QuestionImportAtomic
+	}
+	if mode != models.QuestionImportAtomic && mode != models.QuestionImportPartial {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz mod (atomic veya partial olmalı)")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxQuestionImportUpload)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Dosya çok büyük veya form okunamadı")
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "ZIP dosyası gerekli")
+		return
+	}
+	defer file.Close()
+
+	if strings.ToLower(filepath.Ext(header.Filename)) != ".zip" {
+		utils.SendError(w, http.StatusBadRequest, "Sadece .zip dosyası kabul edilir")
+		return
+	}
+
+	// İş arka planda çalışacağı için yüklenen dosya geçici bir dosyaya kopyalanır
+	tmp, err := os.CreateTemp("", "question-import-*.zip")
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Geçici dosya oluşturulamadı")
+		return
+	}
+	if _, err := io.Copy(tmp, file); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		utils.SendError(w, http.StatusBadRequest, "Dosya okunamadı")
+		return
+	}
+	tmp.Close()
+
+	actorID := currentUserID(r)
+	jobID, err := services.CreateQuestionImportJob(context.Background(), header.Filename, mode, actorID)
+	if err != nil {
+		os.Remove(tmp.Name())
+		utils.SendError(w, http.StatusInternalServerError, "İçe aktarma işi oluşturulamadı")
+		return
+	}
+	services.StartQuestionImport(jobID, tmp.Name(), mode, actorID)
+
+	job, err := services.GetQuestionImportJob(context.Background(), jobID)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "İçe aktarma işi alınamadı")
+		return
+	}
+
+	utils.SendResponse(w, http.StatusAccepted, true, "İçe aktarma işi başlatıldı", job, "")
+}
+
+// İçe Aktarma İşlerini Listele
+func GetQuestionImportJobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := services.ListQuestionImportJobs(context.Background(), 50)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "İçe aktarma işleri alınamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "İçe aktarma işleri getirildi", jobs)
+}
+
+// İçe Aktarma İşinin Durumu (ilerleme takibi)
+func GetQuestionImportJob(w http.ResponseWriter, r *http.Request) {
+	jobID, err := strconv.ParseInt(chi.URLParam(r, "jobId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz iş ID")
+		return
+	}
+
+	job, err := services.GetQuestionImportJob(context.Background(), jobID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, http.StatusNotFound, "İçe aktarma işi bulunamadı")
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "İçe aktarma işi alınamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "İçe aktarma işi getirildi", job)
+}
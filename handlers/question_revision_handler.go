@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Soru Revizyonlarını Listele
+func GetQuestionRevisions(w http.ResponseWriter, r *http.Request) {
+	questionID := chi.URLParam(r, "id")
+
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT qr.id, qr.question_id, qr.revision, qr.reason, qr.restored_from,
+               qr.actor_id, COALESCE(u.username, ''), qr.snapshot, qr.created_at
+        FROM question_revisions qr
+        LEFT JOIN users u ON u.id = qr.actor_id
+        WHERE qr.question_id = $1
+        ORDER BY qr.revision DESC`, questionID)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Revizyonlar alınamadı")
+		return
+	}
+	defer rows.Close()
+
+	revisions := []models.QuestionRevision{}
+	for rows.Next() {
+		rev, err := scanQuestionRevision(rows)
+		if err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Revizyon okunamadı")
+			return
+		}
+		revisions = append(revisions, *rev)
+	}
+
+	utils.SendSuccess(w, "Revizyonlar getirildi", revisions)
+}
+
+// İki Revizyon Arasındaki Fark (?from=1&to=3, varsayılan: son revizyon ve bir öncesi)
+func DiffQuestionRevisions(w http.ResponseWriter, r *http.Request) {
+	questionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz soru ID")
+		return
+	}
+
+	pool := db.GetPool()
+
+	to, err := revisionParam(r, "to")
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz 'to' parametresi")
+		return
+	}
+	if to == 0 {
+		err := pool.QueryRow(context.Background(),
+			"SELECT COALESCE(MAX(revision), 0) FROM question_revisions WHERE question_id = $1",
+			questionID).Scan(&to)
+		if err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Revizyonlar alınamadı")
+			return
+		}
+	}
+
+	from, err := revisionParam(r, "from")
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz 'from' parametresi")
+		return
+	}
+	if from == 0 {
+		from = to - 1
+	}
+
+	revisions := make([]*models.QuestionRevision, 0, 2)
+	for _, number := range []int{from, to} {
+		rev, err := loadQuestionRevision(pool, questionID, number)
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.SendError(w, http.StatusNotFound, "Revizyon bulunamadı: "+strconv.Itoa(number))
+			return
+		}
+		if err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Revizyon okunamadı")
+			return
+		}
+		revisions = append(revisions, rev)
+	}
+
+	utils.SendSuccess(w, "Revizyon farkı hesaplandı", models.QuestionRevisionDiff{
+		QuestionID:   questionID,
+		FromRevision: from,
+		ToRevision:   to,
+		Changes:      services.DiffQuestionSnapshots(revisions[0].Snapshot, revisions[1].Snapshot),
+	})
+}
+
+// Soruyu Önceki Bir Revizyona Geri Döndür
+func RestoreQuestionRevision(w http.ResponseWriter, r *http.Request) {
+	questionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz soru ID")
+		return
+	}
+	revision, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz revizyon numarası")
+		return
+	}
+
+	userID := currentUserID(r)
+
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Transaction başlatma hatası")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	if err := services.EnsureQuestionBaselineRevision(context.Background(), tx, questionID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.SendError(w, http.StatusNotFound, "Soru bulunamadı")
+			return
+		}
+		utils.SendError(w, http.StatusInternalServerError, "Revizyon kaydedilemedi")
+		return
+	}
+
+	before, err := services.LoadQuestionSnapshot(context.Background(), tx, questionID)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Soru okunamadı")
+		return
+	}
+
+	target, err := loadQuestionRevision(tx, questionID, revision)
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, http.StatusNotFound, "Revizyon bulunamadı")
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Revizyon okunamadı")
+		return
+	}
+
+	s := target.Snapshot
+
+	// Ölçek öncesi revizyonlardaki serbest metin zorluk değerleri ölçeğe çevrilir
+	if s.DifficultyLevel, err = services.NormalizeDifficulty(s.DifficultyLevel); err != nil {
+		s.DifficultyLevel = ""
+	}
+	_, err = tx.Exec(context.Background(),
+		`UPDATE questions SET
+            path_url = $1, answer = $2, solution_url = $3,
+            publisher_id = $4, difficulty_level = $5, body = $6,
+            updated_user_id = $7, updated_at = CURRENT_TIMESTAMP
+         WHERE id = $8`,
+		s.PathURL, s.Answer, s.SolutionURL, s.PublisherID, s.DifficultyLevel, s.Body, userID, questionID)
+	if err == nil {
+		// Kategori ilişkileri ağaç düğümleri üzerinden geri yüklenir
+		err = services.SetQuestionCategories(context.Background(), tx, questionID, s.Categories, s.Nodes)
+	}
+	if err == nil {
+		err = services.SetQuestionImages(context.Background(), tx, questionID)
+	}
+	var pgErr *pgconn.PgError
+	if (errors.As(err, &pgErr) && pgErr.Code == "23503") || errors.Is(err, services.ErrCategoryNodeNotFound) {
+		utils.SendError(w, http.StatusConflict, "Revizyondaki yayıncı veya kategorilerden bazıları artık mevcut değil")
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Soru geri yüklenemedi")
+		return
+	}
+
+	// Yayındaki soruya eski içerik geri yüklenirse soru yeniden incelemeye alınır
+	if err := services.ReturnEditedQuestionToReview(context.Background(), tx, questionID, userID,
+		services.QuestionActionRestore, before); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Soru durumu güncellenemedi")
+		return
+	}
+
+	newRevision, err := services.RecordQuestionRevision(context.Background(), tx, questionID, userID,
+		models.RevisionReasonRestore, &revision)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Revizyon kaydedilemedi")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Transaction commit hatası")
+		return
+	}
+
+	utils.SendSuccess(w, "Soru revizyona geri döndürüldü", map[string]interface{}{
+		"question_id":   questionID,
+		"restored_from": revision,
+		"revision":      newRevision,
+	})
+}
+
+func loadQuestionRevision(q services.DBTX, questionID, revision int) (*models.QuestionRevision, error) {
+	return scanQuestionRevision(q.QueryRow(context.Background(), `
+        SELECT qr.id, qr.question_id, qr.revision, qr.reason, qr.restored_from,
+               qr.actor_id, COALESCE(u.username, ''), qr.snapshot, qr.created_at
+        FROM question_revisions qr
+        LEFT JOIN users u ON u.id = qr.actor_id
+        WHERE qr.question_id = $1 AND qr.revision = $2`, questionID, revision))
+}
+
+func scanQuestionRevision(row pgx.Row) (*models.QuestionRevision, error) {
+	var rev models.QuestionRevision
+	var snapshot []byte
+	if err := row.Scan(&rev.ID, &rev.QuestionID, &rev.Revision, &rev.Reason, &rev.RestoredFrom,
+		&rev.ActorID, &rev.ActorName, &snapshot, &rev.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(snapshot, &rev.Snapshot); err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+func revisionParam(r *http.Request, name string) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(v)
+}
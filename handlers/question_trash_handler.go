@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// Çöp Kutusundaki Soruları Listele
+func GetTrashedQuestions(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT q.id, q.path_url, q.answer, q.popularity,
+               q.created_user_id, q.updated_user_id, q.solution_url,
+               q.publisher_id, q.difficulty_level, q.status,
+               q.created_at, q.updated_at,
+               q.deleted_at, q.deleted_by, COALESCE(u.username, '')
+        FROM questions q
+        LEFT JOIN users u ON u.id = q.deleted_by
+        WHERE q.deleted_at IS NOT NULL
+        ORDER BY q.deleted_at DESC`)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Çöp kutusu alınamadı")
+		return
+	}
+	defer rows.Close()
+
+	retention := services.QuestionTrashRetention()
+	questions := []models.TrashedQuestion{}
+	for rows.Next() {
+		var t models.TrashedQuestion
+		q := &t.Question
+		if err := rows.Scan(&q.ID, &q.PathURL, &q.Answer, &q.Popularity,
+			&q.CreatedUserID, &q.UpdatedUserID, &q.SolutionURL,
+			&q.PublisherID, &q.DifficultyLevel, &q.Status,
+			&q.CreatedAt, &q.UpdatedAt,
+			&t.DeletedAt, &t.DeletedBy, &t.DeletedByName); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Çöp kutusu okunamadı")
+			return
+		}
+		t.PurgeAfter = t.DeletedAt.Add(retention)
+		signQuestionImages(q)
+		questions = append(questions, t)
+	}
+
+	utils.SendSuccess(w, "Çöp kutusu getirildi", questions)
+}
+
+// Soruyu Çöp Kutusundan Geri Yükle
+func RestoreQuestion(w http.ResponseWriter, r *http.Request) {
+	questionID := chi.URLParam(r, "id")
+
+	var restoredAt time.Time
+	err := db.GetPool().QueryRow(context.Background(),
+		`UPDATE questions
+         SET deleted_at = NULL, deleted_by = NULL, updated_at = CURRENT_TIMESTAMP
+         WHERE id = $1 AND deleted_at IS NOT NULL
+         RETURNING updated_at`,
+		questionID).Scan(&restoredAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, http.StatusNotFound, "Çöp kutusunda soru bulunamadı")
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Soru geri yüklenemedi")
+		return
+	}
+
+	utils.SendSuccess(w, "Soru geri yüklendi", map[string]interface{}{
+		"question_id": questionID,
+		"restored_at": restoredAt,
+	})
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// Soru Durumunu Değiştirme (gönder, onayla, reddet, arşivle, yeniden aç)
+func TransitionQuestionStatus(w http.ResponseWriter, r *http.Request) {
+	questionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Geçersiz soru ID")
+		return
+	}
+
+	var req models.QuestionTransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+
+	userID := r.Context().Value("userID").(int)
+
+	isReviewer, err := services.UserHasRole(context.Background(), userID, services.QuestionReviewerRoles...)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Kullanıcı rolleri kontrol edilirken hata oluştu")
+		return
+	}
+
+	pool := db.GetPool()
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Transaction başlatma hatası")
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	var current string
+	err = tx.QueryRow(context.Background(),
+		"SELECT status FROM questions WHERE id = $1 AND deleted_at IS NULL FOR UPDATE",
+		questionID).Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		utils.SendError(w, http.StatusNotFound, "Soru bulunamadı")
+		return
+	}
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Soru durumu alınamadı")
+		return
+	}
+
+	next, err := services.NextQuestionStatus(req.Action, current, isReviewer, req.Comment)
+	switch {
+	case errors.Is(err, services.ErrQuestionReviewerRequired):
+		utils.SendError(w, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, services.ErrQuestionTransitionInvalid):
+		utils.SendError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		utils.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Action == "approve" {
+		err := services.CheckQuestionApprover(context.Background(), tx, questionID, userID)
+		if errors.Is(err, services.ErrQuestionSelfApproval) {
+			utils.SendError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Soru geçmişi kontrol edilemedi")
+			return
+		}
+	}
+
+	_, err = tx.Exec(context.Background(),
+		"UPDATE questions SET status = $1, updated_user_id = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3",
+		next, userID, questionID)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Soru durumu güncellenemedi")
+		return
+	}
+
+	transition := models.QuestionTransition{
+		Action:     req.Action,
+		FromStatus: current,
+		ToStatus:   next,
+		ActorID:    &userID,
+		Comment:    req.Comment,
+	}
+	err = tx.QueryRow(context.Background(),
+		`INSERT INTO question_status_transitions (question_id, action, from_status, to_status, actor_id, comment)
+         VALUES ($1, $2, $3, $4, $5, $6)
+         RETURNING id, question_id, created_at`,
+		questionID, req.Action, current, next, userID, req.Comment).Scan(
+		&transition.ID, &transition.QuestionID, &transition.CreatedAt)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Durum geçişi kaydedilemedi")
+		return
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Transaction commit hatası")
+		return
+	}
+
+	utils.SendSuccess(w, "Soru durumu güncellendi", transition)
+}
+
+// Soru Durum Geçmişi
+func GetQuestionTransitions(w http.ResponseWriter, r *http.Request) {
+	questionID := chi.URLParam(r, "id")
+
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT t.id, t.question_id, t.action, t.from_status, t.to_status,
+               t.actor_id, COALESCE(u.username, ''), t.comment, t.created_at
+        FROM question_status_transitions t
+        LEFT JOIN users u ON u.id = t.actor_id
+        WHERE t.question_id = $1
+        ORDER BY t.created_at, t.id`, questionID)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Durum geçmişi alınamadı")
+		return
+	}
+	defer rows.Close()
+
+	transitions := []models.QuestionTransition{}
+	for rows.Next() {
+		var t models.QuestionTransition
+		if err := rows.Scan(&t.ID, &t.QuestionID, &t.Action, &t.FromStatus, &t.ToStatus,
+			&t.ActorID, &t.ActorName, &t.Comment, &t.CreatedAt); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "Durum geçmişi okunamadı")
+			return
+		}
+		transitions = append(transitions, t)
+	}
+
+	utils.SendSuccess(w, "Durum geçmişi getirildi", transitions)
+}
+
+// İnceleme Bekleyen Sorular
+func GetReviewQueue(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.GetPool().Query(context.Background(), `
+        SELECT q.id, q.path_url, q.answer, q.popularity,
+               q.created_user_id, q.updated_user_id, q.solution_url,
+               q.publisher_id, q.difficulty_level, q.status,
+               q.created_at, q.updated_at
+        FROM questions q
+        WHERE q.status = $1 AND q.deleted_at IS NULL
+        ORDER BY q.updated_at, q.id`, models.QuestionStatusInReview)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "İnceleme listesi alınamadı")
+		return
+	}
+	defer rows.Close()
+
+	questions := []models.Question{}
+	for rows.Next() {
+		var q models.Question
+		if err := rows.Scan(&q.ID, &q.PathURL, &q.Answer, &q.Popularity,
+			&q.CreatedUserID, &q.UpdatedUserID, &q.SolutionURL,
+			&q.PublisherID, &q.DifficultyLevel, &q.Status,
+			&q.CreatedAt, &q.UpdatedAt); err != nil {
+			utils.SendError(w, http.StatusInternalServerError, "İnceleme listesi okunamadı")
+			return
+		}
+		signQuestionImages(&q)
+		questions = append(questions, q)
+	}
+
+	utils.SendSuccess(w, "İnceleme bekleyen sorular getirildi", questions)
+}
+
+// Kullanıcının taslak ve incelemedeki soruları görebilen bir editör olup olmadığı
+func isQuestionEditor(r *http.Request) bool {
+	userID := currentUserID(r)
+	if userID == 0 {
+		return false
+	}
+
+	isEditor, err := services.UserHasRole(context.Background(), userID, services.QuestionEditorRoles...)
+	return err == nil && isEditor
+}
+
+// Popülerlik olaylarını kullanıcı başına tekilleştirmek için istek sahibinin kimliği
+func questionEventActor(r *http.Request) string {
+	if userID := currentUserID(r); userID != 0 {
+		return services.UserEventActor(userID)
+	}
+	guestID, _ := r.Context().Value("userID").(string)
+	return guestID
+}
+
+// Giriş yapmış üyenin ID'si; misafirler için 0
+func currentUserID(r *http.Request) int {
+	if isGuest, ok := r.Context().Value("is_guest").(bool); !ok || isGuest {
+		return 0
+	}
+	userID, _ := r.Context().Value("userID").(int)
+	return userID
+}
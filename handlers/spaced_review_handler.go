@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"osymapp/models"
+	"osymapp/services"
+	"osymapp/utils"
+)
+
+const maxDueReviews = 100
+
+// Bugün tekrar edilecek sorular (?limit); cevaplar source=review ile gönderilir
+func GetDueReviews(w http.ResponseWriter, r *http.Request) {
+	limit, _ := pageParams(r.URL.Query(), 20, maxDueReviews)
+
+	cards, total, today, err := services.GetDueReviewCards(context.Background(), currentUserID(r), limit)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Tekrar listesi alınamadı")
+		return
+	}
+
+	ids := make([]int, len(cards))
+	for i, c := range cards {
+		ids[i] = c.QuestionID
+	}
+	questions, err := loadPracticeQuestions(ids, false, models.QuestionStatusPublished)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Sorular alınamadı")
+		return
+	}
+	byID := make(map[int]models.Question, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+
+	list := models.DueReviewList{Date: today.Format("2006-01-02"), TotalDue: total, Items: []models.DueReview{}}
+	for _, c := range cards {
+		if q, ok := byID[c.QuestionID]; ok {
+			list.Items = append(list.Items, models.DueReview{Card: c, Question: q})
+		}
+	}
+
+	utils.SendSuccess(w, "Tekrar listesi getirildi", list)
+}
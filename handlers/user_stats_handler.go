@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"osymapp/services"
+	"osymapp/utils"
+	"strconv"
+)
+
+const (
+	defaultStatsWeeks = 12
+	maxStatsWeeks     = 104
+)
+
+// İstatistiklerim (?weeks: net trendi için hafta sayısı, ?min_attempts: zayıf konu eşiği)
+func GetMyStats(w http.ResponseWriter, r *http.Request) {
+	userID := currentUserID(r)
+	if userID == 0 {
+		utils.SendError(w, http.StatusForbidden, "Misafir kullanıcıların istatistiği tutulmaz")
+		return
+	}
+
+	weeks, err := strconv.Atoi(r.URL.Query().Get("weeks"))
+	if err != nil || weeks <= 0 {
+		weeks = defaultStatsWeeks
+	}
+	if weeks > maxStatsWeeks {
+		weeks = maxStatsWeeks
+	}
+	minAttempts, err := strconv.Atoi(r.URL.Query().Get("min_attempts"))
+	if err != nil || minAttempts <= 0 {
+		minAttempts = services.DefaultWeakTopicMinAttempts
+	}
+
+	stats, err := services.GetUserStats(context.Background(), userID, weeks, minAttempts)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "İstatistikler alınamadı")
+		return
+	}
+
+	utils.SendSuccess(w, "İstatistikler getirildi", stats)
+}
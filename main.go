@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
 	"osymapp/auth"
 	"osymapp/db"
 	"osymapp/handlers"
 	appmiddleware "osymapp/middleware"
+	"osymapp/services"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -20,6 +24,50 @@ func main() {
 	}
 	defer db.GetPool().Close()
 
+	if err := db.Migrate(); err != nil {
+		log.Fatal("Could not apply database migrations:", err)
+	}
+
+	// Slug'ı olmayan kategoriler için slug üret
+	if n, err := services.EnsureCategorySlugs(context.Background()); err != nil {
+		log.Fatal("Could not generate category slugs:", err)
+	} else if n > 0 {
+		log.Printf("%d kategori için slug üretildi", n)
+	}
+
+	// Yönetim komutu verildiyse sunucuyu başlatmadan çalıştır
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Önceki çalıştırmada yarıda kalan soru içe aktarma işlerini kapat
+	if err := services.FailInterruptedQuestionImports(context.Background()); err != nil {
+		log.Printf("Yarıda kalan içe aktarma işleri güncellenemedi: %v", err)
+	}
+
+	// Geri yükleme süresi dolan kategorileri temizle
+	services.StartCategoryPurge(time.Hour)
+
+	// Çöp kutusunda saklama süresi dolan soruları temizle
+	services.StartQuestionPurge(time.Hour)
+
+	// Soru olaylarını toplu yaz ve popülerlik skorlarını güncelle
+	services.StartPopularityPipeline(time.Minute, 10*time.Minute)
+
+	// Öğrenci cevaplarından gözlenen zorluğu hesapla
+	services.StartDifficultyCalibration(6 * time.Hour)
+
+	// Süresi dolan sınavları otomatik teslim et
+	services.StartExamSweeper(30 * time.Second)
+
+	// Sahipsiz resim temizleme görevi
+	if interval, opts := services.ImageGCConfigFromEnv(); interval > 0 {
+		services.StartImageGC(interval, opts)
+	}
+
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
 
@@ -27,8 +75,8 @@ func main() {
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:3000"}, // React uygulamanızın adresi
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-None-Match"},
+		ExposedHeaders:   []string{"Link", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -37,11 +85,23 @@ func main() {
 		w.Write([]byte("Merhaba, Dünya!"))
 	})
 
+	// Herkese açık, salt okunur katalog
+	r.Route("/catalog", func(r chi.Router) {
+		r.Get("/categories", handlers.GetCatalogTree)
+		r.Get("/categories/*", handlers.GetCatalogCategory)
+		r.Get("/sub-categories/{subId}/categories", handlers.GetSubCategoryCategories)
+		r.Get("/publishers", handlers.GetCatalogPublishers)
+		r.Get("/difficulty-levels", handlers.GetDifficultyLevels)
+	})
+
 	r.Post("/login", handlers.Login)
 	r.Post("/register", handlers.Register)
 	r.Post("/logout", auth.Logout)
 	r.Post("/guest-login", handlers.GuestLogin)
 
+	// Bağlantıyla paylaşılan koleksiyonlar (salt okunur)
+	r.Get("/shared/collections/{token}", handlers.GetSharedCollection)
+
 	r.Group(func(r chi.Router) {
 		r.Use(auth.JwtVerify)
 
@@ -51,10 +111,76 @@ func main() {
 		r.Put("/profile/password", handlers.ChangePassword)
 
 		// Normal kullanıcı işlemleri
-		r.Post("/questions", handlers.CreateQuestion)
 		r.Get("/questions", handlers.GetQuestions)
-		r.Put("/questions/{id}", handlers.UpdateQuestion)
-		r.Delete("/questions/{id}", handlers.DeleteQuestion)
+		r.Put("/questions/{id}/bookmark", handlers.BookmarkQuestion)
+		r.Delete("/questions/{id}/bookmark", handlers.UnbookmarkQuestion)
+		r.Get("/questions/{id}/note", handlers.GetQuestionNote)
+		r.Put("/questions/{id}/note", handlers.SaveQuestionNote)
+		r.Delete("/questions/{id}/note", handlers.DeleteQuestionNote)
+		r.Post("/questions/{id}/attempts", handlers.SubmitQuestionAttempt)
+		r.Post("/questions/{id}/view", handlers.RecordQuestionView)
+
+		// Soru yazma, durum ve revizyon işlemleri yalnızca editörler için
+		r.Group(func(r chi.Router) {
+			r.Use(appmiddleware.RequireRole(services.QuestionEditorRoles...))
+			r.Post("/questions", handlers.CreateQuestion)
+			r.Put("/questions/{id}", handlers.UpdateQuestion)
+			r.Delete("/questions/{id}", handlers.DeleteQuestion)
+			r.Post("/questions/{id}/transitions", handlers.TransitionQuestionStatus)
+			r.Get("/questions/{id}/transitions", handlers.GetQuestionTransitions)
+			r.Get("/questions/{id}/revisions", handlers.GetQuestionRevisions)
+			r.Get("/questions/{id}/revisions/diff", handlers.DiffQuestionRevisions)
+			r.Post("/questions/{id}/revisions/{revision}/restore", handlers.RestoreQuestionRevision)
+		})
+
+		// Uyarlanabilir test
+		r.Post("/practice/adaptive", handlers.StartAdaptiveSession)
+		r.Get("/practice/adaptive/{id}", handlers.GetAdaptiveSession)
+		r.Post("/practice/adaptive/{id}/answer", handlers.AnswerAdaptiveQuestion)
+
+		// Cevap geçmişi ve yanlışlar defteri
+		r.Get("/me/stats", handlers.GetMyStats)
+		r.Get("/me/attempts", handlers.GetMyAttempts)
+		r.Get("/me/mistakes", handlers.GetMyMistakes)
+		r.Post("/me/mistakes/practice-sets", handlers.CreateMistakePracticeSet)
+		r.Post("/me/mistakes/{questionId}/mastered", handlers.MarkMistakeMastered)
+		r.Delete("/me/mistakes/{questionId}/mastered", handlers.UnmarkMistakeMastered)
+		r.Get("/me/practice-sets/{id}", handlers.GetPracticeSet)
+
+		// Kaydedilen sorular, koleksiyonlar ve notlar
+		r.Get("/me/bookmarks", handlers.GetMyBookmarks)
+		r.Get("/me/notes", handlers.GetMyNotes)
+		r.Get("/me/collections", handlers.GetMyCollections)
+		r.Post("/me/collections", handlers.CreateCollection)
+		r.Get("/me/collections/{id}", handlers.GetCollection)
+		r.Put("/me/collections/{id}", handlers.UpdateCollection)
+		r.Delete("/me/collections/{id}", handlers.DeleteCollection)
+		r.Put("/me/collections/{id}/questions/{questionId}", handlers.AddQuestionToCollection)
+		r.Delete("/me/collections/{id}/questions/{questionId}", handlers.RemoveQuestionFromCollection)
+		r.Post("/me/collections/{id}/share", handlers.ShareCollection)
+		r.Delete("/me/collections/{id}/share", handlers.UnshareCollection)
+
+		// Süreli deneme sınavları
+		r.Post("/exams", handlers.StartExamSession)
+		r.Get("/exams", handlers.GetMyExamSessions)
+		r.Get("/exams/{id}", handlers.GetExamSession)
+		r.Get("/exams/{id}/clock", handlers.GetExamClock)
+		r.Post("/exams/{id}/answers", handlers.AnswerExamQuestion)
+		r.Post("/exams/{id}/pause", handlers.PauseExamSession)
+		r.Post("/exams/{id}/resume", handlers.ResumeExamSession)
+		r.Post("/exams/{id}/submit", handlers.SubmitExamSession)
+
+		// Aralıklı tekrar
+		r.Get("/review/due", handlers.GetDueReviews)
+
+		// İnceleme yetkisine sahip kullanıcılar için onay kuyruğu
+		r.Group(func(r chi.Router) {
+			r.Use(appmiddleware.RequireRole(services.QuestionReviewerRoles...))
+			r.Get("/review/questions", handlers.GetReviewQueue)
+		})
+
+		// Kategorilere slug yolu ile erişim (/categories/tyt/matematik/problemler)
+		r.Get("/categories/*", handlers.GetCategoryBySlugPath)
 
 		// Sadece Admin rolüne sahip kullanıcılar için rol yönetimi
 		r.Group(func(r chi.Router) {
@@ -71,6 +197,7 @@ func main() {
 			// Kullanıcı ve rol yönetimi
 			r.Get("/admin/users", handlers.GetAllUsers)
 			r.Get("/admin/roles", handlers.GetAllRoles)
+			r.Get("/admin/users/{userId}/abilities", handlers.GetUserAbilities)
 			r.Post("/users/{userId}/roles", handlers.AssignRole)
 			r.Delete("/users/{userId}/roles", handlers.RemoveRole)
 			r.Put("/admin/users/{userId}/roles", handlers.UpdateUserRoles)
@@ -83,28 +210,63 @@ func main() {
 			r.Post("/admin/categories/sub/{subId}/categories", handlers.AddCategoriesToSub)
 			r.Get("/admin/categories/main/{name}", handlers.GetMainCategoryDetails)
 			r.Get("/admin/categories/all", handlers.GetAllCategories)
+			r.Get("/admin/categories/stats", handlers.GetCategoryStats)
+			r.Get("/admin/categories/trash", handlers.GetDeletedCategories)
+			r.Post("/admin/categories/import", handlers.ImportCategories)
+			r.Get("/admin/categories/export", handlers.ExportCategories)
+			r.Get("/admin/categories/main/{mainId}/delete-preview", handlers.PreviewMainCategoryDeletion)
 			r.Delete("/admin/categories/main/{mainId}", handlers.DeleteMainCategory)
+			r.Post("/admin/categories/main/{mainId}/restore", handlers.RestoreMainCategory)
 			r.Put("/admin/categories/main/{mainId}", handlers.UpdateMainCategory)
+			r.Get("/admin/categories/sub/{subId}/delete-preview", handlers.PreviewSubCategoryDeletion)
 			r.Delete("/admin/categories/sub/{subId}", handlers.DeleteSubCategory)
+			r.Post("/admin/categories/sub/{subId}/restore", handlers.RestoreSubCategory)
 			r.Put("/admin/categories/sub/{subId}", handlers.UpdateSubCategory)
+			r.Get("/admin/categories/sub/{subId}/category/{categoryId}/delete-preview", handlers.PreviewCategoryDeletion)
 			r.Delete("/admin/categories/sub/{subId}/category/{categoryId}", handlers.DeleteCategory)
+			r.Post("/admin/categories/sub/{subId}/category/{categoryId}/restore", handlers.RestoreCategory)
 			r.Put("/admin/categories/sub/{subId}/category/{categoryId}", handlers.UpdateCategory)
+			r.Put("/admin/categories/sub/{subId}/category/{categoryId}/move", handlers.MoveCategory)
+			r.Post("/admin/categories/merge", handlers.MergeCategories)
+			r.Put("/admin/categories/order", handlers.ReorderMainCategories)
+			r.Put("/admin/categories/main/{mainId}/order", handlers.ReorderSubCategories)
+			r.Put("/admin/categories/sub/{subId}/order", handlers.ReorderCategories)
+
+			// Kategori ağacı işlemleri
+			r.Post("/admin/category-tree", handlers.CreateCategoryNode)
+			r.Get("/admin/category-tree", handlers.GetCategoryTree)
+			r.Get("/admin/category-tree/{id}", handlers.GetCategoryNode)
+			r.Put("/admin/category-tree/{id}", handlers.UpdateCategoryNode)
+			r.Put("/admin/category-tree/{id}/move", handlers.MoveCategoryNode)
+			r.Put("/admin/category-tree/order", handlers.ReorderCategoryNodes)
+			r.Delete("/admin/category-tree/{id}", handlers.DeleteCategoryNode)
+			r.Post("/admin/category-tree/{id}/restore", handlers.RestoreCategoryNode)
 
 			// Soru işlemleri
 			r.Post("/admin/questions", handlers.CreateQuestionWithCategories)
 			r.Put("/admin/questions/{id}", handlers.UpdateQuestionWithCategories)
 			r.Delete("/admin/questions/{id}", handlers.DeleteQuestion)
+			r.Get("/admin/questions/trash", handlers.GetTrashedQuestions)
+			r.Post("/admin/questions/{id}/restore", handlers.RestoreQuestion)
+			r.Post("/admin/questions/import", handlers.ImportQuestions)
+			r.Get("/admin/questions/import", handlers.GetQuestionImportJobs)
+			r.Get("/admin/questions/import/{jobId}", handlers.GetQuestionImportJob)
+			r.Get("/admin/questions/export", handlers.ExportQuestions)
+			r.Get("/admin/questions/difficulty-report", handlers.GetDifficultyReport)
+			r.Get("/admin/questions/irt", handlers.GetQuestionIRTParams)
 
 			// Yayıncı işlemleri
 			r.Post("/admin/publishers", handlers.CreatePublisher)
 			r.Get("/admin/publishers", handlers.GetAllPublishers)
 			r.Put("/admin/publishers/{id}", handlers.UpdatePublisher)
 			r.Delete("/admin/publishers/{id}", handlers.DeletePublisher)
+			r.Post("/admin/publishers/{id}/logo", handlers.UploadPublisherLogo)
+			r.Put("/admin/publishers/{id}/watermark", handlers.UpdatePublisherWatermark)
 		})
 	})
 
-	// Statik dosya sunucusu
-	r.Handle("/images/*", http.StripPrefix("/images/", http.FileServer(http.Dir("images"))))
+	// Resim sunucusu (images/public dışındaki resimler imzalı URL gerektirir)
+	r.Get("/images/*", handlers.ServeImage)
 
 	log.Println("Server starting on port 8080...")
 	http.ListenAndServe(":8080", r)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"osymapp/db"
+	"osymapp/services"
 	"osymapp/utils"
 )
 
@@ -67,3 +68,29 @@ func RequireAdmin(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// Verilen rollerden en az birine sahip olmayı gerektiren middleware
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			isGuest, ok := r.Context().Value("is_guest").(bool)
+			if !ok || isGuest {
+				utils.SendError(w, http.StatusForbidden, "Bu işlem için üye girişi yapmanız gerekmektedir")
+				return
+			}
+
+			userID := r.Context().Value("userID").(int)
+			hasRole, err := services.UserHasRole(context.Background(), userID, roles...)
+			if err != nil {
+				utils.SendError(w, http.StatusInternalServerError, "Kullanıcı rolleri kontrol edilirken hata oluştu")
+				return
+			}
+			if !hasRole {
+				utils.SendError(w, http.StatusForbidden, "Bu işlem için yetkiniz bulunmamaktadır")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Uyarlanabilir test oturum durumları ve bitiş nedenleri
+const (
+	AdaptiveSessionActive    = "active"
+	AdaptiveSessionCompleted = "completed"
+
+	AdaptiveStopSEThreshold   = "se_threshold"
+	AdaptiveStopMaxQuestions  = "max_questions"
+	AdaptiveStopPoolExhausted = "pool_exhausted"
+)
+
+type AdaptiveSessionRequest struct {
+	MaxQuestions int     `json:"max_questions"`
+	SEThreshold  float64 `json:"se_threshold"`
+}
+
+type AdaptiveAnswerRequest struct {
+	QuestionID  int    `json:"question_id"`
+	Answer      string `json:"answer"`
+	TimeSpentMS *int   `json:"time_spent_ms"`
+}
+
+type AdaptiveSession struct {
+	ID                int64             `json:"id"`
+	UserID            int               `json:"user_id"`
+	Status            string            `json:"status"`
+	Filters           map[string]string `json:"filters"`
+	MaxQuestions      int               `json:"max_questions"`
+	SEThreshold       float64           `json:"se_threshold"`
+	Theta             float64           `json:"theta"`
+	StandardError     float64           `json:"standard_error"`
+	StopReason        string            `json:"stop_reason,omitempty"`
+	CurrentQuestionID *int              `json:"current_question_id"`
+	Answered          int               `json:"answered"`
+	CreatedAt         time.Time         `json:"created_at"`
+	FinishedAt        *time.Time        `json:"finished_at"`
+	Trajectory        []AdaptiveStep    `json:"trajectory,omitempty"`
+}
+
+// Oturumda sorulan soru ve cevaptan sonraki yetenek kestirimi
+type AdaptiveStep struct {
+	Position    int        `json:"position"`
+	QuestionID  int        `json:"question_id"`
+	ContentArea int        `json:"content_area"`
+	Information float64    `json:"information"`
+	IsCorrect   *bool      `json:"is_correct"`
+	ThetaAfter  *float64   `json:"theta_after"`
+	SEAfter     *float64   `json:"se_after"`
+	SelectedAt  time.Time  `json:"selected_at"`
+	AnsweredAt  *time.Time `json:"answered_at"`
+}
+
+type AdaptiveAnswerResult struct {
+	Correct       bool             `json:"correct"`
+	CorrectAnswer string           `json:"correct_answer"`
+	SolutionURL   string           `json:"solution_url"`
+	Session       *AdaptiveSession `json:"session"`
+	NextQuestion  *Question        `json:"next_question"`
+}
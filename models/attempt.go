@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// Cevabın geldiği yer
+const (
+	AttemptSourcePractice = "practice"
+	AttemptSourceAdaptive = "adaptive"
+	AttemptSourceMistakes = "mistakes"
+	AttemptSourceReview   = "review"
+	AttemptSourceExam     = "exam"
+)
+
+type QuestionAttemptRequest struct {
+	Answer      string `json:"answer"`
+	TimeSpentMS *int   `json:"time_spent_ms"`
+	Source      string `json:"source"`     // practice (varsayılan), mistakes veya review
+	SessionID   *int64 `json:"session_id"` // tekrar seti ID'si
+}
+
+type QuestionAttempt struct {
+	ID           int64     `json:"id"`
+	UserID       int       `json:"user_id"`
+	QuestionID   int       `json:"question_id"`
+	ChosenAnswer string    `json:"chosen_answer"`
+	IsCorrect    bool      `json:"is_correct"`
+	TimeSpentMS  *int      `json:"time_spent_ms"`
+	Source       string    `json:"source"`
+	SessionID    *int64    `json:"session_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type QuestionAttemptResult struct {
+	Attempt       QuestionAttempt `json:"attempt"`
+	CorrectAnswer string          `json:"correct_answer"`
+	SolutionURL   string          `json:"solution_url"`
+}
+
+// Yanlış cevaplar defterindeki soru
+type Mistake struct {
+	Question     Question   `json:"question"`
+	WrongCount   int        `json:"wrong_count"`
+	LastAnswer   string     `json:"last_answer"`
+	FirstWrongAt time.Time  `json:"first_wrong_at"`
+	LastWrongAt  time.Time  `json:"last_wrong_at"`
+	MasteredAt   *time.Time `json:"mastered_at"`
+}
+
+type PracticeSetItem struct {
+	Position   int        `json:"position"`
+	QuestionID int        `json:"question_id"`
+	IsCorrect  *bool      `json:"is_correct"`
+	AnsweredAt *time.Time `json:"answered_at"`
+}
+
+type PracticeSet struct {
+	ID        int64             `json:"id"`
+	Source    string            `json:"source"`
+	Filters   map[string]string `json:"filters"`
+	CreatedAt time.Time         `json:"created_at"`
+	Items     []PracticeSetItem `json:"items"`
+	Questions []Question        `json:"questions"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// Öğrencilere ve misafirlere açık katalog yanıtları; yalnızca görüntüleme için gerekli alanlar içerir
+
+type CatalogMainCategory struct {
+	ID            int                  `json:"id"`
+	Name          string               `json:"name"`
+	Slug          string               `json:"slug"`
+	SubCategories []CatalogSubCategory `json:"sub_categories"`
+}
+
+type CatalogSubCategory struct {
+	ID         int           `json:"id"`
+	Name       string        `json:"name"`
+	Slug       string        `json:"slug"`
+	Categories []CategoryRef `json:"categories"`
+}
+
+type CatalogPublisher struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	WebsiteURL string `json:"website_url,omitempty"`
+	LogoURL    string `json:"logo_url,omitempty"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type MainCategory struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
@@ -22,11 +24,61 @@ type MainCategorySubCategory struct {
 }
 
 type CategoryRequest struct {
-	MainCategory  string             `json:"main_category"`
-	SubCategories []SubCategoryGroup `json:"sub_categories"`
+	MainCategory  string             `json:"main_category" yaml:"main_category"`
+	SubCategories []SubCategoryGroup `json:"sub_categories" yaml:"sub_categories"`
 }
 
 type SubCategoryGroup struct {
-	SubCategory string   `json:"sub_category"`
-	Categories  []string `json:"categories"`
+	SubCategory string   `json:"sub_category" yaml:"sub_category"`
+	Categories  []string `json:"categories" yaml:"categories"`
+}
+
+// Toplu içe aktarmada yapılan (veya dry-run'da yapılacak) değişiklik
+type TaxonomyChange struct {
+	Action string `json:"action"` // create, exists, missing_in_file
+	Level  string `json:"level"`  // main, sub, category
+	Path   string `json:"path"`
+}
+
+type TaxonomyImportResult struct {
+	DryRun   bool             `json:"dry_run"`
+	Mode     string           `json:"mode"`
+	Created  int              `json:"created"`
+	Existing int              `json:"existing"`
+	Changes  []TaxonomyChange `json:"changes"`
+}
+
+type CategoryDeletionImpact struct {
+	Level             string `json:"level"`
+	ID                int    `json:"id"`
+	SubCategories     int    `json:"sub_categories"`
+	Categories        int    `json:"categories"`
+	Questions         int    `json:"questions"`
+	OrphanedQuestions int    `json:"orphaned_questions"` // başka kategorisi kalmayacak sorular
+}
+
+type DeletedCategory struct {
+	Level           string    `json:"level"` // main, sub, category veya üçüncü seviyeden derin düğümler için node
+	ID              int       `json:"id"`
+	NodeID          int       `json:"node_id"` // /admin/category-tree/{id}/restore ile geri yüklenebilir
+	Name            string    `json:"name"`
+	DeletedAt       time.Time `json:"deleted_at"`
+	DeletedBy       *int      `json:"deleted_by"`
+	RestorableUntil time.Time `json:"restorable_until"`
+}
+
+// Slug ile adreslenen taksonomi düğümü
+type CategoryRef struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type CategorySlugNode struct {
+	Level    string        `json:"level"` // main, sub, category
+	ID       int           `json:"id"`
+	Name     string        `json:"name"`
+	Slug     string        `json:"slug"`
+	Path     []CategoryRef `json:"path"`
+	Children []CategoryRef `json:"children"`
 }
@@ -0,0 +1,24 @@
+package models
+
+type PublisherCount struct {
+	PublisherID int    `json:"publisher_id"`
+	Name        string `json:"name"`
+	Count       int    `json:"count"`
+}
+
+type CategoryStats struct {
+	QuestionCount   int              `json:"question_count"`
+	ByDifficulty    map[string]int   `json:"by_difficulty"`
+	ByPublisher     []PublisherCount `json:"by_publisher"`
+	Attempts        int              `json:"attempts"`
+	CorrectAttempts int              `json:"correct_attempts"`
+	SuccessRate     *float64         `json:"success_rate"` // cevap yoksa null
+}
+
+type CategoryStatsNode struct {
+	ID            int                  `json:"id"`
+	Name          string               `json:"name"`
+	Stats         CategoryStats        `json:"stats"`
+	SubCategories []*CategoryStatsNode `json:"sub_categories,omitempty"`
+	Categories    []*CategoryStatsNode `json:"categories,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+type CategoryNode struct {
+	ID       int             `json:"id"`
+	ParentID *int            `json:"parent_id"`
+	Name     string          `json:"name"`
+	Depth    int             `json:"depth"`
+	Position int             `json:"position"`
+	Children []*CategoryNode `json:"children,omitempty"`
+}
+
+type CategoryNodeRequest struct {
+	ParentID *int   `json:"parent_id"`
+	Name     string `json:"name"`
+}
+
+type CategoryNodeMoveRequest struct {
+	ParentID *int `json:"parent_id"` // null ise kök düğüm olur
+}
+
+type CategoryNodeOrderRequest struct {
+	ParentID *int  `json:"parent_id"` // null ise kök düğümler sıralanır
+	NodeIDs  []int `json:"node_ids"`
+}
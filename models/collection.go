@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type QuestionBookmark struct {
+	QuestionID   int       `json:"question_id"`
+	BookmarkedAt time.Time `json:"bookmarked_at"`
+	Question     *Question `json:"question,omitempty"`
+}
+
+type QuestionCollection struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	QuestionCount int       `json:"question_count"`
+	ShareToken    *string   `json:"share_token,omitempty"`
+	QuestionIDs   []int     `json:"question_ids,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type QuestionCollectionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Paylaşım bağlantısıyla açılan koleksiyon; cevaplar ve çözümler gizlidir
+type SharedQuestionCollection struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Owner       string     `json:"owner"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Questions   []Question `json:"questions"`
+}
+
+type QuestionNote struct {
+	QuestionID int       `json:"question_id"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type QuestionNoteRequest struct {
+	Note string `json:"note"`
+}
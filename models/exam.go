@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+// Sınav modları, durumları ve bitiş nedenleri
+const (
+	ExamModeExam     = "exam"     // gerçek sınav: duraklatılamaz
+	ExamModePractice = "practice" // alıştırma: duraklatılabilir
+
+	ExamStatusActive   = "active"
+	ExamStatusPaused   = "paused"
+	ExamStatusFinished = "finished"
+
+	ExamFinishSubmitted = "submitted"
+	ExamFinishExpired   = "expired"
+)
+
+type ExamPreset struct {
+	DurationMinutes int `json:"duration_minutes"`
+	QuestionCount   int `json:"question_count"`
+}
+
+// ÖSYM oturumlarının süre ve soru sayıları
+var ExamPresets = map[string]ExamPreset{
+	"tyt": {DurationMinutes: 165, QuestionCount: 120},
+	"ayt": {DurationMinutes: 180, QuestionCount: 80},
+	"ydt": {DurationMinutes: 180, QuestionCount: 80},
+}
+
+// Preset verilirse süre ve soru sayısı ondan alınır; ayrıca gönderilen değerler önceliklidir
+type ExamSessionRequest struct {
+	Preset          string `json:"preset"`
+	Mode            string `json:"mode"` // exam (varsayılan) veya practice
+	DurationMinutes int    `json:"duration_minutes"`
+	QuestionCount   int    `json:"question_count"`
+}
+
+type ExamAnswerRequest struct {
+	QuestionID  int    `json:"question_id"`
+	Answer      string `json:"answer"` // boş cevap soruyu boş bırakır
+	TimeSpentMS *int   `json:"time_spent_ms"`
+}
+
+// Sunucu saatine göre kalan süre; sayfa yenilendiğinde istemci saati buradan kurar
+type ExamClock struct {
+	Status           string    `json:"status"`
+	RemainingSeconds int       `json:"remaining_seconds"`
+	DeadlineAt       time.Time `json:"deadline_at"`
+	ServerTime       time.Time `json:"server_time"`
+}
+
+type ExamResult struct {
+	Correct int     `json:"correct"`
+	Wrong   int     `json:"wrong"`
+	Blank   int     `json:"blank"`
+	Net     float64 `json:"net"`
+}
+
+type ExamSessionItem struct {
+	Position      int        `json:"position"`
+	QuestionID    int        `json:"question_id"`
+	Answer        string     `json:"answer"`
+	TimeSpentMS   *int       `json:"time_spent_ms"`
+	AnsweredAt    *time.Time `json:"answered_at"`
+	IsCorrect     *bool      `json:"is_correct"`               // sınav bitince dolar
+	CorrectAnswer string     `json:"correct_answer,omitempty"` // sınav bitince gösterilir
+	Question      *Question  `json:"question,omitempty"`
+}
+
+type ExamSession struct {
+	ID              int64             `json:"id"`
+	UserID          int               `json:"user_id"`
+	Mode            string            `json:"mode"`
+	Preset          string            `json:"preset"`
+	Filters         map[string]string `json:"filters"`
+	DurationSeconds int               `json:"duration_seconds"`
+	StartedAt       time.Time         `json:"started_at"`
+	PausedAt        *time.Time        `json:"paused_at"`
+	PausedSeconds   int               `json:"paused_seconds"`
+	FinishedAt      *time.Time        `json:"finished_at"`
+	FinishReason    string            `json:"finish_reason,omitempty"`
+	QuestionCount   int               `json:"question_count"`
+	Answered        int               `json:"answered"`
+	Result          *ExamResult       `json:"result"`
+	ExamClock
+	Items []ExamSessionItem `json:"items,omitempty"`
+}
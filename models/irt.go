@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Sorunun IRT parametreleri
+type QuestionIRT struct {
+	Model        string    `json:"model"` // 2pl, 3pl
+	A            float64   `json:"a"`     // ayırt edicilik
+	B            float64   `json:"b"`     // zorluk
+	C            float64   `json:"c"`     // şans parametresi
+	Responses    int       `json:"responses"`
+	CalibratedAt time.Time `json:"calibrated_at"`
+}
+
+type QuestionIRTParams struct {
+	QuestionID      int         `json:"question_id"`
+	DifficultyLevel string      `json:"difficulty_level"`
+	IRT             QuestionIRT `json:"irt"`
+}
+
+// Öğrencinin ana kategorideki yetenek kestirimi
+type UserAbility struct {
+	UserID           int       `json:"user_id"`
+	MainCategoryID   int       `json:"main_category_id"`
+	MainCategoryName string    `json:"main_category_name"`
+	Theta            float64   `json:"theta"`
+	StandardError    float64   `json:"standard_error"`
+	Responses        int       `json:"responses"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type IRTCalibrationReport struct {
+	Model      string    `json:"model"`
+	Responses  int       `json:"responses"`
+	Questions  int       `json:"questions"`
+	Abilities  int       `json:"abilities"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
@@ -8,4 +8,16 @@ type Publisher struct {
 	WebsiteURL string    `json:"website_url,omitempty"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Filigran ayarları
+	LogoURL           string  `json:"logo_url,omitempty"`
+	WatermarkEnabled  bool    `json:"watermark_enabled"`
+	WatermarkPosition string  `json:"watermark_position,omitempty"`
+	WatermarkOpacity  float64 `json:"watermark_opacity,omitempty"`
+}
+
+type WatermarkSettingsRequest struct {
+	Enabled  bool    `json:"enabled"`
+	Position string  `json:"position"`
+	Opacity  float64 `json:"opacity"`
 }
@@ -3,25 +3,62 @@ package models
 import "time"
 
 type QuestionRequest struct {
-	PathURL         string `json:"path_url"`
-	Answer          string `json:"answer"`
-	SolutionURL     string `json:"solution_url"`
-	PublisherID     int    `json:"publisher_id"`
-	DifficultyLevel string `json:"difficulty_level"`
-	CategoryIDs     []int  `json:"category_ids"`
+	PathURL         string        `json:"path_url"`
+	Answer          string        `json:"answer"`
+	SolutionURL     string        `json:"solution_url"`
+	PublisherID     int           `json:"publisher_id"`
+	DifficultyLevel string        `json:"difficulty_level"`
+	CategoryIDs     []int         `json:"category_ids"`
+	NodeIDs         []int         `json:"node_ids"` // category_tree düğümleri
+	Body            *QuestionBody `json:"body,omitempty"`
 }
 
 type Question struct {
-	ID              int       `json:"id"`
-	PathURL         string    `json:"path_url"`
-	Answer          string    `json:"answer"`
-	Popularity      int       `json:"popularity"`
-	CreatedUserID   int       `json:"created_user_id"`
-	UpdatedUserID   int       `json:"updated_user_id"`
-	SolutionURL     string    `json:"solution_url"`
-	PublisherID     int       `json:"publisher_id"`
-	DifficultyLevel string    `json:"difficulty_level"`
-	Categories      []int     `json:"categories"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              int           `json:"id"`
+	PathURL         string        `json:"path_url"`
+	Answer          string        `json:"answer"`
+	Popularity      int           `json:"popularity"`
+	CreatedUserID   int           `json:"created_user_id"`
+	UpdatedUserID   int           `json:"updated_user_id"`
+	SolutionURL     string        `json:"solution_url"`
+	PublisherID     int           `json:"publisher_id"`
+	DifficultyLevel string        `json:"difficulty_level"`
+	Status          string        `json:"status"`
+	Body            *QuestionBody `json:"body,omitempty"`
+	IRT             *QuestionIRT  `json:"irt,omitempty"` // yalnızca editör ve yöneticilere
+	Categories      []int         `json:"categories"`
+	Nodes           []int         `json:"nodes"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
 }
+
+type TrashedQuestion struct {
+	Question
+	DeletedAt     time.Time `json:"deleted_at"`
+	DeletedBy     *int      `json:"deleted_by"`
+	DeletedByName string    `json:"deleted_by_name,omitempty"`
+	PurgeAfter    time.Time `json:"purge_after"`
+}
+
+// Dışa aktarılan soru kaydı (JSON Lines, CSV ve QTI için ortak)
+type QuestionExportRecord struct {
+	ID              int           `json:"id"`
+	PathURL         string        `json:"path_url"`
+	SolutionURL     string        `json:"solution_url"`
+	Answer          string        `json:"answer"`
+	DifficultyLevel string        `json:"difficulty_level"`
+	Status          string        `json:"status"`
+	PublisherID     int           `json:"publisher_id"`
+	PublisherName   string        `json:"publisher_name"`
+	Categories      []string      `json:"categories"` // slug yolları (tyt/matematik/problemler)
+	Body            *QuestionBody `json:"body,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// Popülerlik skoruna katkı veren soru olayları
+const (
+	QuestionEventView     = "view"
+	QuestionEventAttempt  = "attempt"
+	QuestionEventBookmark = "bookmark"
+)
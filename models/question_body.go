@@ -0,0 +1,19 @@
+package models
+
+// Sorunun resme ek olarak saklanan metin hali. Alanlar Markdown alt kümesi ve
+// satır içi ($...$, \(...\)) veya blok ($$...$$, \[...\]) LaTeX içerebilir.
+type QuestionBody struct {
+	Stem     string           `json:"stem"`
+	Options  []QuestionOption `json:"options,omitempty"`
+	Solution string           `json:"solution,omitempty"`
+}
+
+type QuestionOption struct {
+	Label string `json:"label"` // A, B, C...
+	Text  string `json:"text"`
+}
+
+type QuestionBodyError struct {
+	Field   string `json:"field"` // stem, options[0].text, solution
+	Message string `json:"message"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Zorluk ölçeği: 1 (çok kolay) - 5 (çok zor); boş değer etiketsiz demektir
+const (
+	DifficultyVeryEasy = "1"
+	DifficultyEasy     = "2"
+	DifficultyMedium   = "3"
+	DifficultyHard     = "4"
+	DifficultyVeryHard = "5"
+)
+
+var DifficultyLabels = map[string]string{
+	DifficultyVeryEasy: "Çok kolay",
+	DifficultyEasy:     "Kolay",
+	DifficultyMedium:   "Orta",
+	DifficultyHard:     "Zor",
+	DifficultyVeryHard: "Çok zor",
+}
+
+// Gözlenen zorluğun etiketle çeliştiği durumlar
+const (
+	DifficultyFlagEasierThanLabelled = "easier_than_labelled"
+	DifficultyFlagHarderThanLabelled = "harder_than_labelled"
+	DifficultyFlagLowDiscrimination  = "low_discrimination"
+)
+
+type QuestionDifficultyStats struct {
+	QuestionID     int       `json:"question_id"`
+	Attempts       int       `json:"attempts"`
+	PValue         float64   `json:"p_value"`
+	Discrimination *float64  `json:"discrimination"`
+	ObservedLevel  string    `json:"observed_level"`
+	LabelledLevel  string    `json:"labelled_level"`
+	Flags          []string  `json:"flags"`
+	ComputedAt     time.Time `json:"computed_at"`
+}
+
+type DifficultyCalibrationReport struct {
+	Questions int `json:"questions"`
+	Flagged   int `json:"flagged"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Toplu içe aktarma modları
+const (
+	QuestionImportAtomic  = "atomic"  // bir satır bile hatalıysa hiçbir soru eklenmez
+	QuestionImportPartial = "partial" // geçerli satırlar eklenir, hatalılar raporlanır
+)
+
+// Manifest dosyasındaki bir satır (manifest.csv veya manifest.json)
+type QuestionImportManifestRow struct {
+	QuestionImage string        `json:"question_image"`
+	SolutionImage string        `json:"solution_image"`
+	Answer        string        `json:"answer"`
+	Publisher     string        `json:"publisher"` // yayıncı ID'si veya adı (JSON'da metin olarak)
+	Difficulty    string        `json:"difficulty"`
+	Categories    []string      `json:"categories"` // "tyt/matematik/problemler" veya "TYT > Matematik > Problemler"
+	Body          *QuestionBody `json:"body,omitempty"`
+}
+
+type QuestionImportError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type QuestionImportJob struct {
+	ID            int64                 `json:"id"`
+	Status        string                `json:"status"` // queued, running, completed, failed
+	Mode          string                `json:"mode"`
+	FileName      string                `json:"file_name"`
+	TotalRows     int                   `json:"total_rows"`
+	ProcessedRows int                   `json:"processed_rows"`
+	ImportedRows  int                   `json:"imported_rows"`
+	FailedRows    int                   `json:"failed_rows"`
+	RowErrors     []QuestionImportError `json:"row_errors"`
+	QuestionIDs   []int                 `json:"question_ids"`
+	Error         string                `json:"error,omitempty"`
+	CreatedBy     *int                  `json:"created_by"`
+	CreatedAt     time.Time             `json:"created_at"`
+	StartedAt     *time.Time            `json:"started_at"`
+	FinishedAt    *time.Time            `json:"finished_at"`
+}
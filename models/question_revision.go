@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Revizyon nedenleri
+const (
+	RevisionReasonBaseline = "baseline" // geçmiş tutulmaya başlanmadan önceki hali
+	RevisionReasonCreate   = "create"
+	RevisionReasonUpdate   = "update"
+	RevisionReasonRestore  = "restore"
+)
+
+// Sorunun revizyonda saklanan içeriği
+type QuestionSnapshot struct {
+	PathURL         string        `json:"path_url"`
+	Answer          string        `json:"answer"`
+	SolutionURL     string        `json:"solution_url"`
+	PublisherID     int           `json:"publisher_id"`
+	DifficultyLevel string        `json:"difficulty_level"`
+	Body            *QuestionBody `json:"body,omitempty"`
+	Categories      []int         `json:"categories"`
+	Nodes           []int         `json:"nodes"`
+}
+
+type QuestionRevision struct {
+	ID           int64            `json:"id"`
+	QuestionID   int              `json:"question_id"`
+	Revision     int              `json:"revision"`
+	Reason       string           `json:"reason"`
+	RestoredFrom *int             `json:"restored_from,omitempty"`
+	ActorID      *int             `json:"actor_id"`
+	ActorName    string           `json:"actor_name,omitempty"`
+	Snapshot     QuestionSnapshot `json:"snapshot"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+type QuestionFieldChange struct {
+	Field   string      `json:"field"`
+	From    interface{} `json:"from"`
+	To      interface{} `json:"to"`
+	Added   []int       `json:"added,omitempty"`
+	Removed []int       `json:"removed,omitempty"`
+}
+
+type QuestionRevisionDiff struct {
+	QuestionID   int                   `json:"question_id"`
+	FromRevision int                   `json:"from_revision"`
+	ToRevision   int                   `json:"to_revision"`
+	Changes      []QuestionFieldChange `json:"changes"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Soru durumları
+const (
+	QuestionStatusDraft     = "draft"
+	QuestionStatusInReview  = "in_review"
+	QuestionStatusPublished = "published"
+	QuestionStatusArchived  = "archived"
+)
+
+type QuestionTransitionRequest struct {
+	Action  string `json:"action"` // submit, approve, reject, archive, reopen
+	Comment string `json:"comment"`
+}
+
+type QuestionTransition struct {
+	ID         int64     `json:"id"`
+	QuestionID int       `json:"question_id"`
+	Action     string    `json:"action"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ActorID    *int      `json:"actor_id"`
+	ActorName  string    `json:"actor_name,omitempty"`
+	Comment    string    `json:"comment,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
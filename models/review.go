@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Kullanıcının bir soru için aralıklı tekrar durumu
+type ReviewCard struct {
+	QuestionID     int        `json:"question_id"`
+	Algorithm      string     `json:"algorithm"` // sm2, fsrs
+	Repetitions    int        `json:"repetitions"`
+	Lapses         int        `json:"lapses"`
+	IntervalDays   int        `json:"interval_days"`
+	EaseFactor     float64    `json:"ease_factor,omitempty"`
+	Stability      float64    `json:"stability,omitempty"`
+	Difficulty     float64    `json:"difficulty,omitempty"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at"`
+	DueAt          time.Time  `json:"due_at"`
+	SubCategoryID  int        `json:"sub_category_id,omitempty"`
+}
+
+type DueReview struct {
+	Card     ReviewCard `json:"card"`
+	Question Question   `json:"question"`
+}
+
+type DueReviewList struct {
+	Date     string      `json:"date"`
+	TotalDue int         `json:"total_due"`
+	Items    []DueReview `json:"items"`
+}
+
+// Algoritmaların karşılaştırılması için kart ve tekrar istatistikleri
+type ReviewAlgorithmStats struct {
+	Algorithm       string  `json:"algorithm"`
+	Cards           int     `json:"cards"`
+	Overdue         int     `json:"overdue"`
+	AvgIntervalDays float64 `json:"avg_interval_days"`
+	Lapses          int     `json:"lapses"`
+	Reviews         int     `json:"reviews"`     // source=review ile verilen cevaplar
+	RecallRate      float64 `json:"recall_rate"` // bu cevaplardaki doğru oranı
+}
@@ -0,0 +1,47 @@
+package models
+
+// ÖSYM puanlamasında 4 yanlış 1 doğruyu götürür
+const NetPenaltyRatio = 4.0
+
+// Konu seviyeleri
+const (
+	StatsLevelAll      = "all"
+	StatsLevelMain     = "main"
+	StatsLevelSub      = "sub"
+	StatsLevelCategory = "category"
+	StatsLevelNode     = "node"
+)
+
+type PerformanceStats struct {
+	Attempts  int      `json:"attempts"`
+	Correct   int      `json:"correct"`
+	Wrong     int      `json:"wrong"`
+	Accuracy  float64  `json:"accuracy"`
+	Net       float64  `json:"net"`         // doğru - yanlış/4
+	AvgTimeMS *float64 `json:"avg_time_ms"` // süre bilgisi yoksa null
+}
+
+// Haftalık net değişimi
+type NetTrendPoint struct {
+	WeekStart string `json:"week_start"`
+	PerformanceStats
+}
+
+type TopicPerformance struct {
+	Level    string `json:"level"` // main, sub, category, node
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	ParentID *int   `json:"parent_id,omitempty"` // kategori için alt kategori, düğüm için üst düğüm
+	PerformanceStats
+	Trend []NetTrendPoint `json:"trend"`
+}
+
+type UserStats struct {
+	Overall        PerformanceStats   `json:"overall"`
+	Trend          []NetTrendPoint    `json:"trend"`
+	MainCategories []TopicPerformance `json:"main_categories"`
+	SubCategories  []TopicPerformance `json:"sub_categories"`
+	Categories     []TopicPerformance `json:"categories"`
+	Nodes          []TopicPerformance `json:"nodes"`
+	Weakest        []TopicPerformance `json:"weakest"` // en düşük başarılı kategoriler
+}
@@ -0,0 +1,236 @@
+package psychometrics
+
+import "math"
+
+// Kalibrasyon verisindeki tek gözlem
+type Observation struct {
+	Person  int
+	Item    int
+	Correct bool
+}
+
+type CalibrationOptions struct {
+	Model          string // Model2PL veya Model3PL
+	Iterations     int    // dış döngü (yetenek ↔ madde) sayısı
+	MinItemCount   int    // kalibre edilecek maddenin en az cevap sayısı
+	MinPersonCount int    // madde kestiriminde kullanılacak öğrencinin en az cevap sayısı
+	GuessingPrior  float64
+}
+
+func DefaultCalibrationOptions(model string) CalibrationOptions {
+	return CalibrationOptions{
+		Model:          model,
+		Iterations:     10,
+		MinItemCount:   30,
+		MinPersonCount: 5,
+		GuessingPrior:  0.2, // beş seçenekli sorular
+	}
+}
+
+type CalibratedItem struct {
+	Item
+	Count int `json:"count"`
+}
+
+type PersonAbility struct {
+	Theta float64 `json:"theta"`
+	SE    float64 `json:"se"`
+	Count int     `json:"count"`
+}
+
+type CalibrationResult struct {
+	Items   map[int]CalibratedItem
+	Persons map[int]PersonAbility
+}
+
+// Cevap verisinden madde parametrelerini kestirir. Yetenekler EAP ile, madde
+// parametreleri ön dağılımlı en çok olabilirlik (MAP) ile dönüşümlü olarak
+// güncellenir; ölçek, yeteneklerin standart normal ön dağılımıyla sabitlenir.
+func Calibrate(observations []Observation, opts CalibrationOptions) CalibrationResult {
+	byItem := make(map[int][]Observation)
+	byPerson := make(map[int][]Observation)
+	for _, o := range observations {
+		byItem[o.Item] = append(byItem[o.Item], o)
+		byPerson[o.Person] = append(byPerson[o.Person], o)
+	}
+
+	// Başlangıç: zorluk doğru cevaplama oranının logit'inden
+	items := make(map[int]Item)
+	for id, obs := range byItem {
+		if len(obs) < opts.MinItemCount {
+			continue
+		}
+		p := (float64(countCorrect(obs)) + 0.5) / (float64(len(obs)) + 1)
+		items[id] = Item{A: 1, B: clamp(-math.Log(p/(1-p)), MinTheta, MaxTheta), C: initialGuessing(opts)}
+	}
+
+	thetas := make(map[int]float64)
+	for iter := 0; iter < opts.Iterations; iter++ {
+		for person, obs := range byPerson {
+			if responses := responsesFor(obs, items); len(responses) >= opts.MinPersonCount {
+				thetas[person], _ = EstimateAbility(responses)
+			}
+		}
+		// EAP kestirimleri ön dağılıma doğru büzüldüğünden ölçek her turda
+		// ortalama 0, standart sapma 1 olacak şekilde yeniden sabitlenir
+		standardize(thetas)
+
+		for id, item := range items {
+			var data []itemData
+			for _, o := range byItem[id] {
+				if theta, ok := thetas[o.Person]; ok {
+					data = append(data, itemData{theta: theta, correct: o.Correct})
+				}
+			}
+			if len(data) > 0 {
+				items[id] = fitItem(item, data, opts)
+			}
+		}
+	}
+
+	result := CalibrationResult{Items: make(map[int]CalibratedItem), Persons: make(map[int]PersonAbility)}
+	for id, item := range items {
+		result.Items[id] = CalibratedItem{Item: item, Count: len(byItem[id])}
+	}
+	for person, obs := range byPerson {
+		responses := responsesFor(obs, items)
+		if len(responses) == 0 {
+			continue
+		}
+		theta, se := EstimateAbility(responses)
+		result.Persons[person] = PersonAbility{Theta: theta, SE: se, Count: len(responses)}
+	}
+	return result
+}
+
+type itemData struct {
+	theta   float64
+	correct bool
+}
+
+func initialGuessing(opts CalibrationOptions) float64 {
+	if opts.Model == Model3PL {
+		return opts.GuessingPrior
+	}
+	return 0
+}
+
+func responsesFor(obs []Observation, items map[int]Item) []Response {
+	var responses []Response
+	for _, o := range obs {
+		if item, ok := items[o.Item]; ok {
+			responses = append(responses, Response{Item: item, Correct: o.Correct})
+		}
+	}
+	return responses
+}
+
+func countCorrect(obs []Observation) int {
+	n := 0
+	for _, o := range obs {
+		if o.Correct {
+			n++
+		}
+	}
+	return n
+}
+
+// Madde parametrelerini sayısal türevli gradyan yükselişiyle günceller
+func fitItem(start Item, data []itemData, opts CalibrationOptions) Item {
+	params := []float64{math.Log(start.A), start.B, start.C}
+	free := 2
+	if opts.Model == Model3PL {
+		free = 3
+	}
+
+	objective := func(p []float64) float64 {
+		return itemLogPosterior(unpack(p), data, opts)
+	}
+
+	step := 0.1
+	current := objective(params)
+	for i := 0; i < 100 && step > 1e-4; i++ {
+		grad := make([]float64, len(params))
+		norm := 0.0
+		for k := 0; k < free; k++ {
+			const h = 1e-4
+			shifted := append([]float64(nil), params...)
+			shifted[k] += h
+			grad[k] = (objective(shifted) - current) / h
+			norm += grad[k] * grad[k]
+		}
+		norm = math.Sqrt(norm)
+		if norm < 1e-6 {
+			break
+		}
+
+		candidate := append([]float64(nil), params...)
+		for k := 0; k < free; k++ {
+			candidate[k] += step * grad[k] / norm
+		}
+		candidate = bounded(candidate)
+
+		if value := objective(candidate); value > current {
+			params, current = candidate, value
+			step *= 1.2
+		} else {
+			step /= 2
+		}
+	}
+	return unpack(params)
+}
+
+func unpack(p []float64) Item {
+	return Item{A: math.Exp(p[0]), B: p[1], C: p[2]}
+}
+
+func bounded(p []float64) []float64 {
+	p[0] = clamp(p[0], math.Log(minDiscrimination), math.Log(maxDiscrimination))
+	p[1] = clamp(p[1], MinTheta, MaxTheta)
+	p[2] = clamp(p[2], 0, maxGuessing)
+	return p
+}
+
+// Log olabilirlik + ön dağılımlar: log a ~ N(0, 0.5²), b ~ N(0, 2²), c ~ Beta
+func itemLogPosterior(item Item, data []itemData, opts CalibrationOptions) float64 {
+	ll := 0.0
+	for _, d := range data {
+		ll += logLikelihood(item.Probability(d.theta), d.correct)
+	}
+
+	logA := math.Log(item.A)
+	ll += -logA*logA/(2*0.25) - item.B*item.B/(2*4)
+
+	if opts.Model == Model3PL {
+		// Ortalaması GuessingPrior olan, 20 gözleme eşdeğer Beta ön dağılımı
+		alpha := opts.GuessingPrior*20 + 1
+		beta := (1-opts.GuessingPrior)*20 + 1
+		c := clamp(item.C, 1e-6, 1-1e-6)
+		ll += (alpha-1)*math.Log(c) + (beta-1)*math.Log(1-c)
+	}
+	return ll
+}
+
+func standardize(values map[int]float64) {
+	if len(values) < 2 {
+		return
+	}
+	var sum, sumSq float64
+	for _, v := range values {
+		sum += v
+		sumSq += v * v
+	}
+	n := float64(len(values))
+	mean := sum / n
+	sd := math.Sqrt(sumSq/n - mean*mean)
+	if sd < 1e-6 {
+		return
+	}
+	for k, v := range values {
+		values[k] = (v - mean) / sd
+	}
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
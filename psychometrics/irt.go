@@ -0,0 +1,105 @@
+// Package psychometrics madde tepki kuramı (IRT) hesaplamalarını içerir:
+// 2PL/3PL lojistik modeller, madde bilgisi, yetenek (theta) kestirimi ve
+// cevap verisinden madde parametrelerinin kalibrasyonu.
+package psychometrics
+
+import "math"
+
+// Desteklenen modeller
+const (
+	Model2PL = "2pl"
+	Model3PL = "3pl"
+)
+
+// Parametre sınırları; az veride kestirimlerin uç değerlere kaçmasını önler
+const (
+	MinTheta          = -4.0
+	MaxTheta          = 4.0
+	minDiscrimination = 0.2
+	maxDiscrimination = 4.0
+	maxGuessing       = 0.35
+)
+
+// Madde parametreleri: A ayırt edicilik, B zorluk, C şans (tahmin) parametresi
+type Item struct {
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+	C float64 `json:"c"`
+}
+
+// Bir maddeye verilen tek cevap
+type Response struct {
+	Item    Item
+	Correct bool
+}
+
+// θ yeteneğindeki öğrencinin maddeyi doğru cevaplama olasılığı
+func (it Item) Probability(theta float64) float64 {
+	return it.C + (1-it.C)/(1+math.Exp(-it.A*(theta-it.B)))
+}
+
+// Maddenin θ noktasında sağladığı Fisher bilgisi
+func (it Item) Information(theta float64) float64 {
+	p := it.Probability(theta)
+	if p <= 0 || p >= 1 {
+		return 0
+	}
+	r := (p - it.C) / (1 - it.C)
+	return it.A * it.A * r * r * (1 - p) / p
+}
+
+// Kuadratür noktaları: standart normal ön dağılım üzerinde -4..4 aralığı
+var quadrature = func() (points, weights []float64) {
+	const n = 81
+	for i := 0; i < n; i++ {
+		theta := MinTheta + (MaxTheta-MinTheta)*float64(i)/float64(n-1)
+		points = append(points, theta)
+		weights = append(weights, math.Exp(-theta*theta/2))
+	}
+	return points, weights
+}
+
+var quadPoints, quadWeights = quadrature()
+
+// Beklenen sonsal (EAP) yetenek kestirimi ve standart hatası. Standart normal ön
+// dağılım kullanıldığından tümü doğru veya tümü yanlış cevaplarda da sonlu değer döner.
+// Çok sayıda cevapta olabilirlik taşmasın diye ağırlıklar log-sum-exp ile, en büyük
+// log sonsal değeri çıkarılarak hesaplanır.
+func EstimateAbility(responses []Response) (theta, se float64) {
+	logPosterior := make([]float64, len(quadPoints))
+	maxLog := math.Inf(-1)
+	for i, t := range quadPoints {
+		logPosterior[i] = math.Log(quadWeights[i])
+		for _, r := range responses {
+			logPosterior[i] += logLikelihood(r.Item.Probability(t), r.Correct)
+		}
+		maxLog = math.Max(maxLog, logPosterior[i])
+	}
+
+	var sum, sumTheta, sumTheta2 float64
+	for i, t := range quadPoints {
+		w := math.Exp(logPosterior[i] - maxLog)
+		sum += w
+		sumTheta += w * t
+		sumTheta2 += w * t * t
+	}
+	if sum == 0 || math.IsNaN(sum) {
+		return 0, 1
+	}
+
+	theta = sumTheta / sum
+	variance := sumTheta2/sum - theta*theta
+	if variance < 0 {
+		variance = 0
+	}
+	return theta, math.Sqrt(variance)
+}
+
+func logLikelihood(p float64, correct bool) float64 {
+	const eps = 1e-9
+	p = math.Min(math.Max(p, eps), 1-eps)
+	if correct {
+		return math.Log(p)
+	}
+	return math.Log(1 - p)
+}
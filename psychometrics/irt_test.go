@@ -0,0 +1,66 @@
+package psychometrics
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Verilen yetenekteki öğrencinin rastgele maddelere cevaplarını üretir
+func simulateResponses(rng *rand.Rand, theta float64, n int) []Response {
+	responses := make([]Response, n)
+	for i := range responses {
+		item := Item{A: 0.8 + 1.2*rng.Float64(), B: rng.NormFloat64()}
+		responses[i] = Response{Item: item, Correct: rng.Float64() < item.Probability(theta)}
+	}
+	return responses
+}
+
+func TestEstimateAbilityRecoversSimulatedTheta(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+
+	for _, trueTheta := range []float64{-2, -1, 0, 1, 2} {
+		theta, se := EstimateAbility(simulateResponses(rng, trueTheta, 200))
+		if math.Abs(theta-trueTheta) > 0.35 {
+			t.Errorf("θ=%.1f: kestirim %.3f, fark çok büyük", trueTheta, theta)
+		}
+		if se <= 0 || se > 0.3 {
+			t.Errorf("θ=%.1f: standart hata %.3f beklenen aralıkta değil", trueTheta, se)
+		}
+	}
+}
+
+func TestEstimateAbilityLargeResponseCount(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+
+	// Binlerce cevapta olabilirlik çarpımı float64 sınırının altına iner
+	responses := simulateResponses(rng, 1.5, 5000)
+	theta, se := EstimateAbility(responses)
+	if math.IsNaN(theta) || math.IsNaN(se) {
+		t.Fatalf("kestirim NaN: θ=%v se=%v", theta, se)
+	}
+	if math.Abs(theta-1.5) > 0.15 {
+		t.Fatalf("θ = %.3f, beklenen 1.5 civarı", theta)
+	}
+	if se <= 0 || se > 0.1 {
+		t.Fatalf("standart hata = %.4f, beklenen (0, 0.1]", se)
+	}
+}
+
+func TestEstimateAbilityExtremePatterns(t *testing.T) {
+	item := Item{A: 1.5, B: 0}
+	var allCorrect, allWrong []Response
+	for i := 0; i < 2000; i++ {
+		allCorrect = append(allCorrect, Response{Item: item, Correct: true})
+		allWrong = append(allWrong, Response{Item: item, Correct: false})
+	}
+
+	high, _ := EstimateAbility(allCorrect)
+	low, _ := EstimateAbility(allWrong)
+	if !(high > 3 && high <= MaxTheta) {
+		t.Fatalf("tümü doğru: θ = %.3f, üst sınıra yakın olmalı", high)
+	}
+	if !(low < -3 && low >= MinTheta) {
+		t.Fatalf("tümü yanlış: θ = %.3f, alt sınıra yakın olmalı", low)
+	}
+}
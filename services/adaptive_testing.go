@@ -0,0 +1,397 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"sort"
+	"strings"
+
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/psychometrics"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	defaultAdaptiveMaxQuestions = 30
+	maxAdaptiveQuestions        = 100
+	defaultAdaptiveSEThreshold  = 0.3
+	// Maruz kalma kontrolü: en bilgilendirici bu kadar soru arasından rastgele seçilir
+	adaptiveRandomesqueSize = 5
+)
+
+var (
+	ErrAdaptiveSessionNotFound  = errors.New("uyarlanabilir test oturumu bulunamadı")
+	ErrAdaptiveSessionFinished  = errors.New("uyarlanabilir test oturumu tamamlanmış")
+	ErrAdaptiveQuestionMismatch = errors.New("cevaplanan soru oturumdaki güncel soru değil")
+	ErrAdaptiveSEThreshold      = errors.New("se_threshold 0.1 ile 1 arasında olmalı")
+)
+
+// Aday havuzu GetQuestions filtreleriyle seçilir; öğrenci modunda olduğu için
+// yalnızca yayındaki ve kalibre edilmiş sorular kullanılır
+var adaptiveFilterKeys = []string{"sub_category_id", "category_id", "difficulty", "search", "node_id"}
+
+func AdaptiveFilters(query url.Values) map[string]string {
+	filters := make(map[string]string)
+	for _, key := range adaptiveFilterKeys {
+		if v := query.Get(key); v != "" {
+			filters[key] = v
+		}
+	}
+	return filters
+}
+
+// Yeni oturum açar ve ilk soruyu seçer
+func StartAdaptiveSession(ctx context.Context, userID int, filters map[string]string, req models.AdaptiveSessionRequest) (*models.AdaptiveSession, error) {
+	if req.MaxQuestions <= 0 {
+		req.MaxQuestions = defaultAdaptiveMaxQuestions
+	}
+	if req.MaxQuestions > maxAdaptiveQuestions {
+		req.MaxQuestions = maxAdaptiveQuestions
+	}
+	if req.SEThreshold <= 0 {
+		req.SEThreshold = defaultAdaptiveSEThreshold
+	}
+	if req.SEThreshold < 0.1 || req.SEThreshold > 1 {
+		return nil, ErrAdaptiveSEThreshold
+	}
+
+	filtersJSON, err := json.Marshal(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.GetPool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int64
+	if err := tx.QueryRow(ctx, `
+        INSERT INTO adaptive_sessions (user_id, filters, max_questions, se_threshold)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id`,
+		userID, filtersJSON, req.MaxQuestions, req.SEThreshold).Scan(&id); err != nil {
+		return nil, err
+	}
+
+	session, err := loadAdaptiveSession(ctx, tx, id, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := advanceAdaptiveSession(ctx, tx, session); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return GetAdaptiveSession(ctx, id, userID)
+}
+
+// Güncel sorunun cevabını kaydeder, yetenek kestirimini günceller ve durma
+// koşulu sağlanmadıysa sıradaki soruyu seçer
+func AnswerAdaptiveQuestion(ctx context.Context, userID int, sessionID int64, req models.AdaptiveAnswerRequest) (*models.AdaptiveAnswerResult, error) {
+	tx, err := db.GetPool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	session, err := loadAdaptiveSession(ctx, tx, sessionID, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.AdaptiveSessionActive {
+		return nil, ErrAdaptiveSessionFinished
+	}
+	if session.CurrentQuestionID == nil || *session.CurrentQuestionID != req.QuestionID {
+		return nil, ErrAdaptiveQuestionMismatch
+	}
+
+	// Soru seçildikten sonra başlatılan bir sınavda yer alıyorsa cevap ve çözüm sızdırılmaz
+	locked, err := OngoingExamQuestionIDs(ctx, tx, userID, []int{req.QuestionID})
+	if err != nil {
+		return nil, err
+	}
+	if locked[req.QuestionID] {
+		return nil, ErrExamQuestionLocked
+	}
+
+	attempt, err := RecordQuestionAttempt(ctx, tx, userID, req.QuestionID, false, models.QuestionAttemptRequest{
+		Answer:      req.Answer,
+		TimeSpentMS: req.TimeSpentMS,
+		Source:      models.AttemptSourceAdaptive,
+		SessionID:   &sessionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := &models.AdaptiveAnswerResult{
+		Correct:       attempt.Attempt.IsCorrect,
+		CorrectAnswer: attempt.CorrectAnswer,
+		SolutionURL:   attempt.SolutionURL,
+	}
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE adaptive_session_items SET is_correct = $1, answered_at = CURRENT_TIMESTAMP
+        WHERE session_id = $2 AND question_id = $3`,
+		result.Correct, sessionID, req.QuestionID); err != nil {
+		return nil, err
+	}
+
+	// Yetenek, oturumdaki tüm cevaplardan EAP ile yeniden kestirilir
+	rows, err := tx.Query(ctx, `
+        SELECT i.is_correct, q.irt_a, q.irt_b, q.irt_c
+        FROM adaptive_session_items i
+        JOIN questions q ON q.id = i.question_id
+        WHERE i.session_id = $1 AND i.is_correct IS NOT NULL AND q.irt_model IS NOT NULL`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	var responses []psychometrics.Response
+	for rows.Next() {
+		var r psychometrics.Response
+		if err := rows.Scan(&r.Correct, &r.Item.A, &r.Item.B, &r.Item.C); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		responses = append(responses, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	session.Theta, session.StandardError = psychometrics.EstimateAbility(responses)
+	session.Answered = len(responses)
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE adaptive_session_items SET theta_after = $1, se_after = $2
+        WHERE session_id = $3 AND question_id = $4`,
+		session.Theta, session.StandardError, sessionID, req.QuestionID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `
+        UPDATE adaptive_sessions SET theta = $1, standard_error = $2, current_question_id = NULL
+        WHERE id = $3`,
+		session.Theta, session.StandardError, sessionID); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case session.StandardError < session.SEThreshold:
+		err = finishAdaptiveSession(ctx, tx, sessionID, models.AdaptiveStopSEThreshold)
+	case session.Answered >= session.MaxQuestions:
+		err = finishAdaptiveSession(ctx, tx, sessionID, models.AdaptiveStopMaxQuestions)
+	default:
+		err = advanceAdaptiveSession(ctx, tx, session)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	RecordQuestionEvent(UserEventActor(userID), req.QuestionID, models.QuestionEventAttempt)
+
+	result.Session, err = GetAdaptiveSession(ctx, sessionID, userID)
+	return result, err
+}
+
+// Oturumu soru geçmişi (yetenek izi) ile birlikte döner
+func GetAdaptiveSession(ctx context.Context, sessionID int64, userID int) (*models.AdaptiveSession, error) {
+	pool := db.GetPool()
+	session, err := loadAdaptiveSession(ctx, pool, sessionID, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pool.Query(ctx, `
+        SELECT position, question_id, content_area, information, is_correct,
+               theta_after, se_after, selected_at, answered_at
+        FROM adaptive_session_items
+        WHERE session_id = $1
+        ORDER BY position`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	session.Trajectory = []models.AdaptiveStep{}
+	for rows.Next() {
+		var step models.AdaptiveStep
+		if err := rows.Scan(&step.Position, &step.QuestionID, &step.ContentArea, &step.Information,
+			&step.IsCorrect, &step.ThetaAfter, &step.SEAfter, &step.SelectedAt, &step.AnsweredAt); err != nil {
+			return nil, err
+		}
+		session.Trajectory = append(session.Trajectory, step)
+	}
+	return session, rows.Err()
+}
+
+func loadAdaptiveSession(ctx context.Context, q DBTX, sessionID int64, userID int, forUpdate bool) (*models.AdaptiveSession, error) {
+	query := `
+        SELECT s.id, s.user_id, s.status, s.filters, s.max_questions, s.se_threshold,
+               s.theta, s.standard_error, s.stop_reason, s.current_question_id,
+               (SELECT COUNT(*) FROM adaptive_session_items i
+                WHERE i.session_id = s.id AND i.is_correct IS NOT NULL),
+               s.created_at, s.finished_at
+        FROM adaptive_sessions s
+        WHERE s.id = $1 AND s.user_id = $2`
+	if forUpdate {
+		query += " FOR UPDATE OF s"
+	}
+
+	var s models.AdaptiveSession
+	var filters []byte
+	err := q.QueryRow(ctx, query, sessionID, userID).Scan(&s.ID, &s.UserID, &s.Status, &filters,
+		&s.MaxQuestions, &s.SEThreshold, &s.Theta, &s.StandardError, &s.StopReason,
+		&s.CurrentQuestionID, &s.Answered, &s.CreatedAt, &s.FinishedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAdaptiveSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filters, &s.Filters); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func finishAdaptiveSession(ctx context.Context, q DBTX, sessionID int64, reason string) error {
+	_, err := q.Exec(ctx, `
+        UPDATE adaptive_sessions
+        SET status = 'completed', stop_reason = $1, current_question_id = NULL, finished_at = CURRENT_TIMESTAMP
+        WHERE id = $2`, reason, sessionID)
+	return err
+}
+
+type adaptiveCandidate struct {
+	questionID  int
+	area        int
+	information float64
+}
+
+// Sıradaki soruyu seçer; aday kalmadıysa oturumu bitirir
+func advanceAdaptiveSession(ctx context.Context, tx pgx.Tx, s *models.AdaptiveSession) error {
+	query := make(url.Values)
+	for k, v := range s.Filters {
+		query.Set(k, v)
+	}
+	conditions, args := QuestionFilterConditions(query, false)
+	args = append(args, s.ID)
+	sessionArg := len(args)
+	args = append(args, s.UserID, models.ExamStatusActive, models.ExamStatusPaused)
+	examArg := len(args) - 2
+
+	sql := fmt.Sprintf(`
+        SELECT q.id, q.irt_a, q.irt_b, q.irt_c,
+               COALESCE((SELECT MIN(c.sub_category_id)
+                         FROM question_categories qc
+                         JOIN categories c ON c.id = qc.category_id AND c.deleted_at IS NULL
+                         WHERE qc.question_id = q.id), 0),
+               EXISTS(SELECT 1 FROM adaptive_session_items i WHERE i.session_id = $%d AND i.question_id = q.id)
+        FROM questions q
+        WHERE q.deleted_at IS NULL AND q.irt_model IS NOT NULL
+          -- Devam eden sınavdaki sorular cevapları gösterileceği için havuza alınmaz
+          AND NOT EXISTS (
+              SELECT 1
+              FROM exam_session_items ei
+              JOIN exam_sessions es ON es.id = ei.session_id
+              WHERE ei.question_id = q.id AND es.user_id = $%d AND es.status IN ($%d, $%d)
+          )`, sessionArg, examArg, examArg+1, examArg+2)
+	if len(conditions) > 0 {
+		sql += " AND " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	// Havuzdaki alan büyüklükleri hedef oranları, sorulanlar ise mevcut dağılımı verir
+	poolSize := make(map[int]int)
+	administered := make(map[int]int)
+	var candidates []adaptiveCandidate
+	total, asked := 0, 0
+	for rows.Next() {
+		var c adaptiveCandidate
+		var item psychometrics.Item
+		var used bool
+		if err := rows.Scan(&c.questionID, &item.A, &item.B, &item.C, &c.area, &used); err != nil {
+			rows.Close()
+			return err
+		}
+		poolSize[c.area]++
+		total++
+		if used {
+			administered[c.area]++
+			asked++
+			continue
+		}
+		c.information = item.Information(s.Theta)
+		candidates = append(candidates, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(candidates) == 0 {
+		return finishAdaptiveSession(ctx, tx, s.ID, models.AdaptiveStopPoolExhausted)
+	}
+
+	chosen := selectAdaptiveCandidate(candidates, poolSize, administered, total, asked)
+
+	var position int
+	if err := tx.QueryRow(ctx,
+		"SELECT COALESCE(MAX(position), 0) + 1 FROM adaptive_session_items WHERE session_id = $1",
+		s.ID).Scan(&position); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+        INSERT INTO adaptive_session_items (session_id, position, question_id, content_area, information)
+        VALUES ($1, $2, $3, $4, $5)`,
+		s.ID, position, chosen.questionID, chosen.area, chosen.information); err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx,
+		"UPDATE adaptive_sessions SET current_question_id = $1 WHERE id = $2",
+		chosen.questionID, s.ID)
+	return err
+}
+
+// İçerik dengelemesi: havuzdaki payına göre en çok geride kalan alan seçilir.
+// Maruz kalma kontrolü: o alandaki en bilgilendirici birkaç soru arasından rastgele seçilir.
+func selectAdaptiveCandidate(candidates []adaptiveCandidate, poolSize, administered map[int]int, total, asked int) adaptiveCandidate {
+	byArea := make(map[int][]adaptiveCandidate)
+	for _, c := range candidates {
+		byArea[c.area] = append(byArea[c.area], c)
+	}
+
+	bestArea, bestDeficit := 0, 0.0
+	first := true
+	for area, list := range byArea {
+		target := float64(poolSize[area]) / float64(total) * float64(asked+1)
+		deficit := target - float64(administered[area])
+		if first || deficit > bestDeficit || (deficit == bestDeficit && len(list) > len(byArea[bestArea])) {
+			bestArea, bestDeficit, first = area, deficit, false
+		}
+	}
+
+	list := byArea[bestArea]
+	sort.Slice(list, func(i, j int) bool { return list[i].information > list[j].information })
+	if len(list) > adaptiveRandomesqueSize {
+		list = list[:adaptiveRandomesqueSize]
+	}
+	return list[rand.IntN(len(list))]
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"osymapp/models"
+)
+
+var ErrPracticeSetNotFound = errors.New("tekrar seti bulunamadı")
+
+// Cevabı değerlendirir ve geçmişe kaydeder. Yanlış cevaplar yanlışlar defterine
+// eklenir (öğrenildi olarak işaretlenmişse yeniden açılır), sonuç aralıklı tekrar
+// kartına ve konu istatistiklerine işlenir; tekrar setinden gelen cevaplar setteki
+// soruyu da cevaplanmış olarak işaretler. Soru yoksa, çöp kutusundaysa veya editör
+// olmayan kullanıcı için yayında değilse pgx.ErrNoRows döner; böylece taslakların
+// cevap anahtarı sızmaz ve istatistiklere yalnızca yayındaki sorular işlenir.
+func RecordQuestionAttempt(ctx context.Context, q DBTX, userID, questionID int, editor bool, req models.QuestionAttemptRequest) (*models.QuestionAttemptResult, error) {
+	result := &models.QuestionAttemptResult{}
+	if err := q.QueryRow(ctx, `
+        SELECT COALESCE(answer, ''), COALESCE(solution_url, '')
+        FROM questions
+        WHERE id = $1 AND deleted_at IS NULL AND ($2 OR status = $3)`,
+		questionID, editor, models.QuestionStatusPublished).Scan(&result.CorrectAnswer, &result.SolutionURL); err != nil {
+		return nil, err
+	}
+
+	if req.Source == "" {
+		req.Source = models.AttemptSourcePractice
+	}
+	chosen := strings.TrimSpace(req.Answer)
+	correct := strings.EqualFold(chosen, strings.TrimSpace(result.CorrectAnswer))
+
+	if req.Source == models.AttemptSourceMistakes && req.SessionID != nil {
+		tag, err := q.Exec(ctx, `
+            UPDATE practice_set_items i SET is_correct = $1, answered_at = CURRENT_TIMESTAMP
+            FROM practice_sets s
+            WHERE s.id = i.set_id AND s.id = $2 AND s.user_id = $3 AND i.question_id = $4`,
+			correct, *req.SessionID, userID, questionID)
+		if err != nil {
+			return nil, err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil, ErrPracticeSetNotFound
+		}
+	}
+
+	a := &result.Attempt
+	if err := q.QueryRow(ctx, `
+        INSERT INTO question_attempts (user_id, question_id, is_correct, chosen_answer, time_spent_ms, source, session_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id, user_id, question_id, chosen_answer, is_correct, time_spent_ms, source, session_id, created_at`,
+		userID, questionID, correct, chosen, req.TimeSpentMS, req.Source, req.SessionID).Scan(
+		&a.ID, &a.UserID, &a.QuestionID, &a.ChosenAnswer, &a.IsCorrect,
+		&a.TimeSpentMS, &a.Source, &a.SessionID, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if !correct {
+		if _, err := q.Exec(ctx, `
+            INSERT INTO user_mistakes (user_id, question_id, last_answer)
+            VALUES ($1, $2, $3)
+            ON CONFLICT (user_id, question_id) DO UPDATE
+            SET wrong_count = user_mistakes.wrong_count + 1,
+                last_answer = EXCLUDED.last_answer,
+                last_wrong_at = CURRENT_TIMESTAMP,
+                mastered_at = NULL`,
+			userID, questionID, chosen); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := UpdateReviewCard(ctx, q, userID, questionID, ReviewGrade(correct, req.TimeSpentMS),
+		req.Source == models.AttemptSourceReview); err != nil {
+		return nil, err
+	}
+	if err := recordAttemptStats(ctx, q, userID, questionID, correct, req.TimeSpentMS); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"osymapp/db"
+)
+
+// Silinen kategorilerin geri yüklenebileceği süre
+const CategoryRetention = 30 * 24 * time.Hour
+
+// Geri yükleme süresi dolan kategori düğümlerini ve eski tablolardaki karşılıklarını
+// kalıcı olarak siler. Alt öğeler ve soru ilişkileri veritabanındaki cascade ile silinir.
+func PurgeDeletedCategories(ctx context.Context) (int64, error) {
+	tx, err := db.GetPool().Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("transaction başlatma hatası: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	cutoff := time.Now().Add(-CategoryRetention)
+	var purged int64
+	for _, query := range []string{
+		"DELETE FROM category_tree WHERE deleted_at < $1",
+		"DELETE FROM categories WHERE deleted_at < $1",
+		`DELETE FROM main_category_sub_category
+         WHERE sub_category_id IN (SELECT id FROM sub_categories WHERE deleted_at < $1)`,
+		"DELETE FROM sub_categories WHERE deleted_at < $1",
+		"DELETE FROM main_categories WHERE deleted_at < $1",
+	} {
+		result, err := tx.Exec(ctx, query, cutoff)
+		if err != nil {
+			return 0, fmt.Errorf("silinen kategoriler temizlenemedi: %v", err)
+		}
+		purged += result.RowsAffected()
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("transaction commit hatası: %v", err)
+	}
+	return purged, nil
+}
+
+// Süresi dolan kategorileri arka planda periyodik olarak temizler
+func StartCategoryPurge(interval time.Duration) {
+	go func() {
+		for {
+			time.Sleep(interval)
+
+			purged, err := PurgeDeletedCategories(context.Background())
+			if err != nil {
+				log.Printf("Kategori temizleme hatası: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Kategori temizleme: %d kayıt kalıcı olarak silindi", purged)
+			}
+		}
+	}()
+}
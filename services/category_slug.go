@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"osymapp/db"
+	"osymapp/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const maxSlugLength = 200
+
+var ErrCategorySlugNotFound = errors.New("kategori bulunamadı")
+
+var turkishSlugReplacer = strings.NewReplacer(
+	"ç", "c", "Ç", "c",
+	"ğ", "g", "Ğ", "g",
+	"ı", "i", "I", "i", "İ", "i",
+	"ö", "o", "Ö", "o",
+	"ş", "s", "Ş", "s",
+	"ü", "u", "Ü", "u",
+	"â", "a", "Â", "a",
+	"î", "i", "Î", "i",
+	"û", "u", "Û", "u",
+)
+
+// Türkçe karakterleri ASCII karşılıklarına çevirip URL'de kullanılabilir slug üretir.
+// Örn: "Türkçe Dil Bilgisi" -> "turkce-dil-bilgisi"
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(turkishSlugReplacer.Replace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimSuffix(slug[:maxSlugLength], "-")
+	}
+	if slug == "" {
+		slug = "kategori"
+	}
+	return slug
+}
+
+func categoryLevelTable(level string) (string, error) {
+	switch level {
+	case "main":
+		return "main_categories", nil
+	case "sub":
+		return "sub_categories", nil
+	case "category":
+		return "categories", nil
+	}
+	return "", fmt.Errorf("geçersiz kategori seviyesi: %s", level)
+}
+
+// Aynı ebeveyn altındaki kardeşlerde (silinmişler dahil) slug kullanılıyor mu
+func categorySlugTaken(ctx context.Context, q DBTX, level string, id int, slug string) (bool, error) {
+	var query string
+	switch level {
+	case "main":
+		query = "SELECT EXISTS(SELECT 1 FROM main_categories WHERE slug = $1 AND id <> $2)"
+	case "sub":
+		query = `SELECT EXISTS(
+            SELECT 1
+            FROM sub_categories sc
+            JOIN main_category_sub_category mcsc ON mcsc.sub_category_id = sc.id
+            WHERE sc.slug = $1 AND sc.id <> $2
+              AND mcsc.main_category_id IN (
+                  SELECT main_category_id FROM main_category_sub_category WHERE sub_category_id = $2
+              ))`
+	case "category":
+		query = `SELECT EXISTS(
+            SELECT 1 FROM categories
+            WHERE slug = $1 AND id <> $2
+              AND sub_category_id = (SELECT sub_category_id FROM categories WHERE id = $2))`
+	default:
+		return false, fmt.Errorf("geçersiz kategori seviyesi: %s", level)
+	}
+
+	var taken bool
+	err := q.QueryRow(ctx, query, slug, id).Scan(&taken)
+	return taken, err
+}
+
+// Mevcut slug adından türetilmişse ("ad" veya "ad-2") korunur
+func slugMatchesBase(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(slug, base+"-")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// Düğümün slug'ını adından üretir ve kardeşleri arasında benzersiz hale getirir.
+// Slug değişirse eskisi yönlendirme tablosuna yazılır.
+func SetCategorySlug(ctx context.Context, q DBTX, level string, id int) (string, error) {
+	table, err := categoryLevelTable(level)
+	if err != nil {
+		return "", err
+	}
+
+	var name string
+	var current *string
+	err = q.QueryRow(ctx, fmt.Sprintf("SELECT name, slug FROM %s WHERE id = $1", table), id).Scan(&name, &current)
+	if err != nil {
+		return "", err
+	}
+
+	base := Slugify(name)
+	if current != nil && slugMatchesBase(*current, base) {
+		taken, err := categorySlugTaken(ctx, q, level, id, *current)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return *current, nil
+		}
+	}
+
+	slug := base
+	for n := 2; ; n++ {
+		taken, err := categorySlugTaken(ctx, q, level, id, slug)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			break
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	if _, err := q.Exec(ctx, fmt.Sprintf("UPDATE %s SET slug = $1 WHERE id = $2", table), slug, id); err != nil {
+		return "", err
+	}
+
+	if current != nil && *current != slug {
+		var redirectQuery string
+		switch level {
+		case "main":
+			redirectQuery = "INSERT INTO category_slug_redirects (level, parent_id, slug, node_id) VALUES ('main', 0, $1, $2)"
+		case "sub":
+			redirectQuery = `INSERT INTO category_slug_redirects (level, parent_id, slug, node_id)
+                SELECT 'sub', main_category_id, $1, $2 FROM main_category_sub_category WHERE sub_category_id = $2`
+		case "category":
+			redirectQuery = `INSERT INTO category_slug_redirects (level, parent_id, slug, node_id)
+                SELECT 'category', sub_category_id, $1, $2 FROM categories WHERE id = $2`
+		}
+		if _, err := q.Exec(ctx, redirectQuery, *current, id); err != nil {
+			return "", err
+		}
+	}
+
+	return slug, nil
+}
+
+// Taşınan veya birleştirilen düğümün eski adresini yeni düğüme yönlendirir
+func RecordCategorySlugRedirect(ctx context.Context, q DBTX, level string, parentID int, slug string, nodeID int) error {
+	_, err := q.Exec(ctx,
+		"INSERT INTO category_slug_redirects (level, parent_id, slug, node_id) VALUES ($1, $2, $3, $4)",
+		level, parentID, slug, nodeID)
+	return err
+}
+
+// Slug'ı olmayan tüm düğümler için slug üretir
+func EnsureCategorySlugs(ctx context.Context) (int, error) {
+	pool := db.GetPool()
+	assigned := 0
+
+	for _, level := range []string{"main", "sub", "category"} {
+		table, _ := categoryLevelTable(level)
+		rows, err := pool.Query(ctx, fmt.Sprintf("SELECT id FROM %s WHERE slug IS NULL ORDER BY id", table))
+		if err != nil {
+			return assigned, err
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+		if err != nil {
+			return assigned, err
+		}
+
+		for _, id := range ids {
+			if _, err := SetCategorySlug(ctx, pool, level, id); err != nil {
+				return assigned, fmt.Errorf("%s %d için slug üretilemedi: %v", table, id, err)
+			}
+			assigned++
+		}
+	}
+
+	return assigned, nil
+}
+
+// Slug yolunu (ana/alt/kategori) düğümlere çözer. Eski slug'lar veya taşınmış
+// düğümler için redirected true döner; path her zaman güncel adresi içerir.
+func ResolveCategorySlugPath(ctx context.Context, segments []string) (path []models.CategoryRef, redirected bool, err error) {
+	if len(segments) == 0 || len(segments) > 3 {
+		return nil, false, ErrCategorySlugNotFound
+	}
+
+	pool := db.GetPool()
+	levels := []string{"main", "sub", "category"}
+	parentID := 0
+
+	for i, segment := range segments {
+		level := levels[i]
+		segment = strings.ToLower(segment)
+
+		ref, err := findCategoryBySlug(ctx, pool, level, parentID, segment)
+		if errors.Is(err, pgx.ErrNoRows) {
+			ref, err = findCategoryBySlugRedirect(ctx, pool, level, parentID, segment)
+			redirected = true
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, ErrCategorySlugNotFound
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		path = append(path, ref)
+		parentID = ref.ID
+	}
+
+	if redirected {
+		// Yönlendirilen düğüm başka bir ebeveyne taşınmış olabilir; yolu yeniden kur
+		if path, err = canonicalCategoryPath(ctx, pool, path); err != nil {
+			return nil, false, err
+		}
+	}
+	for i, ref := range path {
+		if ref.Slug != segments[i] {
+			redirected = true
+		}
+	}
+
+	return path, redirected, nil
+}
+
+func findCategoryBySlug(ctx context.Context, q DBTX, level string, parentID int, slug string) (models.CategoryRef, error) {
+	var ref models.CategoryRef
+	var err error
+	switch level {
+	case "main":
+		err = q.QueryRow(ctx,
+			"SELECT id, name, slug FROM main_categories WHERE slug = $1 AND deleted_at IS NULL",
+			slug).Scan(&ref.ID, &ref.Name, &ref.Slug)
+	case "sub":
+		err = q.QueryRow(ctx,
+			`SELECT sc.id, sc.name, sc.slug
+             FROM sub_categories sc
+             JOIN main_category_sub_category mcsc ON mcsc.sub_category_id = sc.id
+             WHERE mcsc.main_category_id = $1 AND sc.slug = $2 AND sc.deleted_at IS NULL`,
+			parentID, slug).Scan(&ref.ID, &ref.Name, &ref.Slug)
+	case "category":
+		err = q.QueryRow(ctx,
+			"SELECT id, name, slug FROM categories WHERE sub_category_id = $1 AND slug = $2 AND deleted_at IS NULL",
+			parentID, slug).Scan(&ref.ID, &ref.Name, &ref.Slug)
+	}
+	return ref, err
+}
+
+func findCategoryBySlugRedirect(ctx context.Context, q DBTX, level string, parentID int, slug string) (models.CategoryRef, error) {
+	table, err := categoryLevelTable(level)
+	if err != nil {
+		return models.CategoryRef{}, err
+	}
+
+	var ref models.CategoryRef
+	err = q.QueryRow(ctx, fmt.Sprintf(`
+        SELECT t.id, t.name, t.slug
+        FROM category_slug_redirects r
+        JOIN %s t ON t.id = r.node_id AND t.deleted_at IS NULL
+        WHERE r.level = $1 AND r.parent_id = $2 AND r.slug = $3
+        ORDER BY r.created_at DESC, r.id DESC
+        LIMIT 1`, table),
+		level, parentID, slug).Scan(&ref.ID, &ref.Name, &ref.Slug)
+	return ref, err
+}
+
+// En derindeki düğümden yukarı doğru güncel ebeveynleri bulur
+func canonicalCategoryPath(ctx context.Context, q DBTX, path []models.CategoryRef) ([]models.CategoryRef, error) {
+	canonical := make([]models.CategoryRef, len(path))
+	copy(canonical, path)
+
+	if len(path) == 3 {
+		sub := &canonical[1]
+		err := q.QueryRow(ctx,
+			`SELECT sc.id, sc.name, sc.slug
+             FROM categories c
+             JOIN sub_categories sc ON sc.id = c.sub_category_id
+             WHERE c.id = $1`,
+			path[2].ID).Scan(&sub.ID, &sub.Name, &sub.Slug)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(path) >= 2 {
+		// Alt kategori istenen ana kategoriye bağlı değilse ilk bağlı olana yönlendir
+		main := &canonical[0]
+		err := q.QueryRow(ctx,
+			`SELECT mc.id, mc.name, mc.slug
+             FROM main_category_sub_category mcsc
+             JOIN main_categories mc ON mc.id = mcsc.main_category_id AND mc.deleted_at IS NULL
+             WHERE mcsc.sub_category_id = $1
+             ORDER BY mc.id = $2 DESC, mc.id
+             LIMIT 1`,
+			canonical[1].ID, path[0].ID).Scan(&main.ID, &main.Name, &main.Slug)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return canonical, nil
+}
@@ -0,0 +1,756 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"osymapp/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Kategori ağacı kategorilerin asıl kaynağıdır. Ağacın ilk üç seviyesi eski tablolara
+// (main_categories > sub_categories > categories) yansıtılır; okuma sorguları ve slug'lar
+// onları kullanmaya devam eder. Yansıtma yalnızca yazılan düğümler için yapılır.
+
+var (
+	ErrCategoryNodeNotFound   = errors.New("kategori düğümü bulunamadı")
+	ErrCategoryParentNotFound = errors.New("üst düğüm bulunamadı")
+	ErrCategoryNodeCycle      = errors.New("düğüm kendi alt ağacına taşınamaz")
+	ErrCategoryNodeOrder      = errors.New("sıralama tüm kardeşleri tam olarak bir kez içermelidir")
+	ErrCategoryNodeNotDeleted = errors.New("kategori düğümü silinmemiş")
+	ErrCategoryParentDeleted  = errors.New("önce üst düğüm geri yüklenmelidir")
+	ErrCategoryRestoreExpired = errors.New("geri yükleme süresi dolmuş")
+)
+
+// Düğümün derinliğine karşılık gelen eski seviye; daha derin düğümlerin karşılığı yoktur
+func legacyLevelForDepth(depth int) string {
+	switch depth {
+	case 0:
+		return "main"
+	case 1:
+		return "sub"
+	case 2:
+		return "category"
+	}
+	return ""
+}
+
+// Eski tablodaki kaydın ağaçtaki düğümünü döndürür
+func CategoryNodeForLegacy(ctx context.Context, q DBTX, level string, id int) (int, error) {
+	var nodeID int
+	err := q.QueryRow(ctx,
+		"SELECT id FROM category_tree WHERE legacy_level = $1 AND legacy_id = $2",
+		level, id).Scan(&nodeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrCategoryNodeNotFound
+	}
+	return nodeID, err
+}
+
+// Eski kayıtların düğümlerini aynı sırayla döndürür
+func CategoryNodesForLegacy(ctx context.Context, q DBTX, level string, ids []int) ([]int, error) {
+	nodes := make([]int, 0, len(ids))
+	for _, id := range ids {
+		nodeID, err := CategoryNodeForLegacy(ctx, q, level, id)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, nodeID)
+	}
+	return nodes, nil
+}
+
+// Düğümün eski tablodaki kaydının ID'si; karşılığı yoksa 0
+func CategoryNodeLegacyID(ctx context.Context, q DBTX, nodeID int) (int, error) {
+	var legacyID *int
+	err := q.QueryRow(ctx, "SELECT legacy_id FROM category_tree WHERE id = $1", nodeID).Scan(&legacyID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrCategoryNodeNotFound
+	}
+	if err != nil || legacyID == nil {
+		return 0, err
+	}
+	return *legacyID, nil
+}
+
+// Düğümün eski tablodaki kaydının slug'ı
+func LegacyCategorySlug(ctx context.Context, q DBTX, nodeID int) (string, error) {
+	var level *string
+	var legacyID *int
+	err := q.QueryRow(ctx, "SELECT legacy_level, legacy_id FROM category_tree WHERE id = $1", nodeID).Scan(&level, &legacyID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && level == nil) {
+		return "", ErrCategoryNodeNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	table, err := categoryLevelTable(*level)
+	if err != nil {
+		return "", err
+	}
+	var slug string
+	err = q.QueryRow(ctx, fmt.Sprintf("SELECT slug FROM %s WHERE id = $1", table), *legacyID).Scan(&slug)
+	return slug, err
+}
+
+// Ebeveynin sonuna yeni düğüm ekler
+func CreateCategoryNode(ctx context.Context, q DBTX, parentID *int, name string) (*models.CategoryNode, error) {
+	// Ebeveyn kilitlenir; eşzamanlı eklemeler aynı sırayı almaz ve ebeveyn bu sırada silinemez
+	if parentID != nil {
+		var exists bool
+		err := q.QueryRow(ctx,
+			"SELECT true FROM category_tree WHERE id = $1 AND deleted_at IS NULL FOR UPDATE",
+			*parentID).Scan(&exists)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCategoryParentNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	node := &models.CategoryNode{ParentID: parentID, Name: name}
+	err := q.QueryRow(ctx,
+		`INSERT INTO category_tree (parent_id, name, position)
+         VALUES ($1, $2, (SELECT COALESCE(MAX(position), 0) + 1 FROM category_tree WHERE parent_id IS NOT DISTINCT FROM $1))
+         RETURNING id, position`,
+		parentID, name).Scan(&node.ID, &node.Position)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := projectCategoryNodes(ctx, q, []int{node.ID}); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// Canlı düğümün adını değiştirir
+func RenameCategoryNode(ctx context.Context, q DBTX, nodeID int, name string) error {
+	result, err := q.Exec(ctx,
+		"UPDATE category_tree SET name = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 AND deleted_at IS NULL",
+		name, nodeID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrCategoryNodeNotFound
+	}
+	return projectCategoryNodes(ctx, q, []int{nodeID})
+}
+
+// Düğümü alt ağacıyla birlikte yeni ebeveynin sonuna taşır (parentID nil ise kök olur)
+func MoveCategoryNode(ctx context.Context, q DBTX, nodeID int, parentID *int) error {
+	var exists bool
+	err := q.QueryRow(ctx,
+		"SELECT true FROM category_tree WHERE id = $1 AND deleted_at IS NULL FOR UPDATE",
+		nodeID).Scan(&exists)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCategoryNodeNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if parentID != nil {
+		// Yeni ebeveyn ve ataları kilitlenir; eşzamanlı iki taşıma birbirinin altına
+		// geçerek döngü oluşturamaz
+		rows, err := q.Query(ctx, `
+            SELECT id, deleted_at IS NULL FROM category_tree
+            WHERE id IN (
+                WITH RECURSIVE Ancestors AS (
+                    SELECT id, parent_id FROM category_tree WHERE id = $1
+                    UNION ALL
+                    SELECT c.id, c.parent_id FROM category_tree c JOIN Ancestors a ON c.id = a.parent_id
+                )
+                SELECT id FROM Ancestors
+            )
+            ORDER BY id
+            FOR UPDATE`, *parentID)
+		if err != nil {
+			return err
+		}
+		parentLive := false
+		cycle := false
+		for rows.Next() {
+			var id int
+			var live bool
+			if err := rows.Scan(&id, &live); err != nil {
+				rows.Close()
+				return err
+			}
+			if id == *parentID {
+				parentLive = live
+			}
+			if id == nodeID {
+				cycle = true
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if cycle {
+			return ErrCategoryNodeCycle
+		}
+		if !parentLive {
+			return ErrCategoryParentNotFound
+		}
+	}
+
+	_, err = q.Exec(ctx,
+		`UPDATE category_tree
+         SET parent_id = $1,
+             position = (SELECT COALESCE(MAX(position), 0) + 1 FROM category_tree WHERE parent_id IS NOT DISTINCT FROM $1),
+             updated_at = CURRENT_TIMESTAMP
+         WHERE id = $2`,
+		parentID, nodeID)
+	if err != nil {
+		return err
+	}
+
+	// Alt ağaçtaki düğümlerin seviyesi değişmiş olabilir
+	subtree, err := categorySubtreeIDs(ctx, q, nodeID)
+	if err != nil {
+		return err
+	}
+	if err := projectCategoryNodes(ctx, q, subtree); err != nil {
+		return err
+	}
+
+	questionIDs, err := questionsLinkedToNodes(ctx, q, subtree)
+	if err != nil {
+		return err
+	}
+	return SyncQuestionCategories(ctx, q, questionIDs)
+}
+
+// Ebeveyn altındaki canlı kardeşlerin sırasını verilen ID listesine göre günceller.
+// Liste tüm kardeşleri tam olarak bir kez içermelidir.
+func ReorderCategoryNodes(ctx context.Context, q DBTX, parentID *int, ids []int) error {
+	rows, err := q.Query(ctx,
+		"SELECT id FROM category_tree WHERE parent_id IS NOT DISTINCT FROM $1 AND deleted_at IS NULL FOR UPDATE",
+		parentID)
+	if err != nil {
+		return err
+	}
+	current, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return err
+	}
+
+	if len(current) == 0 {
+		return ErrCategoryNodeNotFound
+	}
+	if !sameIDSet(current, ids) {
+		return ErrCategoryNodeOrder
+	}
+
+	for i, id := range ids {
+		if _, err := q.Exec(ctx,
+			"UPDATE category_tree SET position = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2",
+			i+1, id); err != nil {
+			return err
+		}
+	}
+	return projectCategoryNodes(ctx, q, ids)
+}
+
+// Düğümü alt ağacıyla birlikte silinmiş olarak işaretler. Tüm alt ağaç aynı zaman
+// damgasını alır; geri yükleme bu damgayla birlikte silinenleri geri getirir.
+func DeleteCategoryNode(ctx context.Context, q DBTX, nodeID, deletedBy int) (time.Time, error) {
+	var exists bool
+	err := q.QueryRow(ctx,
+		"SELECT true FROM category_tree WHERE id = $1 AND deleted_at IS NULL FOR UPDATE",
+		nodeID).Scan(&exists)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, ErrCategoryNodeNotFound
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	subtree, err := categorySubtreeIDs(ctx, q, nodeID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	rows, err := q.Query(ctx,
+		`UPDATE category_tree
+         SET deleted_at = CURRENT_TIMESTAMP, deleted_by = NULLIF($2, 0), updated_at = CURRENT_TIMESTAMP
+         WHERE id = ANY($1) AND deleted_at IS NULL
+         RETURNING id, deleted_at`,
+		subtree, deletedBy)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var deleted []int
+	var deletedAt time.Time
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id, &deletedAt); err != nil {
+			rows.Close()
+			return time.Time{}, err
+		}
+		deleted = append(deleted, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return time.Time{}, err
+	}
+
+	return deletedAt, projectCategoryNodes(ctx, q, deleted)
+}
+
+// Silinmiş düğümü ve onunla aynı anda silinen alt düğümlerini geri yükler
+func RestoreCategoryNode(ctx context.Context, q DBTX, nodeID int) error {
+	var deletedAt *time.Time
+	var parentLive bool
+	err := q.QueryRow(ctx,
+		`SELECT t.deleted_at, p.id IS NULL OR p.deleted_at IS NULL
+         FROM category_tree t
+         LEFT JOIN category_tree p ON p.id = t.parent_id
+         WHERE t.id = $1
+         FOR UPDATE OF t`,
+		nodeID).Scan(&deletedAt, &parentLive)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCategoryNodeNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if deletedAt == nil {
+		return ErrCategoryNodeNotDeleted
+	}
+	if time.Since(*deletedAt) > CategoryRetention {
+		return ErrCategoryRestoreExpired
+	}
+	if !parentLive {
+		return ErrCategoryParentDeleted
+	}
+
+	subtree, err := categorySubtreeIDs(ctx, q, nodeID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := q.Query(ctx,
+		`UPDATE category_tree
+         SET deleted_at = NULL, deleted_by = NULL, updated_at = CURRENT_TIMESTAMP
+         WHERE id = ANY($1) AND deleted_at = $2
+         RETURNING id`,
+		subtree, *deletedAt)
+	if err != nil {
+		return err
+	}
+	restored, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return err
+	}
+	return projectCategoryNodes(ctx, q, restored)
+}
+
+// Kaynak düğümün sorularını ve alt düğümlerini hedefe aktarır, kaynağı silinmiş olarak
+// işaretler. Hedefe yeni bağlanan soru sayısını döndürür.
+func MergeCategoryNodes(ctx context.Context, q DBTX, sourceID, targetID, deletedBy int) (int64, error) {
+	var found int
+	err := q.QueryRow(ctx,
+		"SELECT COUNT(*) FROM (SELECT id FROM category_tree WHERE id IN ($1, $2) AND deleted_at IS NULL ORDER BY id FOR UPDATE) t",
+		sourceID, targetID).Scan(&found)
+	if err != nil {
+		return 0, err
+	}
+	if found != 2 {
+		return 0, ErrCategoryNodeNotFound
+	}
+
+	questionIDs, err := questionsLinkedToNodes(ctx, q, []int{sourceID})
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := q.Exec(ctx,
+		`INSERT INTO question_category_nodes (question_id, node_id)
+         SELECT question_id, $2 FROM question_category_nodes WHERE node_id = $1
+         ON CONFLICT DO NOTHING`,
+		sourceID, targetID)
+	if err != nil {
+		return 0, err
+	}
+	moved := result.RowsAffected()
+
+	if _, err := q.Exec(ctx, "DELETE FROM question_category_nodes WHERE node_id = $1", sourceID); err != nil {
+		return 0, err
+	}
+
+	// Kaynağın canlı alt düğümleri silinmemesi için hedefin altına taşınır
+	rows, err := q.Query(ctx,
+		"SELECT id FROM category_tree WHERE parent_id = $1 AND deleted_at IS NULL ORDER BY position, id",
+		sourceID)
+	if err != nil {
+		return 0, err
+	}
+	children, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return 0, err
+	}
+	for _, child := range children {
+		if err := MoveCategoryNode(ctx, q, child, &targetID); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := DeleteCategoryNode(ctx, q, sourceID, deletedBy); err != nil {
+		return 0, err
+	}
+	return moved, SyncQuestionCategories(ctx, q, questionIDs)
+}
+
+// Düğüm ve silinmemiş tüm alt düğümleri
+func LiveCategorySubtree(ctx context.Context, q DBTX, nodeID int) ([]int, error) {
+	rows, err := q.Query(ctx, `
+        WITH RECURSIVE Subtree AS (
+            SELECT id FROM category_tree WHERE id = $1 AND deleted_at IS NULL
+            UNION ALL
+            SELECT c.id FROM category_tree c JOIN Subtree s ON c.parent_id = s.id
+            WHERE c.deleted_at IS NULL
+        )
+        SELECT id FROM Subtree`, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+// Düğümlere bağlı soruları hedef düğüme aktarır; hedefe yeni bağlanan soru sayısını döndürür
+func ReassignCategoryQuestions(ctx context.Context, q DBTX, nodeIDs []int, targetID int) (int64, error) {
+	questionIDs, err := questionsLinkedToNodes(ctx, q, nodeIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := q.Exec(ctx,
+		`INSERT INTO question_category_nodes (question_id, node_id)
+         SELECT DISTINCT question_id, $2::int FROM question_category_nodes WHERE node_id = ANY($1)
+         ON CONFLICT DO NOTHING`,
+		nodeIDs, targetID)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := q.Exec(ctx, "DELETE FROM question_category_nodes WHERE node_id = ANY($1)", nodeIDs); err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), SyncQuestionCategories(ctx, q, questionIDs)
+}
+
+// Sorunun kategori bağlantılarını değiştirir. Eski kategori ID'leri düğümlerine çevrilir;
+// eski tablodaki ilişkiler düğümlerden yeniden üretilir.
+func SetQuestionCategories(ctx context.Context, q DBTX, questionID int, categoryIDs, nodeIDs []int) error {
+	nodes := append([]int{}, nodeIDs...)
+	if len(categoryIDs) > 0 {
+		rows, err := q.Query(ctx,
+			"SELECT id FROM category_tree WHERE legacy_level = 'category' AND legacy_id = ANY($1)",
+			categoryIDs)
+		if err != nil {
+			return err
+		}
+		mapped, err := pgx.CollectRows(rows, pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		if len(mapped) != len(uniqueIDs(categoryIDs)) {
+			return ErrCategoryNodeNotFound
+		}
+		nodes = append(nodes, mapped...)
+	}
+
+	if _, err := q.Exec(ctx, "DELETE FROM question_category_nodes WHERE question_id = $1", questionID); err != nil {
+		return err
+	}
+
+	if len(nodes) > 0 {
+		result, err := q.Exec(ctx,
+			`INSERT INTO question_category_nodes (question_id, node_id)
+             SELECT $1, t.id FROM category_tree t WHERE t.id = ANY($2)
+             ON CONFLICT DO NOTHING`,
+			questionID, nodes)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() != int64(len(uniqueIDs(nodes))) {
+			return ErrCategoryNodeNotFound
+		}
+	}
+
+	return SyncQuestionCategories(ctx, q, []int{questionID})
+}
+
+// Soruların eski kategori ilişkilerini bağlı oldukları düğümlerden yeniden üretir.
+// Üçüncü seviyeden derin bir düğüme bağlı soru, o düğümün üçüncü seviyedeki atasına bağlanır.
+func SyncQuestionCategories(ctx context.Context, q DBTX, questionIDs []int) error {
+	if len(questionIDs) == 0 {
+		return nil
+	}
+
+	if _, err := q.Exec(ctx, "DELETE FROM question_categories WHERE question_id = ANY($1)", questionIDs); err != nil {
+		return err
+	}
+
+	_, err := q.Exec(ctx, `
+        INSERT INTO question_categories (question_id, category_id)
+        WITH RECURSIVE Ancestors AS (
+            SELECT qcn.question_id, t.id, t.parent_id, t.legacy_level, t.legacy_id
+            FROM question_category_nodes qcn
+            JOIN category_tree t ON t.id = qcn.node_id
+            WHERE qcn.question_id = ANY($1)
+            UNION
+            SELECT a.question_id, t.id, t.parent_id, t.legacy_level, t.legacy_id
+            FROM category_tree t
+            JOIN Ancestors a ON t.id = a.parent_id
+        )
+        SELECT DISTINCT question_id, legacy_id FROM Ancestors WHERE legacy_level = 'category'`,
+		questionIDs)
+	return err
+}
+
+// Düğüm ve tüm alt düğümleri
+func categorySubtreeIDs(ctx context.Context, q DBTX, nodeID int) ([]int, error) {
+	rows, err := q.Query(ctx, `
+        WITH RECURSIVE Subtree AS (
+            SELECT id FROM category_tree WHERE id = $1
+            UNION ALL
+            SELECT c.id FROM category_tree c JOIN Subtree s ON c.parent_id = s.id
+        )
+        SELECT id FROM Subtree`, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+func questionsLinkedToNodes(ctx context.Context, q DBTX, nodeIDs []int) ([]int, error) {
+	rows, err := q.Query(ctx,
+		"SELECT DISTINCT question_id FROM question_category_nodes WHERE node_id = ANY($1)",
+		nodeIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+type categoryNodeProjection struct {
+	id          int
+	parentID    *int
+	name        string
+	position    int
+	deletedAt   *time.Time
+	legacyLevel *string
+	legacyID    *int
+	depth       int
+}
+
+// Verilen düğümleri eski tablolara yansıtır. Seviyesi değişen düğümün eski kaydı silinmiş
+// olarak işaretlenir ve yeni seviyesinde yeni bir kayıt oluşturulur.
+func projectCategoryNodes(ctx context.Context, q DBTX, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := q.Query(ctx, `
+        WITH RECURSIVE Ancestors AS (
+            SELECT id AS node_id, parent_id, 0 AS up FROM category_tree WHERE id = ANY($1)
+            UNION ALL
+            SELECT a.node_id, t.parent_id, a.up + 1
+            FROM category_tree t
+            JOIN Ancestors a ON t.id = a.parent_id
+        )
+        SELECT t.id, t.parent_id, t.name, t.position, t.deleted_at, t.legacy_level, t.legacy_id, d.depth
+        FROM category_tree t
+        JOIN (SELECT node_id, MAX(up) AS depth FROM Ancestors GROUP BY node_id) d ON d.node_id = t.id
+        ORDER BY d.depth, t.id`, ids)
+	if err != nil {
+		return err
+	}
+	var nodes []categoryNodeProjection
+	for rows.Next() {
+		var n categoryNodeProjection
+		if err := rows.Scan(&n.id, &n.parentID, &n.name, &n.position, &n.deletedAt,
+			&n.legacyLevel, &n.legacyID, &n.depth); err != nil {
+			rows.Close()
+			return err
+		}
+		nodes = append(nodes, n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, n := range nodes {
+		if err := projectCategoryNode(ctx, q, n); err != nil {
+			return fmt.Errorf("düğüm %d eski tablolara yansıtılamadı: %w", n.id, err)
+		}
+	}
+	return nil
+}
+
+func projectCategoryNode(ctx context.Context, q DBTX, n categoryNodeProjection) error {
+	level := legacyLevelForDepth(n.depth)
+
+	if n.legacyLevel != nil && *n.legacyLevel != level {
+		table, err := categoryLevelTable(*n.legacyLevel)
+		if err != nil {
+			return err
+		}
+		if _, err := q.Exec(ctx,
+			fmt.Sprintf("UPDATE %s SET deleted_at = COALESCE(deleted_at, CURRENT_TIMESTAMP) WHERE id = $1", table),
+			*n.legacyID); err != nil {
+			return err
+		}
+		if _, err := q.Exec(ctx,
+			"UPDATE category_tree SET legacy_level = NULL, legacy_id = NULL WHERE id = $1", n.id); err != nil {
+			return err
+		}
+		n.legacyLevel, n.legacyID = nil, nil
+	}
+	if level == "" {
+		return nil
+	}
+
+	// Ebeveynin eski kaydı; sıralama derinliğe göre olduğundan önce o yansıtılmıştır
+	var parentLegacyID int
+	if n.parentID != nil {
+		var err error
+		if parentLegacyID, err = CategoryNodeLegacyID(ctx, q, *n.parentID); err != nil {
+			return err
+		}
+	}
+
+	var id int
+	var oldParentID *int
+	var oldSlug *string
+	if n.legacyID != nil {
+		id = *n.legacyID
+		var err error
+		switch level {
+		case "sub":
+			err = q.QueryRow(ctx,
+				`SELECT sc.slug, mcsc.main_category_id
+                 FROM sub_categories sc
+                 LEFT JOIN main_category_sub_category mcsc ON mcsc.sub_category_id = sc.id
+                 WHERE sc.id = $1`, id).Scan(&oldSlug, &oldParentID)
+		case "category":
+			err = q.QueryRow(ctx,
+				"SELECT slug, sub_category_id FROM categories WHERE id = $1", id).Scan(&oldSlug, &oldParentID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	var err error
+	switch {
+	case level == "main" && n.legacyID == nil:
+		err = q.QueryRow(ctx,
+			"INSERT INTO main_categories (name, position, deleted_at) VALUES ($1, $2, $3) RETURNING id",
+			n.name, n.position, n.deletedAt).Scan(&id)
+	case level == "main":
+		_, err = q.Exec(ctx,
+			"UPDATE main_categories SET name = $1, position = $2, deleted_at = $3 WHERE id = $4",
+			n.name, n.position, n.deletedAt, id)
+	case level == "sub" && n.legacyID == nil:
+		err = q.QueryRow(ctx,
+			"INSERT INTO sub_categories (name, deleted_at) VALUES ($1, $2) RETURNING id",
+			n.name, n.deletedAt).Scan(&id)
+		if err == nil {
+			_, err = q.Exec(ctx,
+				"INSERT INTO main_category_sub_category (main_category_id, sub_category_id, position) VALUES ($1, $2, $3)",
+				parentLegacyID, id, n.position)
+		}
+	case level == "sub":
+		_, err = q.Exec(ctx,
+			"UPDATE sub_categories SET name = $1, deleted_at = $2 WHERE id = $3",
+			n.name, n.deletedAt, id)
+		if err == nil && oldParentID == nil {
+			_, err = q.Exec(ctx,
+				"INSERT INTO main_category_sub_category (main_category_id, sub_category_id, position) VALUES ($1, $2, $3)",
+				parentLegacyID, id, n.position)
+		} else if err == nil {
+			_, err = q.Exec(ctx,
+				"UPDATE main_category_sub_category SET main_category_id = $1, position = $2 WHERE sub_category_id = $3",
+				parentLegacyID, n.position, id)
+		}
+	case n.legacyID == nil:
+		err = q.QueryRow(ctx,
+			`INSERT INTO categories (sub_category_id, name, position, deleted_at)
+             VALUES ($1, $2, $3, $4) RETURNING id`,
+			parentLegacyID, n.name, n.position, n.deletedAt).Scan(&id)
+	default:
+		_, err = q.Exec(ctx,
+			"UPDATE categories SET sub_category_id = $1, name = $2, position = $3, deleted_at = $4 WHERE id = $5",
+			parentLegacyID, n.name, n.position, n.deletedAt, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if n.legacyID == nil {
+		if _, err := q.Exec(ctx,
+			"UPDATE category_tree SET legacy_level = $1, legacy_id = $2 WHERE id = $3",
+			level, id, n.id); err != nil {
+			return err
+		}
+	}
+
+	// Taşınan kaydın eski adresi yeni konuma yönlendirilir; slug hedefteki kardeşlere göre
+	// yeniden üretilir
+	if oldParentID != nil && *oldParentID != parentLegacyID {
+		if oldSlug != nil {
+			if err := RecordCategorySlugRedirect(ctx, q, level, *oldParentID, *oldSlug, id); err != nil {
+				return err
+			}
+		}
+		table, _ := categoryLevelTable(level)
+		if _, err := q.Exec(ctx, fmt.Sprintf("UPDATE %s SET slug = NULL WHERE id = $1", table), id); err != nil {
+			return err
+		}
+	}
+
+	_, err = SetCategorySlug(ctx, q, level, id)
+	return err
+}
+
+func sameIDSet(current, requested []int) bool {
+	if len(current) != len(requested) {
+		return false
+	}
+
+	seen := make(map[int]bool, len(current))
+	for _, id := range current {
+		seen[id] = true
+	}
+	for _, id := range requested {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
+
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
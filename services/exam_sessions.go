@@ -0,0 +1,569 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"osymapp/db"
+	"osymapp/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	maxExamDurationMinutes = 300
+	maxExamQuestions       = 200
+	examSweepBatchSize     = 100
+)
+
+var (
+	ErrExamSessionNotFound   = errors.New("sınav oturumu bulunamadı")
+	ErrExamSessionFinished   = errors.New("sınav oturumu tamamlanmış")
+	ErrExamTimeExpired       = errors.New("sınav süresi doldu")
+	ErrExamSessionPaused     = errors.New("sınav duraklatılmış")
+	ErrExamPauseNotAllowed   = errors.New("yalnızca alıştırma modundaki sınavlar duraklatılabilir")
+	ErrExamNotPaused         = errors.New("sınav duraklatılmamış")
+	ErrExamQuestionNotInExam = errors.New("soru bu sınavda yok")
+	ErrExamNoQuestions       = errors.New("filtrelere uyan soru bulunamadı")
+	ErrExamInvalidRequest    = errors.New("geçersiz sınav ayarları")
+	ErrExamQuestionLocked    = errors.New("soru devam eden sınavınızda; cevabı sınav bitince gösterilir")
+)
+
+// Soru havuzu GetQuestions filtreleriyle daraltılabilir; yalnızca yayındaki sorular kullanılır
+var examFilterKeys = []string{"sub_category_id", "category_id", "difficulty", "search", "node_id"}
+
+func ExamFilters(query url.Values) map[string]string {
+	filters := make(map[string]string)
+	for _, key := range examFilterKeys {
+		if v := query.Get(key); v != "" {
+			filters[key] = v
+		}
+	}
+	return filters
+}
+
+// Süre sunucu saatiyle hesaplanır; duraklatılmış sınavda süre duraklatma anında donar
+const examSessionColumns = `
+        s.id, s.user_id, s.mode, s.preset, s.filters, s.status, s.duration_seconds,
+        s.started_at, s.deadline_at, s.paused_at, s.paused_seconds, s.finished_at,
+        COALESCE(s.finish_reason, ''), s.correct, s.wrong, s.blank, s.net,
+        (SELECT COUNT(*) FROM exam_session_items i WHERE i.session_id = s.id),
+        (SELECT COUNT(*) FROM exam_session_items i WHERE i.session_id = s.id AND i.answer <> ''),
+        CASE s.status
+            WHEN 'active' THEN GREATEST(0, CEIL(EXTRACT(EPOCH FROM s.deadline_at - CURRENT_TIMESTAMP)))
+            WHEN 'paused' THEN GREATEST(0, CEIL(EXTRACT(EPOCH FROM s.deadline_at - s.paused_at)))
+            ELSE 0
+        END::int,
+        CURRENT_TIMESTAMP::timestamp`
+
+func scanExamSession(row pgx.Row) (*models.ExamSession, error) {
+	var s models.ExamSession
+	var filters []byte
+	var correct, wrong, blank *int
+	var net *float64
+	err := row.Scan(&s.ID, &s.UserID, &s.Mode, &s.Preset, &filters, &s.Status, &s.DurationSeconds,
+		&s.StartedAt, &s.DeadlineAt, &s.PausedAt, &s.PausedSeconds, &s.FinishedAt,
+		&s.FinishReason, &correct, &wrong, &blank, &net,
+		&s.QuestionCount, &s.Answered, &s.RemainingSeconds, &s.ServerTime)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrExamSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filters, &s.Filters); err != nil {
+		return nil, err
+	}
+	if correct != nil && wrong != nil && blank != nil && net != nil {
+		s.Result = &models.ExamResult{Correct: *correct, Wrong: *wrong, Blank: *blank, Net: *net}
+	}
+	return &s, nil
+}
+
+func loadExamSession(ctx context.Context, q DBTX, sessionID int64, userID int, forUpdate bool) (*models.ExamSession, error) {
+	query := "SELECT " + examSessionColumns + " FROM exam_sessions s WHERE s.id = $1 AND s.user_id = $2"
+	if forUpdate {
+		query += " FOR UPDATE OF s"
+	}
+	return scanExamSession(q.QueryRow(ctx, query, sessionID, userID))
+}
+
+// Süre dolmuş ama henüz kapatılmamış oturum
+func examExpired(s *models.ExamSession) bool {
+	return s.Status == models.ExamStatusActive && s.RemainingSeconds <= 0
+}
+
+// Yeni sınav açar ve soruları rastgele seçer
+func StartExamSession(ctx context.Context, userID int, filters map[string]string, req models.ExamSessionRequest) (*models.ExamSession, error) {
+	if req.Preset != "" {
+		preset, ok := models.ExamPresets[req.Preset]
+		if !ok {
+			return nil, fmt.Errorf("%w: bilinmeyen sınav türü %q", ErrExamInvalidRequest, req.Preset)
+		}
+		if req.DurationMinutes == 0 {
+			req.DurationMinutes = preset.DurationMinutes
+		}
+		if req.QuestionCount == 0 {
+			req.QuestionCount = preset.QuestionCount
+		}
+	}
+	if req.Mode == "" {
+		req.Mode = models.ExamModeExam
+	}
+	if req.Mode != models.ExamModeExam && req.Mode != models.ExamModePractice {
+		return nil, fmt.Errorf("%w: mod exam veya practice olmalı", ErrExamInvalidRequest)
+	}
+	if req.DurationMinutes <= 0 || req.DurationMinutes > maxExamDurationMinutes {
+		return nil, fmt.Errorf("%w: süre 1-%d dakika olmalı", ErrExamInvalidRequest, maxExamDurationMinutes)
+	}
+	if req.QuestionCount <= 0 || req.QuestionCount > maxExamQuestions {
+		return nil, fmt.Errorf("%w: soru sayısı 1-%d olmalı", ErrExamInvalidRequest, maxExamQuestions)
+	}
+
+	filtersJSON, err := json.Marshal(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.GetPool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int64
+	if err := tx.QueryRow(ctx, `
+        INSERT INTO exam_sessions (user_id, mode, preset, filters, duration_seconds, deadline_at)
+        VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP + $5 * INTERVAL '1 second')
+        RETURNING id`,
+		userID, req.Mode, req.Preset, filtersJSON, req.DurationMinutes*60).Scan(&id); err != nil {
+		return nil, err
+	}
+
+	query := make(url.Values)
+	for k, v := range filters {
+		query.Set(k, v)
+	}
+	conditions, args := QuestionFilterConditions(query, false)
+	args = append(args, id, req.QuestionCount)
+	sql := fmt.Sprintf(`
+        INSERT INTO exam_session_items (session_id, position, question_id)
+        SELECT $%d, ROW_NUMBER() OVER (), id
+        FROM (
+            SELECT q.id FROM questions q
+            WHERE q.deleted_at IS NULL`, len(args)-1)
+	if len(conditions) > 0 {
+		sql += " AND " + strings.Join(conditions, " AND ")
+	}
+	sql += fmt.Sprintf(" ORDER BY random() LIMIT $%d) picked", len(args))
+
+	tag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrExamNoQuestions
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return GetExamSession(ctx, id, userID)
+}
+
+// Cevabı kaydeder; sınav bitene kadar değiştirilebilir, doğruluk bitişte hesaplanır.
+// Süre dolduysa oturum hemen kapatılır ve ErrExamTimeExpired döner.
+func AnswerExamQuestion(ctx context.Context, userID int, sessionID int64, req models.ExamAnswerRequest) (*models.ExamSession, error) {
+	tx, err := db.GetPool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	session, err := loadExamSession(ctx, tx, sessionID, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case session.Status == models.ExamStatusFinished:
+		return nil, ErrExamSessionFinished
+	case session.Status == models.ExamStatusPaused:
+		return nil, ErrExamSessionPaused
+	case examExpired(session):
+		graded, err := finalizeExamSession(ctx, tx, session, models.ExamFinishExpired)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, err
+		}
+		recordExamAttemptEvents(userID, graded)
+		return nil, ErrExamTimeExpired
+	}
+
+	tag, err := tx.Exec(ctx, `
+        UPDATE exam_session_items
+        SET answer = $1,
+            time_spent_ms = CASE WHEN $2::int IS NULL THEN time_spent_ms ELSE COALESCE(time_spent_ms, 0) + $2::int END,
+            answered_at = CASE WHEN $1 = '' THEN NULL ELSE CURRENT_TIMESTAMP END
+        WHERE session_id = $3 AND question_id = $4`,
+		strings.TrimSpace(req.Answer), req.TimeSpentMS, sessionID, req.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrExamQuestionNotInExam
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return loadExamSession(ctx, db.GetPool(), sessionID, userID, false)
+}
+
+// Sınavı teslim eder
+func SubmitExamSession(ctx context.Context, userID int, sessionID int64) (*models.ExamSession, error) {
+	err := withExamSession(ctx, userID, sessionID, func(tx pgx.Tx, s *models.ExamSession) ([]int, error) {
+		if s.Status == models.ExamStatusFinished {
+			return nil, ErrExamSessionFinished
+		}
+		// Süre dolduktan sonraki teslim, süre dolumu olarak kaydedilir
+		reason := models.ExamFinishSubmitted
+		if examExpired(s) {
+			reason = models.ExamFinishExpired
+		}
+		return finalizeExamSession(ctx, tx, s, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return GetExamSession(ctx, sessionID, userID)
+}
+
+// Alıştırma modundaki sınavı duraklatır; kalan süre donar
+func PauseExamSession(ctx context.Context, userID int, sessionID int64) (*models.ExamSession, error) {
+	err := withExamSession(ctx, userID, sessionID, func(tx pgx.Tx, s *models.ExamSession) ([]int, error) {
+		switch {
+		case s.Mode != models.ExamModePractice:
+			return nil, ErrExamPauseNotAllowed
+		case s.Status == models.ExamStatusFinished:
+			return nil, ErrExamSessionFinished
+		case s.Status == models.ExamStatusPaused:
+			return nil, ErrExamSessionPaused
+		case examExpired(s):
+			return finalizeExamSession(ctx, tx, s, models.ExamFinishExpired)
+		}
+		_, err := tx.Exec(ctx,
+			"UPDATE exam_sessions SET status = 'paused', paused_at = CURRENT_TIMESTAMP WHERE id = $1", s.ID)
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loadExamSession(ctx, db.GetPool(), sessionID, userID, false)
+}
+
+// Duraklatılan sınavı sürdürür; bitiş zamanı duraklatılan süre kadar ileri kayar
+func ResumeExamSession(ctx context.Context, userID int, sessionID int64) (*models.ExamSession, error) {
+	err := withExamSession(ctx, userID, sessionID, func(tx pgx.Tx, s *models.ExamSession) ([]int, error) {
+		switch s.Status {
+		case models.ExamStatusFinished:
+			return nil, ErrExamSessionFinished
+		case models.ExamStatusActive:
+			return nil, ErrExamNotPaused
+		}
+		_, err := tx.Exec(ctx, `
+            UPDATE exam_sessions
+            SET status = 'active',
+                deadline_at = deadline_at + (CURRENT_TIMESTAMP - paused_at),
+                paused_seconds = paused_seconds + CEIL(EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - paused_at))::int,
+                paused_at = NULL
+            WHERE id = $1`, s.ID)
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loadExamSession(ctx, db.GetPool(), sessionID, userID, false)
+}
+
+// Kalan süre; süresi dolmuş oturum süpürücüyü beklemeden kapatılır
+func GetExamClock(ctx context.Context, userID int, sessionID int64) (*models.ExamClock, error) {
+	session, err := loadExamSession(ctx, db.GetPool(), sessionID, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	if examExpired(session) {
+		if session, err = expireExamSession(ctx, userID, sessionID); err != nil {
+			return nil, err
+		}
+	}
+	return &session.ExamClock, nil
+}
+
+// Oturumu sorularıyla döner. Sınav sürerken cevap anahtarı gösterilmez.
+func GetExamSession(ctx context.Context, sessionID int64, userID int) (*models.ExamSession, error) {
+	pool := db.GetPool()
+	session, err := loadExamSession(ctx, pool, sessionID, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	if examExpired(session) {
+		if session, err = expireExamSession(ctx, userID, sessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	finished := session.Status == models.ExamStatusFinished
+	rows, err := pool.Query(ctx, `
+        SELECT i.position, i.question_id, i.answer, i.time_spent_ms, i.answered_at, i.is_correct,
+               CASE WHEN $2 THEN COALESCE(q.answer, '') ELSE '' END
+        FROM exam_session_items i
+        JOIN questions q ON q.id = i.question_id
+        WHERE i.session_id = $1
+        ORDER BY i.position`, sessionID, finished)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	session.Items = []models.ExamSessionItem{}
+	for rows.Next() {
+		var item models.ExamSessionItem
+		if err := rows.Scan(&item.Position, &item.QuestionID, &item.Answer, &item.TimeSpentMS,
+			&item.AnsweredAt, &item.IsCorrect, &item.CorrectAnswer); err != nil {
+			return nil, err
+		}
+		session.Items = append(session.Items, item)
+	}
+	return session, rows.Err()
+}
+
+// Kullanıcının devam eden (aktif veya duraklatılmış) sınavlarındaki sorular.
+// Bu soruların cevap ve çözümleri sınav bitene kadar başka yollardan da gösterilmez.
+func OngoingExamQuestionIDs(ctx context.Context, q DBTX, userID int, questionIDs []int) (map[int]bool, error) {
+	locked := make(map[int]bool)
+	if userID == 0 || len(questionIDs) == 0 {
+		return locked, nil
+	}
+
+	var ids []int
+	err := q.QueryRow(ctx, `
+        SELECT COALESCE(array_agg(DISTINCT i.question_id), '{}')
+        FROM exam_session_items i
+        JOIN exam_sessions s ON s.id = i.session_id
+        WHERE s.user_id = $1 AND s.status IN ($2, $3) AND i.question_id = ANY($4)`,
+		userID, models.ExamStatusActive, models.ExamStatusPaused, questionIDs).Scan(&ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		locked[id] = true
+	}
+	return locked, nil
+}
+
+// Kullanıcının sınavları (en yeni önce)
+func ListExamSessions(ctx context.Context, userID, limit, offset int) ([]models.ExamSession, error) {
+	rows, err := db.GetPool().Query(ctx, "SELECT "+examSessionColumns+`
+        FROM exam_sessions s
+        WHERE s.user_id = $1
+        ORDER BY s.started_at DESC, s.id DESC
+        LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.ExamSession{}
+	for rows.Next() {
+		s, err := scanExamSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *s)
+	}
+	return sessions, rows.Err()
+}
+
+func expireExamSession(ctx context.Context, userID int, sessionID int64) (*models.ExamSession, error) {
+	err := withExamSession(ctx, userID, sessionID, func(tx pgx.Tx, s *models.ExamSession) ([]int, error) {
+		// Bu arada süpürücü veya başka bir istek kapatmış olabilir
+		if !examExpired(s) {
+			return nil, nil
+		}
+		return finalizeExamSession(ctx, tx, s, models.ExamFinishExpired)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loadExamSession(ctx, db.GetPool(), sessionID, userID, false)
+}
+
+// Oturumu kilitleyip fn'i tek transaction içinde çalıştırır. fn'in döndürdüğü
+// değerlendirilen sorular commit'ten sonra popülerlik olayı olarak kaydedilir.
+func withExamSession(ctx context.Context, userID int, sessionID int64, fn func(pgx.Tx, *models.ExamSession) ([]int, error)) error {
+	tx, err := db.GetPool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	session, err := loadExamSession(ctx, tx, sessionID, userID, true)
+	if err != nil {
+		return err
+	}
+	graded, err := fn(tx, session)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	recordExamAttemptEvents(userID, graded)
+	return nil
+}
+
+// Verilen cevapları cevap geçmişine işler, sonucu hesaplar ve oturumu kapatır.
+// Değerlendirilen soruların ID'lerini döner.
+func finalizeExamSession(ctx context.Context, tx pgx.Tx, s *models.ExamSession, reason string) ([]int, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT question_id, answer, time_spent_ms
+        FROM exam_session_items
+        WHERE session_id = $1 AND answer <> ''
+        ORDER BY position`, s.ID)
+	if err != nil {
+		return nil, err
+	}
+	type answered struct {
+		questionID  int
+		answer      string
+		timeSpentMS *int
+	}
+	var items []answered
+	for rows.Next() {
+		var a answered
+		if err := rows.Scan(&a.questionID, &a.answer, &a.timeSpentMS); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		items = append(items, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var result models.ExamResult
+	var graded []int
+	for _, a := range items {
+		attempt, err := RecordQuestionAttempt(ctx, tx, s.UserID, a.questionID, false, models.QuestionAttemptRequest{
+			Answer:      a.answer,
+			TimeSpentMS: a.timeSpentMS,
+			Source:      models.AttemptSourceExam,
+			SessionID:   &s.ID,
+		})
+		// Sınav sırasında çöp kutusuna taşınan veya yayından kaldırılan sorular değerlendirilmez
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		graded = append(graded, a.questionID)
+		if attempt.Attempt.IsCorrect {
+			result.Correct++
+		} else {
+			result.Wrong++
+		}
+		if _, err := tx.Exec(ctx,
+			"UPDATE exam_session_items SET is_correct = $1 WHERE session_id = $2 AND question_id = $3",
+			attempt.Attempt.IsCorrect, s.ID, a.questionID); err != nil {
+			return nil, err
+		}
+	}
+	result.Blank = s.QuestionCount - result.Correct - result.Wrong
+	result.Net = float64(result.Correct) - float64(result.Wrong)/models.NetPenaltyRatio
+
+	_, err = tx.Exec(ctx, `
+        UPDATE exam_sessions
+        SET status = 'finished', finish_reason = $1, finished_at = LEAST(CURRENT_TIMESTAMP::timestamp, deadline_at),
+            paused_at = NULL, correct = $2, wrong = $3, blank = $4, net = $5
+        WHERE id = $6`,
+		reason, result.Correct, result.Wrong, result.Blank, result.Net, s.ID)
+	return graded, err
+}
+
+// Kapatılan oturumdaki cevaplar popülerlik skoruna deneme olarak yansır
+func recordExamAttemptEvents(userID int, questionIDs []int) {
+	for _, id := range questionIDs {
+		RecordQuestionEvent(UserEventActor(userID), id, models.QuestionEventAttempt)
+	}
+}
+
+// Süresi dolan oturumları kapatır; her oturum kendi transaction'ında işlenir
+func FinalizeExpiredExamSessions(ctx context.Context) (int, error) {
+	rows, err := db.GetPool().Query(ctx, `
+        SELECT id, user_id FROM exam_sessions
+        WHERE status = 'active' AND deadline_at <= CURRENT_TIMESTAMP
+        ORDER BY deadline_at
+        LIMIT $1`, examSweepBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	type expired struct {
+		id     int64
+		userID int
+	}
+	var sessions []expired
+	for rows.Next() {
+		var e expired
+		if err := rows.Scan(&e.id, &e.userID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		sessions = append(sessions, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	finalized := 0
+	for _, e := range sessions {
+		// Kapatılamayan sınav diğerlerinin teslimini engellemez; sonraki taramada tekrar denenir
+		session, err := expireExamSession(ctx, e.userID, e.id)
+		if err != nil {
+			log.Printf("Sınav %d kapatılamadı: %v", e.id, err)
+			continue
+		}
+		if session.Status == models.ExamStatusFinished {
+			finalized++
+		}
+	}
+	return finalized, nil
+}
+
+// Süresi dolan sınavları arka planda otomatik teslim eder
+func StartExamSweeper(interval time.Duration) {
+	go func() {
+		for {
+			time.Sleep(interval)
+
+			n, err := FinalizeExpiredExamSessions(context.Background())
+			if err != nil {
+				log.Printf("Sınav süpürme hatası: %v", err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("Sınav süpürme: süresi dolan %d sınav teslim edildi", n)
+			}
+		}
+	}()
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"osymapp/db"
+	"osymapp/models"
+)
+
+// Henüz commit edilmemiş yüklemelerin silinmemesi için varsayılan bekleme süresi
+const DefaultImageGCGracePeriod = time.Hour
+
+type ImageGCOptions struct {
+	DryRun      bool
+	GracePeriod time.Duration
+}
+
+// Veritabanında olup diskte bulunmayan resim referansı
+type DanglingImageRef struct {
+	QuestionID int    `json:"question_id"`
+	Column     string `json:"column"`
+	URL        string `json:"url"`
+}
+
+type ImageGCReport struct {
+	DryRun       bool               `json:"dry_run"`
+	ScannedFiles int                `json:"scanned_files"`
+	Orphans      []string           `json:"orphans"`
+	Removed      []string           `json:"removed"`
+	Skipped      int                `json:"skipped_in_grace_period"`
+	Dangling     []DanglingImageRef `json:"dangling"`
+	StartedAt    time.Time          `json:"started_at"`
+	FinishedAt   time.Time          `json:"finished_at"`
+}
+
+// Diskteki soru/çözüm resimlerini questions tablosuyla karşılaştırır.
+// Hiçbir soruya bağlı olmayan ve grace period'dan eski dosyaları siler (DryRun
+// değilse), diskte bulunmayan referansları da rapora ekler.
+func CollectOrphanImages(ctx context.Context, opts ImageGCOptions) (*ImageGCReport, error) {
+	report := &ImageGCReport{DryRun: opts.DryRun, StartedAt: time.Now()}
+
+	referenced := make(map[string]bool)
+	rows, err := db.GetPool().Query(ctx,
+		"SELECT id, COALESCE(path_url, ''), COALESCE(solution_url, '') FROM questions")
+	if err != nil {
+		return nil, fmt.Errorf("soru resimleri alınamadı: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var pathURL, solutionURL string
+		if err := rows.Scan(&id, &pathURL, &solutionURL); err != nil {
+			return nil, fmt.Errorf("soru resmi okunamadı: %v", err)
+		}
+		for column, url := range map[string]string{"path_url": pathURL, "solution_url": solutionURL} {
+			if url == "" {
+				continue
+			}
+			filePath := filepath.Clean(strings.TrimPrefix(url, "/"))
+			referenced[filePath] = true
+			if _, err := os.Stat(filePath); os.IsNotExist(err) {
+				report.Dangling = append(report.Dangling, DanglingImageRef{QuestionID: id, Column: column, URL: url})
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("soru resimleri okunamadı: %v", err)
+	}
+
+	// Eski revizyonlardaki resimler geri yükleme için korunur
+	revRows, err := db.GetPool().Query(ctx, `
+        SELECT DISTINCT url
+        FROM question_revisions,
+             LATERAL (VALUES (snapshot->>'path_url'), (snapshot->>'solution_url')) AS refs(url)
+        WHERE url <> ''`)
+	if err != nil {
+		return nil, fmt.Errorf("revizyon resimleri alınamadı: %v", err)
+	}
+	defer revRows.Close()
+
+	for revRows.Next() {
+		var url string
+		if err := revRows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("revizyon resmi okunamadı: %v", err)
+		}
+		referenced[filepath.Clean(strings.TrimPrefix(url, "/"))] = true
+	}
+	if err := revRows.Err(); err != nil {
+		return nil, fmt.Errorf("revizyon resimleri okunamadı: %v", err)
+	}
+
+	// Soru metinlerinde Markdown ile gömülen resimler de referans sayılır
+	bodyRows, err := db.GetPool().Query(ctx, `
+        SELECT body FROM questions WHERE body IS NOT NULL
+        UNION ALL
+        SELECT snapshot->'body' FROM question_revisions WHERE jsonb_typeof(snapshot->'body') = 'object'`)
+	if err != nil {
+		return nil, fmt.Errorf("soru metinleri alınamadı: %v", err)
+	}
+	defer bodyRows.Close()
+
+	for bodyRows.Next() {
+		var body *models.QuestionBody
+		if err := bodyRows.Scan(&body); err != nil {
+			return nil, fmt.Errorf("soru metni okunamadı: %v", err)
+		}
+		for _, url := range QuestionBodyImageURLs(body) {
+			referenced[filepath.Clean(strings.TrimPrefix(url, "/"))] = true
+		}
+	}
+	if err := bodyRows.Err(); err != nil {
+		return nil, fmt.Errorf("soru metinleri okunamadı: %v", err)
+	}
+
+	cutoff := time.Now().Add(-opts.GracePeriod)
+	for _, dir := range []string{QuestionImagesPath, SolutionImagesPath} {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("dizin okunamadı (%s): %v", dir, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			report.ScannedFiles++
+
+			filePath := filepath.Join(dir, entry.Name())
+			if referenced[filePath] {
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return nil, fmt.Errorf("dosya bilgisi alınamadı (%s): %v", filePath, err)
+			}
+			if info.ModTime().After(cutoff) {
+				report.Skipped++
+				continue
+			}
+
+			url := "/" + filepath.ToSlash(filePath)
+			report.Orphans = append(report.Orphans, url)
+			if opts.DryRun {
+				continue
+			}
+			if err := DeleteImage(url); err != nil {
+				log.Printf("Sahipsiz resim silinirken hata: %v", err)
+				continue
+			}
+			report.Removed = append(report.Removed, url)
+		}
+	}
+
+	sort.Slice(report.Dangling, func(i, j int) bool {
+		return report.Dangling[i].QuestionID < report.Dangling[j].QuestionID
+	})
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// Ortam değişkenlerinden arka plan görevinin ayarlarını okur.
+// IMAGE_GC_INTERVAL boş veya "0" ise görev devre dışıdır.
+func ImageGCConfigFromEnv() (time.Duration, ImageGCOptions) {
+	opts := ImageGCOptions{GracePeriod: DefaultImageGCGracePeriod}
+
+	if v := os.Getenv("IMAGE_GC_GRACE"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			opts.GracePeriod = d
+		} else {
+			log.Printf("Uyarı: IMAGE_GC_GRACE geçersiz (%s), varsayılan kullanılıyor", v)
+		}
+	}
+	if v := os.Getenv("IMAGE_GC_DRY_RUN"); v != "" {
+		opts.DryRun, _ = strconv.ParseBool(v)
+	}
+
+	var interval time.Duration
+	if v := os.Getenv("IMAGE_GC_INTERVAL"); v != "" && v != "0" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Printf("Uyarı: IMAGE_GC_INTERVAL geçersiz (%s), görev devre dışı", v)
+		} else {
+			interval = d
+		}
+	}
+	return interval, opts
+}
+
+// Sahipsiz resim temizleme görevini arka planda periyodik olarak çalıştırır
+func StartImageGC(interval time.Duration, opts ImageGCOptions) {
+	go func() {
+		for {
+			time.Sleep(interval)
+
+			report, err := CollectOrphanImages(context.Background(), opts)
+			if err != nil {
+				log.Printf("Resim temizleme hatası: %v", err)
+				continue
+			}
+			log.Printf("Resim temizleme: %d dosya tarandı, %d sahipsiz, %d silindi, %d eksik referans (dry-run: %v)",
+				report.ScannedFiles, len(report.Orphans), len(report.Removed), len(report.Dangling), report.DryRun)
+		}
+	}()
+}
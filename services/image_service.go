@@ -6,6 +6,7 @@ import (
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -16,28 +17,52 @@ const (
 
 // Resim yükleme servisi
 func UploadImage(file multipart.File, header *multipart.FileHeader, directory string) (string, error) {
+	return SaveImage(file, filepath.Ext(header.Filename), directory)
+}
+
+// Resmi verilen dizine benzersiz bir adla kaydeder ve URL yolunu döner
+func SaveImage(src io.Reader, extension, directory string) (string, error) {
 	// Dizin yoksa oluştur
 	if err := os.MkdirAll(directory, 0755); err != nil {
 		return "", fmt.Errorf("dizin oluşturma hatası: %v", err)
 	}
 
-	// Benzersiz dosya adı oluştur
-	extension := filepath.Ext(header.Filename)
-	filename := fmt.Sprintf("%d%s", time.Now().UnixNano(), extension)
-	fullPath := filepath.Join(directory, filename)
+	// Benzersiz dosya adı oluştur; toplu yüklemede aynı zaman damgası gelirse tekrar dene
+	var dst *os.File
+	var fullPath string
+	for {
+		filename := fmt.Sprintf("%d%s", time.Now().UnixNano(), extension)
+		fullPath = filepath.Join(directory, filename)
 
-	// Dosyayı oluştur
-	dst, err := os.Create(fullPath)
-	if err != nil {
-		return "", fmt.Errorf("dosya oluşturma hatası: %v", err)
+		var err error
+		dst, err = os.OpenFile(fullPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return "", fmt.Errorf("dosya oluşturma hatası: %v", err)
+		}
 	}
 	defer dst.Close()
 
 	// Dosyayı kopyala
-	if _, err := io.Copy(dst, file); err != nil {
+	if _, err := io.Copy(dst, src); err != nil {
+		os.Remove(fullPath)
 		return "", fmt.Errorf("dosya kopyalama hatası: %v", err)
 	}
 
 	// URL yolunu döndür
 	return "/" + fullPath, nil
 }
+
+// Resim silme servisi
+func DeleteImage(url string) error {
+	if url == "" {
+		return nil
+	}
+	filePath := strings.TrimPrefix(url, "/")
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("dosya silme hatası: %v", err)
+	}
+	return nil
+}
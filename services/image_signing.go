@@ -0,0 +1,108 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// İmzasız erişime açık resimlerin dizini (logolar, statik görseller vb.)
+const PublicImagesPath = "images/public"
+
+const defaultImageURLTTL = time.Hour
+
+var (
+	ErrImageSignatureMissing = errors.New("resim imzası bulunamadı")
+	ErrImageSignatureInvalid = errors.New("resim imzası geçersiz")
+	ErrImageURLExpired       = errors.New("resim bağlantısının süresi dolmuş")
+)
+
+var (
+	imageSigningKey []byte
+	imageURLTTL     = defaultImageURLTTL
+)
+
+func init() {
+	godotenv.Load()
+
+	// İmza anahtarını environment variable'dan al, yoksa JWT anahtarını kullan
+	secret := os.Getenv("IMAGE_SIGNING_KEY")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	if secret == "" {
+		// Geliştirme için sabit değer kullan
+		secret = "your-256-bit-secret-key-here-make-it-long-and-secure"
+		log.Println("Uyarı: IMAGE_SIGNING_KEY bulunamadı, varsayılan değer kullanılıyor!")
+	}
+	imageSigningKey = []byte(secret)
+
+	if v := os.Getenv("IMAGE_URL_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			imageURLTTL = d
+		} else {
+			log.Printf("Uyarı: IMAGE_URL_TTL geçersiz (%s), varsayılan kullanılıyor", v)
+		}
+	}
+}
+
+func imageSignature(path string, expires int64) string {
+	mac := hmac.New(sha256.New, imageSigningKey)
+	fmt.Fprintf(mac, "%s|%d", path, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Resim yolunun imzasız erişime açık olup olmadığını kontrol eder
+func IsPublicImage(path string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(path, "/"), PublicImagesPath+"/")
+}
+
+// Resim yoluna son kullanma zamanı ve HMAC imzası ekler.
+// Boş ve herkese açık yollar olduğu gibi döner.
+func SignImageURL(path string) string {
+	if path == "" || IsPublicImage(path) {
+		return path
+	}
+	path = StripImageSignature(path)
+	expires := time.Now().Add(imageURLTTL).Unix()
+	return fmt.Sprintf("%s?expires=%d&sig=%s", path, expires, imageSignature(path, expires))
+}
+
+// İstemciden gelen imzalı bir URL'yi veritabanında saklanacak yola çevirir
+func StripImageSignature(url string) string {
+	if i := strings.IndexByte(url, '?'); i >= 0 {
+		return url[:i]
+	}
+	return url
+}
+
+// İmzayı ve son kullanma zamanını doğrular, geçerliyse son kullanma zamanını döner
+func VerifyImageURL(path, expiresParam, sig string) (time.Time, error) {
+	if expiresParam == "" || sig == "" {
+		return time.Time{}, ErrImageSignatureMissing
+	}
+
+	expires, err := strconv.ParseInt(expiresParam, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrImageSignatureInvalid
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(imageSignature(path, expires))) {
+		return time.Time{}, ErrImageSignatureInvalid
+	}
+
+	expiry := time.Unix(expires, 0)
+	if time.Now().After(expiry) {
+		return time.Time{}, ErrImageURLExpired
+	}
+	return expiry, nil
+}
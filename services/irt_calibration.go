@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/psychometrics"
+)
+
+// Öğrenci cevaplarından soruların IRT parametrelerini kalibre eder ve her öğrencinin
+// ana kategori bazında yetenek kestirimini günceller. Her öğrencinin bir soruya ilk
+// cevabı kullanılır; yeterli cevabı olmayan soruların önceki parametreleri korunur.
+func RunIRTCalibration(ctx context.Context, model string) (*models.IRTCalibrationReport, error) {
+	if model != psychometrics.Model2PL && model != psychometrics.Model3PL {
+		return nil, fmt.Errorf("desteklenmeyen IRT modeli: %s", model)
+	}
+	report := &models.IRTCalibrationReport{Model: model, StartedAt: time.Now()}
+	pool := db.GetPool()
+
+	rows, err := pool.Query(ctx, `
+        SELECT DISTINCT ON (a.user_id, a.question_id) a.user_id, a.question_id, a.is_correct
+        FROM question_attempts a
+        JOIN questions q ON q.id = a.question_id AND q.deleted_at IS NULL
+        ORDER BY a.user_id, a.question_id, a.created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("cevaplar alınamadı: %v", err)
+	}
+	var observations []psychometrics.Observation
+	for rows.Next() {
+		var o psychometrics.Observation
+		if err := rows.Scan(&o.Person, &o.Item, &o.Correct); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("cevap okunamadı: %v", err)
+		}
+		observations = append(observations, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cevaplar okunamadı: %v", err)
+	}
+	report.Responses = len(observations)
+
+	result := psychometrics.Calibrate(observations, psychometrics.DefaultCalibrationOptions(model))
+
+	// Soruların bağlı olduğu ana kategoriler
+	mainsByQuestion := make(map[int][]int)
+	rows, err = pool.Query(ctx, `
+        SELECT DISTINCT qc.question_id, mcsc.main_category_id
+        FROM question_categories qc
+        JOIN categories c ON c.id = qc.category_id AND c.deleted_at IS NULL
+        JOIN main_category_sub_category mcsc ON mcsc.sub_category_id = c.sub_category_id
+        JOIN main_categories mc ON mc.id = mcsc.main_category_id AND mc.deleted_at IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("soru kategorileri alınamadı: %v", err)
+	}
+	for rows.Next() {
+		var questionID, mainID int
+		if err := rows.Scan(&questionID, &mainID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("soru kategorisi okunamadı: %v", err)
+		}
+		mainsByQuestion[questionID] = append(mainsByQuestion[questionID], mainID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("soru kategorileri okunamadı: %v", err)
+	}
+
+	// Öğrenci × ana kategori bazında kalibre edilmiş sorulara verilen cevaplar
+	type abilityKey struct{ user, main int }
+	responses := make(map[abilityKey][]psychometrics.Response)
+	for _, o := range observations {
+		item, ok := result.Items[o.Item]
+		if !ok {
+			continue
+		}
+		for _, mainID := range mainsByQuestion[o.Item] {
+			key := abilityKey{o.Person, mainID}
+			responses[key] = append(responses[key], psychometrics.Response{Item: item.Item, Correct: o.Correct})
+		}
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("transaction başlatma hatası: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for questionID, item := range result.Items {
+		if _, err := tx.Exec(ctx, `
+            UPDATE questions
+            SET irt_model = $1, irt_a = $2, irt_b = $3, irt_c = $4,
+                irt_responses = $5, irt_calibrated_at = CURRENT_TIMESTAMP
+            WHERE id = $6`,
+			model, item.A, item.B, item.C, item.Count, questionID); err != nil {
+			return nil, fmt.Errorf("soru %d parametreleri kaydedilemedi: %v", questionID, err)
+		}
+	}
+	report.Questions = len(result.Items)
+
+	for key, rs := range responses {
+		theta, se := psychometrics.EstimateAbility(rs)
+		if _, err := tx.Exec(ctx, `
+            INSERT INTO user_abilities (user_id, main_category_id, theta, standard_error, responses, updated_at)
+            VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
+            ON CONFLICT (user_id, main_category_id) DO UPDATE
+            SET theta = EXCLUDED.theta, standard_error = EXCLUDED.standard_error,
+                responses = EXCLUDED.responses, updated_at = EXCLUDED.updated_at`,
+			key.user, key.main, theta, se, len(rs)); err != nil {
+			return nil, fmt.Errorf("yetenek kestirimi kaydedilemedi: %v", err)
+		}
+	}
+	report.Abilities = len(responses)
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("transaction commit hatası: %v", err)
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"osymapp/db"
+	"osymapp/models"
+)
+
+// Olay ağırlıkları ve skorun yarı ömrü
+const (
+	popularityViewWeight     = 1
+	popularityAttemptWeight  = 3
+	popularityBookmarkWeight = 5
+	popularityHalfLifeDays   = 7
+	popularityRetentionDays  = 90
+)
+
+type questionEvent struct {
+	questionID int
+	kind       string
+	at         time.Time
+}
+
+type questionEventKey struct {
+	questionID int
+	day        string
+}
+
+type questionEventCounts struct {
+	views, attempts, bookmarks int
+}
+
+type questionEventDedupeKey struct {
+	actor      string
+	questionID int
+	kind       string
+}
+
+var (
+	questionEvents        = make(chan questionEvent, 10000)
+	droppedQuestionEvents atomic.Int64
+)
+
+// Gün içinde kuyruğa alınmış (kullanıcı, soru, olay) üçlüleri; gün değişince sıfırlanır.
+// Sayfa yenileme veya kaydet/kaldır döngüsü skoru şişirmesin diye aynı üçlü günde bir kez sayılır.
+var questionEventDedupe = struct {
+	sync.Mutex
+	day  string
+	seen map[questionEventDedupeKey]bool
+}{seen: make(map[questionEventDedupeKey]bool)}
+
+// Üye kullanıcının olay kimliği; misafirler token'daki misafir kimliğiyle ayrılır
+func UserEventActor(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
+
+// Soru olayını kuyruğa ekler; istek yolunu bekletmemek için kuyruk doluysa olay atılır.
+// Aynı kullanıcıdan aynı gün gelen tekrar olaylar kuyruğa hiç alınmaz.
+func RecordQuestionEvent(actor string, questionID int, kind string) {
+	now := time.Now()
+	if !firstQuestionEventOfDay(actor, questionID, kind, now) {
+		return
+	}
+
+	select {
+	case questionEvents <- questionEvent{questionID: questionID, kind: kind, at: now}:
+	default:
+		droppedQuestionEvents.Add(1)
+	}
+}
+
+func firstQuestionEventOfDay(actor string, questionID int, kind string, now time.Time) bool {
+	if actor == "" {
+		return true
+	}
+
+	d := &questionEventDedupe
+	d.Lock()
+	defer d.Unlock()
+
+	if day := now.Format("2006-01-02"); d.day != day {
+		d.day = day
+		d.seen = make(map[questionEventDedupeKey]bool)
+	}
+	key := questionEventDedupeKey{actor: actor, questionID: questionID, kind: kind}
+	if d.seen[key] {
+		return false
+	}
+	d.seen[key] = true
+	return true
+}
+
+// Olayları bellekte toplayıp flushInterval'da bir toplu yazar, popülerlik skorlarını
+// da scoreInterval'da bir yeniden hesaplar. Aynı soruya gelen olaylar tek satır
+// güncellemesinde birleştiği için popüler sorularda satır kilidi çekişmesi oluşmaz.
+func StartPopularityPipeline(flushInterval, scoreInterval time.Duration) {
+	go func() {
+		pending := make(map[questionEventKey]*questionEventCounts)
+		flush := time.NewTicker(flushInterval)
+		score := time.NewTicker(scoreInterval)
+		defer flush.Stop()
+		defer score.Stop()
+
+		for {
+			select {
+			case ev := <-questionEvents:
+				key := questionEventKey{questionID: ev.questionID, day: ev.at.Format("2006-01-02")}
+				c := pending[key]
+				if c == nil {
+					c = &questionEventCounts{}
+					pending[key] = c
+				}
+				switch ev.kind {
+				case models.QuestionEventView:
+					c.views++
+				case models.QuestionEventAttempt:
+					c.attempts++
+				case models.QuestionEventBookmark:
+					c.bookmarks++
+				}
+
+			case <-flush.C:
+				if n := droppedQuestionEvents.Swap(0); n > 0 {
+					log.Printf("Popülerlik: kuyruk dolu olduğu için %d olay atıldı", n)
+				}
+				if len(pending) == 0 {
+					continue
+				}
+				if err := flushQuestionEvents(context.Background(), pending); err != nil {
+					// Bir sonraki denemede tekrar yazılmak üzere bellekte tutulur
+					log.Printf("Popülerlik olayları yazılamadı: %v", err)
+					continue
+				}
+				pending = make(map[questionEventKey]*questionEventCounts)
+
+			case <-score.C:
+				if err := RecomputeQuestionPopularity(context.Background()); err != nil {
+					log.Printf("Popülerlik skoru hesaplanamadı: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func flushQuestionEvents(ctx context.Context, pending map[questionEventKey]*questionEventCounts) error {
+	keys := make([]questionEventKey, 0, len(pending))
+	for k := range pending {
+		keys = append(keys, k)
+	}
+	// Eşzamanlı yazımlarda kilitlenmeyi önlemek için satırlar sabit sırayla güncellenir
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].questionID != keys[j].questionID {
+			return keys[i].questionID < keys[j].questionID
+		}
+		return keys[i].day < keys[j].day
+	})
+
+	ids := make([]int, len(keys))
+	days := make([]string, len(keys))
+	views := make([]int, len(keys))
+	attempts := make([]int, len(keys))
+	bookmarks := make([]int, len(keys))
+	for i, k := range keys {
+		c := pending[k]
+		ids[i], days[i] = k.questionID, k.day
+		views[i], attempts[i], bookmarks[i] = c.views, c.attempts, c.bookmarks
+	}
+
+	// Bu arada kalıcı olarak silinmiş sorulara ait olaylar atlanır
+	_, err := db.GetPool().Exec(ctx, `
+        INSERT INTO question_event_counts (question_id, day, views, attempts, bookmarks)
+        SELECT e.question_id, e.day::date, e.views, e.attempts, e.bookmarks
+        FROM unnest($1::int[], $2::text[], $3::int[], $4::int[], $5::int[])
+             AS e(question_id, day, views, attempts, bookmarks)
+        WHERE EXISTS (SELECT 1 FROM questions q WHERE q.id = e.question_id)
+        ON CONFLICT (question_id, day) DO UPDATE
+        SET views = question_event_counts.views + EXCLUDED.views,
+            attempts = question_event_counts.attempts + EXCLUDED.attempts,
+            bookmarks = question_event_counts.bookmarks + EXCLUDED.bookmarks`,
+		ids, days, views, attempts, bookmarks)
+	return err
+}
+
+// Popülerliği günlük olay toplamlarının zamanla azalan ağırlıklı toplamı olarak,
+// haftalık popülerliği de son 7 günün toplamı olarak günceller. Yalnızca değeri
+// değişen sorular güncellenir.
+func RecomputeQuestionPopularity(ctx context.Context) error {
+	pool := db.GetPool()
+	if _, err := pool.Exec(ctx,
+		"DELETE FROM question_event_counts WHERE day < current_date - $1::int",
+		popularityRetentionDays); err != nil {
+		return fmt.Errorf("eski olaylar silinemedi: %v", err)
+	}
+
+	_, err := pool.Exec(ctx, `
+        WITH Scores AS (
+            SELECT question_id,
+                   SUM((views * $1 + attempts * $2 + bookmarks * $3)
+                       * exp(-(current_date - day) * ln(2) / $4)) AS score,
+                   SUM(views * $1 + attempts * $2 + bookmarks * $3)
+                       FILTER (WHERE day > current_date - 7) AS week
+            FROM question_event_counts
+            GROUP BY question_id
+        ),
+        Target AS (
+            SELECT q.id,
+                   COALESCE(round(s.score), 0)::int AS popularity,
+                   COALESCE(s.week, 0)::int AS popularity_week
+            FROM questions q
+            LEFT JOIN Scores s ON s.question_id = q.id
+        )
+        UPDATE questions q
+        SET popularity = t.popularity, popularity_week = t.popularity_week
+        FROM Target t
+        WHERE q.id = t.id
+          AND (q.popularity IS DISTINCT FROM t.popularity OR q.popularity_week <> t.popularity_week)`,
+		popularityViewWeight, popularityAttemptWeight, popularityBookmarkWeight, float64(popularityHalfLifeDays))
+	if err != nil {
+		return fmt.Errorf("popülerlik güncellenemedi: %v", err)
+	}
+	return nil
+}
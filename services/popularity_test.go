@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"osymapp/models"
+)
+
+func TestFirstQuestionEventOfDayDedupesPerUserQuestionAndDay(t *testing.T) {
+	day := time.Date(2024, 3, 10, 9, 0, 0, 0, time.Local)
+	alice, bob := UserEventActor(1), UserEventActor(2)
+
+	steps := []struct {
+		name     string
+		actor    string
+		question int
+		kind     string
+		at       time.Time
+		want     bool
+	}{
+		{"ilk görüntüleme", alice, 7, models.QuestionEventView, day, true},
+		{"sayfa yenileme", alice, 7, models.QuestionEventView, day.Add(time.Minute), false},
+		{"başka soru", alice, 8, models.QuestionEventView, day.Add(time.Minute), true},
+		{"başka kullanıcı", bob, 7, models.QuestionEventView, day.Add(time.Minute), true},
+		{"ilk kaydetme", alice, 7, models.QuestionEventBookmark, day.Add(time.Hour), true},
+		{"kaldırıp yeniden kaydetme", alice, 7, models.QuestionEventBookmark, day.Add(2 * time.Hour), false},
+		{"ertesi gün", alice, 7, models.QuestionEventView, day.AddDate(0, 0, 1), true},
+		{"kimliksiz olay", "", 7, models.QuestionEventView, day.AddDate(0, 0, 1), true},
+		{"kimliksiz olay tekrar", "", 7, models.QuestionEventView, day.AddDate(0, 0, 1), true},
+	}
+
+	for _, s := range steps {
+		if got := firstQuestionEventOfDay(s.actor, s.question, s.kind, s.at); got != s.want {
+			t.Fatalf("%s: firstQuestionEventOfDay = %v, beklenen %v", s.name, got, s.want)
+		}
+	}
+}
@@ -0,0 +1,520 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"osymapp/models"
+)
+
+const (
+	maxQuestionBodyFieldLength = 20000
+	maxQuestionOptions         = 10
+)
+
+// Güvenlik nedeniyle matematik ifadelerinde izin verilmeyen komutlar
+// (makro tanımı, dosya erişimi ve istemcide HTML/bağlantı üreten komutlar)
+var forbiddenLatexCommands = map[string]bool{
+	"def": true, "gdef": true, "edef": true, "xdef": true, "let": true,
+	"newcommand": true, "renewcommand": true, "providecommand": true,
+	"newenvironment": true, "renewenvironment": true,
+	"input": true, "include": true, "write": true, "immediate": true, "openout": true,
+	"read": true, "catcode": true, "csname": true,
+	"href": true, "url": true, "includegraphics": true,
+	"html": true, "htmlClass": true, "htmlId": true, "htmlStyle": true, "htmlData": true,
+}
+
+var allowedLatexEnvironments = map[string]bool{
+	"matrix": true, "pmatrix": true, "bmatrix": true, "Bmatrix": true, "vmatrix": true, "Vmatrix": true,
+	"smallmatrix": true, "cases": true, "array": true, "aligned": true, "align": true, "align*": true,
+	"gathered": true, "split": true, "equation": true, "equation*": true,
+}
+
+var (
+	markdownLinkPattern = regexp.MustCompile(`(!?)\[([^\]]*)\]\(\s*([^)\s]*)((?:\s+"[^"]*")?)\s*\)`)
+	htmlTagPattern      = regexp.MustCompile(`(?s)<!--.*?-->|</?[a-zA-Z][^>]*>`)
+	// [etiket]: adres biçimindeki bağlantı tanımları; adresleri satır içi bağlantılar gibi denetlenemez
+	markdownLinkDefinitionPattern = regexp.MustCompile(`(?m)^[ \t]*(\[)[^\]]+\]:`)
+)
+
+type bodySegmentKind int
+
+const (
+	segmentText bodySegmentKind = iota
+	segmentCode
+	segmentMath
+)
+
+type bodySegment struct {
+	kind    bodySegmentKind
+	text    string // ayraçlar dahil kaynak metin
+	content string // matematik ifadesinin ayraçsız içeriği
+}
+
+// Boş metni nil'e çevirir, boşlukları kırpar ve resim bağlantılarındaki imzaları temizler
+func NormalizeQuestionBody(b *models.QuestionBody) *models.QuestionBody {
+	if b == nil {
+		return nil
+	}
+
+	out := &models.QuestionBody{
+		Stem:     normalizeQuestionText(b.Stem),
+		Solution: normalizeQuestionText(b.Solution),
+	}
+	for _, o := range b.Options {
+		out.Options = append(out.Options, models.QuestionOption{
+			Label: strings.TrimSpace(o.Label),
+			Text:  normalizeQuestionText(o.Text),
+		})
+	}
+
+	if out.Stem == "" && out.Solution == "" && len(out.Options) == 0 {
+		return nil
+	}
+	return out
+}
+
+func normalizeQuestionText(s string) string {
+	s = strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n"))
+	return rewriteMarkdownLinks(s, func(isImage bool, url string) string {
+		return StripImageSignature(url)
+	})
+}
+
+// Kaydetmeden önce Markdown ve LaTeX sözdizimini kontrol eder
+func ValidateQuestionBody(b *models.QuestionBody) []models.QuestionBodyError {
+	errs := []models.QuestionBodyError{}
+	if b == nil {
+		return errs
+	}
+
+	add := func(field string, messages ...string) {
+		for _, m := range messages {
+			errs = append(errs, models.QuestionBodyError{Field: field, Message: m})
+		}
+	}
+
+	if b.Stem == "" {
+		add("stem", "soru metni boş olamaz")
+	}
+	add("stem", validateQuestionText(b.Stem)...)
+	add("solution", validateQuestionText(b.Solution)...)
+
+	if len(b.Options) > maxQuestionOptions {
+		add("options", fmt.Sprintf("en fazla %d seçenek olabilir", maxQuestionOptions))
+	}
+	labels := make(map[string]bool)
+	for i, o := range b.Options {
+		field := fmt.Sprintf("options[%d]", i)
+		switch {
+		case o.Label == "":
+			add(field+".label", "seçenek etiketi boş olamaz")
+		case utf8.RuneCountInString(o.Label) > 8:
+			add(field+".label", "seçenek etiketi en fazla 8 karakter olabilir")
+		case labels[strings.ToUpper(o.Label)]:
+			add(field+".label", "seçenek etiketi tekrar ediyor: "+o.Label)
+		}
+		labels[strings.ToUpper(o.Label)] = true
+
+		if o.Text == "" {
+			add(field+".text", "seçenek metni boş olamaz")
+		}
+		add(field+".text", validateQuestionText(o.Text)...)
+	}
+
+	return errs
+}
+
+func validateQuestionText(s string) []string {
+	if s == "" {
+		return nil
+	}
+	if utf8.RuneCountInString(s) > maxQuestionBodyFieldLength {
+		return []string{fmt.Sprintf("metin en fazla %d karakter olabilir", maxQuestionBodyFieldLength)}
+	}
+
+	segments, errs := splitQuestionText(s)
+
+	var prose strings.Builder
+	htmlReported := false
+	for _, seg := range segments {
+		switch seg.kind {
+		case segmentMath:
+			if strings.TrimSpace(seg.content) == "" {
+				errs = append(errs, "boş matematik ifadesi: "+seg.text)
+			}
+			errs = append(errs, validateLatex(seg.content)...)
+			prose.WriteString("x")
+		case segmentCode:
+			prose.WriteString("x")
+		default:
+			// Ayraçlara bölünmüş etiketler (<img title="$a$">) kaçmasın diye düz metinde < ve > kabul edilmez
+			if !htmlReported && strings.ContainsAny(seg.text, "<>") {
+				errs = append(errs, "< ve > yalnızca matematik ifadesi veya kod içinde kullanılabilir")
+				htmlReported = true
+			}
+			prose.WriteString(seg.text)
+		}
+	}
+
+	// Kalın yazı işaretleri her paragrafta çift olmalı
+	for _, paragraph := range strings.Split(prose.String(), "\n\n") {
+		if strings.Count(paragraph, "**")%2 != 0 {
+			errs = append(errs, "kapatılmamış kalın yazı (**)")
+			break
+		}
+	}
+
+	for _, m := range markdownLinkPattern.FindAllStringSubmatch(prose.String(), -1) {
+		if !isSafeMarkdownURL(m[1] == "!", m[3]) {
+			errs = append(errs, "izin verilmeyen bağlantı: "+m[3])
+		}
+	}
+	if markdownLinkDefinitionPattern.MatchString(prose.String()) {
+		errs = append(errs, "bağlantı tanımları ([etiket]: adres) kullanılamaz; satır içi bağlantı kullanın")
+	}
+
+	return errs
+}
+
+// Metni düz metin, kod ve matematik parçalarına ayırır. Kod içindeki $ işaretleri
+// matematik sayılmaz; \$ ile kaçırılan dolar işareti düz metindir.
+func splitQuestionText(s string) ([]bodySegment, []string) {
+	var segments []bodySegment
+	var errs []string
+
+	textStart := 0
+	flush := func(end int) {
+		if end > textStart {
+			segments = append(segments, bodySegment{kind: segmentText, text: s[textStart:end]})
+		}
+	}
+	emit := func(start, end int, seg bodySegment) {
+		flush(start)
+		seg.text = s[start:end]
+		segments = append(segments, seg)
+		textStart = end
+	}
+
+	i := 0
+	for i < len(s) {
+		switch {
+		case strings.HasPrefix(s[i:], "```") && (i == 0 || s[i-1] == '\n'):
+			closing := strings.Index(s[i+3:], "\n```")
+			if closing < 0 {
+				errs = append(errs, "kapatılmamış kod bloğu (```)")
+				i = len(s)
+				continue
+			}
+			end := i + 3 + closing + 4
+			if nl := strings.IndexByte(s[end:], '\n'); nl >= 0 {
+				end += nl
+			} else {
+				end = len(s)
+			}
+			emit(i, end, bodySegment{kind: segmentCode})
+			i = end
+
+		case s[i] == '`':
+			n := 1
+			for i+n < len(s) && s[i+n] == '`' {
+				n++
+			}
+			closing := strings.Index(s[i+n:], strings.Repeat("`", n))
+			if closing < 0 {
+				// Eşi olmayan ters tırnak düz metindir
+				i += n
+				continue
+			}
+			end := i + n + closing + n
+			emit(i, end, bodySegment{kind: segmentCode})
+			i = end
+
+		case s[i] == '\\' && i+1 < len(s) && (s[i+1] == '(' || s[i+1] == '['):
+			closer := `\)`
+			if s[i+1] == '[' {
+				closer = `\]`
+			}
+			closing := strings.Index(s[i+2:], closer)
+			if closing < 0 {
+				errs = append(errs, fmt.Sprintf("kapatılmamış matematik ifadesi (%s)", s[i:i+2]))
+				i = len(s)
+				continue
+			}
+			end := i + 2 + closing + 2
+			emit(i, end, bodySegment{kind: segmentMath, content: s[i+2 : i+2+closing]})
+			i = end
+
+		case s[i] == '\\':
+			i += 2
+
+		case s[i] == '$':
+			delim := "$"
+			if strings.HasPrefix(s[i:], "$$") {
+				delim = "$$"
+			}
+			closing := indexUnescaped(s[i+len(delim):], delim)
+			if closing < 0 {
+				errs = append(errs, fmt.Sprintf("kapatılmamış matematik ifadesi (%s)", delim))
+				i = len(s)
+				continue
+			}
+			start := i + len(delim)
+			end := start + closing + len(delim)
+			emit(i, end, bodySegment{kind: segmentMath, content: s[start : start+closing]})
+			i = end
+
+		default:
+			i++
+		}
+	}
+	flush(len(s))
+
+	return segments, errs
+}
+
+func indexUnescaped(s, delim string) int {
+	for i := 0; i <= len(s)-len(delim); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], delim) {
+			return i
+		}
+	}
+	return -1
+}
+
+// LaTeX ifadesinde süslü parantez, \left/\right ve \begin/\end dengesini ve
+// yasaklı komutları kontrol eder
+func validateLatex(src string) []string {
+	var errs []string
+	depth, leftRight := 0, 0
+	var envs []string
+
+	for i := 0; i < len(src); {
+		switch src[i] {
+		case '\\':
+			if i+1 >= len(src) {
+				errs = append(errs, "ifade ters bölü ile bitemez")
+				i++
+				continue
+			}
+			j := i + 1
+			for j < len(src) && isASCIILetter(src[j]) {
+				j++
+			}
+			if j == i+1 {
+				// \{, \}, \, gibi tek karakterli komutlar
+				i += 2
+				continue
+			}
+			name := src[i+1 : j]
+			i = j
+
+			switch name {
+			case "left":
+				leftRight++
+			case "right":
+				if leftRight == 0 {
+					errs = append(errs, `\right için eşleşen \left yok`)
+				} else {
+					leftRight--
+				}
+			case "begin", "end":
+				env, next, ok := readLatexGroup(src, i)
+				if !ok {
+					errs = append(errs, fmt.Sprintf(`\%s sonrası ortam adı bekleniyor`, name))
+					continue
+				}
+				i = next
+				if name == "begin" {
+					if !allowedLatexEnvironments[env] {
+						errs = append(errs, "desteklenmeyen ortam: "+env)
+					}
+					envs = append(envs, env)
+				} else if len(envs) == 0 || envs[len(envs)-1] != env {
+					errs = append(errs, fmt.Sprintf(`\end{%s} eşleşen \begin olmadan kullanılmış`, env))
+				} else {
+					envs = envs[:len(envs)-1]
+				}
+			default:
+				if forbiddenLatexCommands[name] {
+					errs = append(errs, `izin verilmeyen komut: \`+name)
+				}
+			}
+		case '{':
+			depth++
+			i++
+		case '}':
+			if depth == 0 {
+				errs = append(errs, "eşleşmeyen }")
+			} else {
+				depth--
+			}
+			i++
+		default:
+			i++
+		}
+	}
+
+	if depth > 0 {
+		errs = append(errs, "kapatılmamış {")
+	}
+	if leftRight > 0 {
+		errs = append(errs, `\left için eşleşen \right yok`)
+	}
+	for _, env := range envs {
+		errs = append(errs, "kapatılmamış ortam: "+env)
+	}
+	return errs
+}
+
+func readLatexGroup(src string, i int) (string, int, bool) {
+	for i < len(src) && src[i] == ' ' {
+		i++
+	}
+	if i >= len(src) || src[i] != '{' {
+		return "", i, false
+	}
+	end := strings.IndexByte(src[i:], '}')
+	if end < 0 {
+		return "", i, false
+	}
+	return src[i+1 : i+end], i + end + 1, true
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// Bağlantılar yalnızca http(s)/mailto, resimler yalnızca http(s) veya sunucudaki /images/ yolu olabilir
+func isSafeMarkdownURL(isImage bool, url string) bool {
+	lower := strings.ToLower(url)
+	switch {
+	case strings.HasPrefix(lower, "https://"), strings.HasPrefix(lower, "http://"):
+		return true
+	case strings.HasPrefix(lower, "/images/"):
+		return true
+	case strings.HasPrefix(lower, "mailto:"):
+		return !isImage
+	}
+	return false
+}
+
+func rewriteMarkdownLinks(s string, rewrite func(isImage bool, url string) string) string {
+	return markdownLinkPattern.ReplaceAllStringFunc(s, func(link string) string {
+		m := markdownLinkPattern.FindStringSubmatch(link)
+		return fmt.Sprintf("%s[%s](%s%s)", m[1], m[2], rewrite(m[1] == "!", m[3]), m[4])
+	})
+}
+
+// Çıktıdan önce metni temizler: kod ve matematik dışındaki HTML etiketleri kaldırılır,
+// kalan < ve > kaçırılır, güvensiz bağlantılar etkisizleştirilir ve sunucudaki resimler imzalanır
+func SanitizeQuestionBody(b *models.QuestionBody) *models.QuestionBody {
+	if b == nil {
+		return nil
+	}
+
+	out := &models.QuestionBody{
+		Stem:     sanitizeQuestionText(b.Stem),
+		Solution: sanitizeQuestionText(b.Solution),
+	}
+	for _, o := range b.Options {
+		out.Options = append(out.Options, models.QuestionOption{
+			Label: stripQuestionHTML(o.Label),
+			Text:  sanitizeQuestionText(o.Text),
+		})
+	}
+	return out
+}
+
+func sanitizeQuestionText(s string) string {
+	segments, _ := splitQuestionText(s)
+
+	var b strings.Builder
+	for _, seg := range segments {
+		if seg.kind != segmentText {
+			b.WriteString(seg.text)
+			continue
+		}
+		// Bir etiket matematik veya kod ayracıyla bölünmüş olabilir; bu yüzden etiketler
+		// kaldırıldıktan sonra parçada kalan < ve > de kaçırılır
+		text := stripQuestionHTML(seg.text)
+		text = rewriteMarkdownLinks(text, func(isImage bool, url string) string {
+			if !isSafeMarkdownURL(isImage, url) {
+				return "#"
+			}
+			if strings.HasPrefix(url, "/images/") {
+				return SignImageURL(StripImageSignature(url))
+			}
+			return url
+		})
+		b.WriteString(text)
+	}
+	return escapeLinkDefinitions(b.String())
+}
+
+// Düz metindeki bağlantı tanımlarının açılış köşeli parantezini kaçırır; tanım düz metne döner.
+// Etiketler kaldırıldıktan sonra satır başına gelen tanımlar da yakalansın diye temizlenmiş metne uygulanır.
+func escapeLinkDefinitions(s string) string {
+	matches := markdownLinkDefinitionPattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	segments, _ := splitQuestionText(s)
+	inText := func(pos int) bool {
+		offset := 0
+		for _, seg := range segments {
+			if pos < offset+len(seg.text) {
+				return seg.kind == segmentText
+			}
+			offset += len(seg.text)
+		}
+		return false
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if !inText(m[2]) {
+			continue
+		}
+		b.WriteString(s[last:m[2]])
+		b.WriteString(`\`)
+		last = m[2]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+var htmlBracketReplacer = strings.NewReplacer("<", "&lt;", ">", "&gt;")
+
+func stripQuestionHTML(s string) string {
+	return htmlBracketReplacer.Replace(htmlTagPattern.ReplaceAllString(s, ""))
+}
+
+// Metinde gömülü, sunucuda saklanan (/images/...) resimlerin yolları
+func QuestionBodyImageURLs(b *models.QuestionBody) []string {
+	if b == nil {
+		return nil
+	}
+
+	var urls []string
+	texts := []string{b.Stem, b.Solution}
+	for _, o := range b.Options {
+		texts = append(texts, o.Text)
+	}
+	for _, text := range texts {
+		for _, m := range markdownLinkPattern.FindAllStringSubmatch(text, -1) {
+			if m[1] == "!" && strings.HasPrefix(m[3], "/images/") {
+				urls = append(urls, StripImageSignature(m[3]))
+			}
+		}
+	}
+	return urls
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"osymapp/models"
+)
+
+func TestSanitizeQuestionTextEscapesSplitTags(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "matematik ile bölünmüş öznitelik",
+			in:   `<img src=x onerror=alert(1) title="$a$">`,
+			want: `&lt;img src=x onerror=alert(1) title="$a$"&gt;`,
+		},
+		{
+			name: "satır içi kod ile bölünmüş etiket",
+			in:   "<img src=x onerror=alert(1) `x`>",
+			want: "&lt;img src=x onerror=alert(1) `x`&gt;",
+		},
+		{
+			name: "kod bloğu ile bölünmüş etiket",
+			in:   "<svg onload=alert(1)\n```\nx\n```\n>",
+			want: "&lt;svg onload=alert(1)\n```\nx\n```\n&gt;",
+		},
+		{
+			name: "tam etiket kaldırılır",
+			in:   "<b>kalın</b> metin",
+			want: "kalın metin",
+		},
+		{
+			name: "matematik içindeki karşılaştırma korunur",
+			in:   `$a < b$ ve $c > d$`,
+			want: `$a < b$ ve $c > d$`,
+		},
+		{
+			name: "kod içindeki etiket korunur",
+			in:   "`<img>`",
+			want: "`<img>`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeQuestionText(tt.in); got != tt.want {
+				t.Fatalf("sanitizeQuestionText(%q) = %q, beklenen %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateQuestionBodyRejectsHTMLOutsideMath(t *testing.T) {
+	tests := []struct {
+		name    string
+		stem    string
+		wantErr bool
+	}{
+		{"matematik ile bölünmüş öznitelik", `<img src=x onerror=alert(1) title="$a$">`, true},
+		{"satır içi kod ile bölünmüş etiket", "<img src=x onerror=alert(1) `x`>", true},
+		{"düz metinde karşılaştırma", "x > 3 ise", true},
+		{"matematik içinde karşılaştırma", `$x > 3$ ise $y < 2$`, false},
+		{"kod içinde etiket", "`<br>` etiketi", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateQuestionBody(&models.QuestionBody{Stem: tt.stem})
+			found := false
+			for _, e := range errs {
+				if e.Field == "stem" && strings.Contains(e.Message, "<") {
+					found = true
+				}
+			}
+			if found != tt.wantErr {
+				t.Fatalf("ValidateQuestionBody(%q) hataları = %v, HTML hatası bekleniyor: %v", tt.stem, errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuestionTextLinkDefinitionsAndAutolinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		want     string
+		wantErrs bool
+	}{
+		{
+			name:     "bağlantı tanımı",
+			in:       "[tıkla][x]\n\n[x]: javascript:alert(1)",
+			want:     "[tıkla][x]\n\n\\[x]: javascript:alert(1)",
+			wantErrs: true,
+		},
+		{
+			name:     "girintili bağlantı tanımı",
+			in:       "  [x]: https://example.com",
+			want:     "  \\[x]: https://example.com",
+			wantErrs: true,
+		},
+		{
+			name:     "etiket kaldırılınca satır başına gelen tanım",
+			in:       "<b>[x]: javascript:alert(1)",
+			want:     "\\[x]: javascript:alert(1)",
+			wantErrs: true,
+		},
+		{
+			name:     "otomatik bağlantı",
+			in:       "<javascript:alert(1)> bağlantısı",
+			want:     " bağlantısı",
+			wantErrs: true,
+		},
+		{
+			name: "kod içindeki tanım korunur",
+			in:   "```\n[x]: y\n```",
+			want: "```\n[x]: y\n```",
+		},
+		{
+			name: "satır içi bağlantı",
+			in:   "[kaynak](https://example.com)",
+			want: "[kaynak](https://example.com)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeQuestionText(tt.in); got != tt.want {
+				t.Fatalf("sanitizeQuestionText(%q) = %q, beklenen %q", tt.in, got, tt.want)
+			}
+			if errs := validateQuestionText(tt.in); (len(errs) > 0) != tt.wantErrs {
+				t.Fatalf("validateQuestionText(%q) = %v, hata bekleniyor: %v", tt.in, errs, tt.wantErrs)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"osymapp/db"
+	"osymapp/models"
+)
+
+var ErrInvalidDifficulty = errors.New("zorluk seviyesi 1-5 arasında olmalı (1 çok kolay, 5 çok zor)")
+
+// Eski serbest metin değerlerinin ölçekteki karşılıkları
+var difficultyAliases = map[string]string{
+	"1":         models.DifficultyVeryEasy,
+	"çok kolay": models.DifficultyVeryEasy,
+	"cok kolay": models.DifficultyVeryEasy,
+	"very easy": models.DifficultyVeryEasy,
+	"very_easy": models.DifficultyVeryEasy,
+	"2":         models.DifficultyEasy,
+	"kolay":     models.DifficultyEasy,
+	"easy":      models.DifficultyEasy,
+	"3":         models.DifficultyMedium,
+	"orta":      models.DifficultyMedium,
+	"medium":    models.DifficultyMedium,
+	"normal":    models.DifficultyMedium,
+	"4":         models.DifficultyHard,
+	"zor":       models.DifficultyHard,
+	"hard":      models.DifficultyHard,
+	"5":         models.DifficultyVeryHard,
+	"çok zor":   models.DifficultyVeryHard,
+	"cok zor":   models.DifficultyVeryHard,
+	"very hard": models.DifficultyVeryHard,
+	"very_hard": models.DifficultyVeryHard,
+}
+
+// Zorluk değerini 1-5 ölçeğine çevirir. Boş değer etiketsiz olarak kabul edilir.
+func NormalizeDifficulty(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	for _, key := range []string{strings.ToLower(value), strings.ToLowerSpecial(unicode.TurkishCase, value)} {
+		if level, ok := difficultyAliases[key]; ok {
+			return level, nil
+		}
+	}
+	return "", ErrInvalidDifficulty
+}
+
+const (
+	// Gözlenen zorluğun güvenilir sayılması için gereken en az cevap sayısı
+	minCalibrationAttempts = 30
+	// Ayırt edicilik bu değerin altındaysa soru işaretlenir
+	lowDiscriminationThreshold = 0.1
+)
+
+// Doğru cevaplama oranını (p) 1-5 ölçeğine çevirir
+func observedDifficultyLevel(p float64) string {
+	switch {
+	case p >= 0.85:
+		return models.DifficultyVeryEasy
+	case p >= 0.65:
+		return models.DifficultyEasy
+	case p >= 0.40:
+		return models.DifficultyMedium
+	case p >= 0.20:
+		return models.DifficultyHard
+	}
+	return models.DifficultyVeryHard
+}
+
+// Etiket ile gözlenen seviye arasında en az iki basamak fark varsa veya soru
+// güçlü ve zayıf öğrencileri ayırt edemiyorsa işaretlenir
+func difficultyFlags(labelled, observed string, discrimination *float64) []string {
+	flags := []string{}
+	if labelled != "" {
+		diff := int(observed[0]) - int(labelled[0])
+		if diff <= -2 {
+			flags = append(flags, models.DifficultyFlagEasierThanLabelled)
+		} else if diff >= 2 {
+			flags = append(flags, models.DifficultyFlagHarderThanLabelled)
+		}
+	}
+	if discrimination != nil && *discrimination < lowDiscriminationThreshold {
+		flags = append(flags, models.DifficultyFlagLowDiscrimination)
+	}
+	return flags
+}
+
+// Öğrenci cevaplarından her sorunun p değerini ve ayırt edicilik indeksini hesaplar.
+// Her öğrencinin bir soruya ilk cevabı kullanılır; ayırt edicilik, toplam başarıya
+// göre üst %27 ile alt %27 öğrenci grubunun doğru oranı farkıdır.
+func CalibrateQuestionDifficulty(ctx context.Context) (*models.DifficultyCalibrationReport, error) {
+	pool := db.GetPool()
+	rows, err := pool.Query(ctx, `
+        WITH FirstAttempts AS (
+            SELECT DISTINCT ON (a.user_id, a.question_id) a.user_id, a.question_id, a.is_correct
+            FROM question_attempts a
+            JOIN questions q ON q.id = a.question_id AND q.deleted_at IS NULL
+            ORDER BY a.user_id, a.question_id, a.created_at
+        ),
+        Scores AS (
+            SELECT user_id, AVG(is_correct::int) AS score
+            FROM FirstAttempts
+            GROUP BY user_id
+            HAVING COUNT(*) >= 5
+        ),
+        Groups AS (
+            SELECT user_id,
+                   CASE WHEN pr >= 0.73 THEN 'upper' WHEN pr <= 0.27 THEN 'lower' END AS grp
+            FROM (SELECT user_id, PERCENT_RANK() OVER (ORDER BY score) AS pr FROM Scores) ranked
+        )
+        SELECT f.question_id, COALESCE(q.difficulty_level, ''),
+               COUNT(*), AVG(f.is_correct::int)::float8,
+               (AVG(f.is_correct::int) FILTER (WHERE g.grp = 'upper')
+                - AVG(f.is_correct::int) FILTER (WHERE g.grp = 'lower'))::float8
+        FROM FirstAttempts f
+        JOIN questions q ON q.id = f.question_id
+        LEFT JOIN Groups g ON g.user_id = f.user_id
+        GROUP BY f.question_id, q.difficulty_level
+        HAVING COUNT(*) >= $1`, minCalibrationAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("cevap istatistikleri alınamadı: %v", err)
+	}
+
+	var stats []models.QuestionDifficultyStats
+	for rows.Next() {
+		var s models.QuestionDifficultyStats
+		if err := rows.Scan(&s.QuestionID, &s.LabelledLevel, &s.Attempts, &s.PValue, &s.Discrimination); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("cevap istatistiği okunamadı: %v", err)
+		}
+		s.PValue = math.Round(s.PValue*1000) / 1000
+		if s.Discrimination != nil {
+			d := math.Round(*s.Discrimination*1000) / 1000
+			s.Discrimination = &d
+		}
+		s.ObservedLevel = observedDifficultyLevel(s.PValue)
+		s.Flags = difficultyFlags(s.LabelledLevel, s.ObservedLevel, s.Discrimination)
+		stats = append(stats, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cevap istatistikleri okunamadı: %v", err)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("transaction başlatma hatası: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Yeterli veri kalmayan (ör. cevapları silinen) soruların eski sonuçları kaldırılır
+	if _, err := tx.Exec(ctx, "DELETE FROM question_difficulty_stats"); err != nil {
+		return nil, fmt.Errorf("eski istatistikler silinemedi: %v", err)
+	}
+
+	report := &models.DifficultyCalibrationReport{Questions: len(stats)}
+	for _, s := range stats {
+		if _, err := tx.Exec(ctx, `
+            INSERT INTO question_difficulty_stats
+                (question_id, attempts, p_value, discrimination, observed_level, labelled_level, flags)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			s.QuestionID, s.Attempts, s.PValue, s.Discrimination, s.ObservedLevel, s.LabelledLevel, s.Flags); err != nil {
+			return nil, fmt.Errorf("soru %d istatistiği kaydedilemedi: %v", s.QuestionID, err)
+		}
+		if len(s.Flags) > 0 {
+			report.Flagged++
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("transaction commit hatası: %v", err)
+	}
+	return report, nil
+}
+
+// Gözlenen zorluğu arka planda periyodik olarak yeniden hesaplar
+func StartDifficultyCalibration(interval time.Duration) {
+	go func() {
+		for {
+			time.Sleep(interval)
+
+			report, err := CalibrateQuestionDifficulty(context.Background())
+			if err != nil {
+				log.Printf("Zorluk kalibrasyonu hatası: %v", err)
+				continue
+			}
+			if report.Flagged > 0 {
+				log.Printf("Zorluk kalibrasyonu: %d sorudan %d tanesi işaretlendi", report.Questions, report.Flagged)
+			}
+		}
+	}()
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"osymapp/models"
+)
+
+// Desteklenen soru dışa aktarma formatları
+const (
+	QuestionExportJSONL = "jsonl"
+	QuestionExportCSV   = "csv"
+	QuestionExportQTI   = "qti"
+)
+
+// CSV başlığı toplu içe aktarma manifestiyle uyumludur; dışa aktarılan dosya tekrar içe aktarılabilir
+var questionExportCSVHeader = []string{
+	"id", "question_image", "solution_image", "answer", "publisher", "publisher_id",
+	"difficulty", "status", "categories", "body", "created_at", "updated_at",
+}
+
+// Dışa aktarılan soruları sırayla yazan akış
+type QuestionExporter interface {
+	Write(rec models.QuestionExportRecord) error
+	Close() error
+}
+
+func QuestionExportFormat(param string) (string, error) {
+	switch format := strings.ToLower(param); format {
+	case "", QuestionExportJSONL, "json":
+		return QuestionExportJSONL, nil
+	case QuestionExportCSV, QuestionExportQTI:
+		return format, nil
+	}
+	return "", fmt.Errorf("desteklenmeyen format: %s", param)
+}
+
+func QuestionExportContentType(format string) string {
+	switch format {
+	case QuestionExportCSV:
+		return "text/csv; charset=utf-8"
+	case QuestionExportQTI:
+		return "application/zip"
+	}
+	return "application/x-ndjson; charset=utf-8"
+}
+
+func QuestionExportFileName(format string) string {
+	switch format {
+	case QuestionExportCSV:
+		return "questions.csv"
+	case QuestionExportQTI:
+		return "questions-qti21.zip"
+	}
+	return "questions.jsonl"
+}
+
+func NewQuestionExporter(w io.Writer, format string) (QuestionExporter, error) {
+	switch format {
+	case QuestionExportJSONL:
+		return &jsonlQuestionExporter{enc: json.NewEncoder(w)}, nil
+	case QuestionExportCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(questionExportCSVHeader); err != nil {
+			return nil, err
+		}
+		return &csvQuestionExporter{w: cw}, nil
+	case QuestionExportQTI:
+		return &qtiQuestionExporter{zw: zip.NewWriter(w), files: map[string]bool{}}, nil
+	}
+	return nil, fmt.Errorf("desteklenmeyen format: %s", format)
+}
+
+type jsonlQuestionExporter struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlQuestionExporter) Write(rec models.QuestionExportRecord) error {
+	return e.enc.Encode(rec)
+}
+
+func (e *jsonlQuestionExporter) Close() error { return nil }
+
+type csvQuestionExporter struct {
+	w *csv.Writer
+}
+
+// Soru metni CSV hücresine JSON olarak yazılır; içe aktarma aynı biçimi okur
+func (e *csvQuestionExporter) Write(rec models.QuestionExportRecord) error {
+	body := ""
+	if rec.Body != nil {
+		data, err := json.Marshal(rec.Body)
+		if err != nil {
+			return err
+		}
+		body = string(data)
+	}
+
+	return e.w.Write([]string{
+		strconv.Itoa(rec.ID), rec.PathURL, rec.SolutionURL, rec.Answer,
+		rec.PublisherName, strconv.Itoa(rec.PublisherID), rec.DifficultyLevel, rec.Status,
+		strings.Join(rec.Categories, ";"), body,
+		rec.CreatedAt.Format("2006-01-02T15:04:05Z07:00"), rec.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+func (e *csvQuestionExporter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// IMS QTI 2.1 paketi: her soru items/ altında ayrı bir assessmentItem, resimler images/ altında,
+// kök dizinde de tüm kaynakları listeleyen imsmanifest.xml
+type qtiQuestionExporter struct {
+	zw        *zip.Writer
+	files     map[string]bool
+	resources []qtiResource
+}
+
+type qtiResource struct {
+	identifier string
+	href       string
+	files      []string
+}
+
+func (e *qtiQuestionExporter) Write(rec models.QuestionExportRecord) error {
+	identifier := fmt.Sprintf("q%d", rec.ID)
+	res := qtiResource{identifier: identifier, href: "items/" + identifier + ".xml"}
+	res.files = append(res.files, res.href)
+
+	questionImage, err := e.addImage(rec.PathURL)
+	if err != nil {
+		return err
+	}
+	solutionImage, err := e.addImage(rec.SolutionURL)
+	if err != nil {
+		return err
+	}
+	for _, f := range []string{questionImage, solutionImage} {
+		if f != "" {
+			res.files = append(res.files, f)
+		}
+	}
+
+	body, bodyImages, err := e.addBodyImages(rec.Body)
+	if err != nil {
+		return err
+	}
+	res.files = append(res.files, bodyImages...)
+	rec.Body = body
+
+	w, err := e.zw.Create(res.href)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, qtiAssessmentItem(identifier, rec, questionImage, solutionImage)); err != nil {
+		return err
+	}
+
+	e.resources = append(e.resources, res)
+	return nil
+}
+
+// Resmi diskten pakete kopyalar ve paket içindeki yolunu döner. Diskte bulunmayan
+// resimler atlanır; akış yarıda kesilmesin diye yalnızca loglanır.
+func (e *qtiQuestionExporter) addImage(url string) (string, error) {
+	if url == "" {
+		return "", nil
+	}
+	name := "images/" + strings.TrimPrefix(path.Clean(url), "/images/")
+	if e.files[name] {
+		return name, nil
+	}
+
+	src, err := os.Open(strings.TrimPrefix(url, "/"))
+	if err != nil {
+		log.Printf("QTI dışa aktarma: %s okunamadı: %v", url, err)
+		return "", nil
+	}
+	defer src.Close()
+
+	dst, err := e.zw.Create(name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		return "", err
+	}
+
+	e.files[name] = true
+	return name, nil
+}
+
+// Metne gömülü sunucu resimlerini pakete ekler ve bağlantılarını paket içindeki yola çevirir
+func (e *qtiQuestionExporter) addBodyImages(b *models.QuestionBody) (*models.QuestionBody, []string, error) {
+	if b == nil {
+		return nil, nil, nil
+	}
+
+	var files []string
+	var firstErr error
+	rewrite := func(text string) string {
+		return rewriteMarkdownLinks(text, func(isImage bool, url string) string {
+			if !isImage || !strings.HasPrefix(url, "/images/") || firstErr != nil {
+				return url
+			}
+			name, err := e.addImage(url)
+			if err != nil {
+				firstErr = err
+			}
+			if name == "" {
+				return url
+			}
+			files = append(files, name)
+			return "../" + name
+		})
+	}
+
+	out := &models.QuestionBody{Stem: rewrite(b.Stem), Solution: rewrite(b.Solution)}
+	for _, o := range b.Options {
+		out.Options = append(out.Options, models.QuestionOption{Label: o.Label, Text: rewrite(o.Text)})
+	}
+	return out, files, firstErr
+}
+
+func (e *qtiQuestionExporter) Close() error {
+	w, err := e.zw.Create("imsmanifest.xml")
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<manifest xmlns="http://www.imsglobal.org/xsd/imscp_v1p1" identifier="osymapp-questions">` + "\n")
+	b.WriteString("  <metadata><schema>IMS Content</schema><schemaversion>1.1.4</schemaversion></metadata>\n")
+	b.WriteString("  <organizations/>\n  <resources>\n")
+	for _, res := range e.resources {
+		fmt.Fprintf(&b, `    <resource identifier="%s" type="imsqti_item_xmlv2p1" href="%s">`+"\n",
+			xmlEscape(res.identifier), xmlEscape(res.href))
+		for _, f := range res.files {
+			fmt.Fprintf(&b, `      <file href="%s"/>`+"\n", xmlEscape(f))
+		}
+		b.WriteString("    </resource>\n")
+	}
+	b.WriteString("  </resources>\n</manifest>\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+	return e.zw.Close()
+}
+
+// Cevap A-E arasında tek harfse çoktan seçmeli, değilse açık uçlu soru olarak yazılır.
+// Soru metni varsa kök, seçenek ve çözüm metinleri Markdown kaynağıyla yazılır; yoksa
+// şıklar soru resminin içinde olduğundan seçenekler yalnızca harflerden oluşur.
+func qtiAssessmentItem(identifier string, rec models.QuestionExportRecord, questionImage, solutionImage string) string {
+	answer := strings.ToUpper(strings.TrimSpace(rec.Answer))
+	isChoice := len(answer) == 1 && answer >= "A" && answer <= "E"
+
+	var stem, solution string
+	optionTexts := map[string]string{}
+	if rec.Body != nil {
+		stem, solution = rec.Body.Stem, rec.Body.Solution
+		for _, o := range rec.Body.Options {
+			optionTexts[strings.ToUpper(o.Label)] = o.Text
+		}
+	}
+	hasFeedback := solutionImage != "" || solution != ""
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	fmt.Fprintf(&b, `<assessmentItem xmlns="http://www.imsglobal.org/xsd/imsqti_v2p1" identifier="%s" title="%s" adaptive="false" timeDependent="false">`+"\n",
+		identifier, xmlEscape(fmt.Sprintf("Soru %d", rec.ID)))
+
+	if isChoice {
+		b.WriteString(`  <responseDeclaration identifier="RESPONSE" cardinality="single" baseType="identifier">` + "\n")
+	} else {
+		b.WriteString(`  <responseDeclaration identifier="RESPONSE" cardinality="single" baseType="string">` + "\n")
+		answer = strings.TrimSpace(rec.Answer)
+	}
+	fmt.Fprintf(&b, "    <correctResponse><value>%s</value></correctResponse>\n  </responseDeclaration>\n", xmlEscape(answer))
+	b.WriteString(`  <outcomeDeclaration identifier="SCORE" cardinality="single" baseType="float"><defaultValue><value>0</value></defaultValue></outcomeDeclaration>` + "\n")
+	if hasFeedback {
+		b.WriteString(`  <outcomeDeclaration identifier="FEEDBACK" cardinality="single" baseType="identifier"/>` + "\n")
+	}
+
+	b.WriteString("  <itemBody>\n")
+	if stem != "" {
+		fmt.Fprintf(&b, "    <p>%s</p>\n", xmlEscape(stem))
+	}
+	if questionImage != "" {
+		fmt.Fprintf(&b, `    <p><img src="../%s" alt="%s"/></p>`+"\n", xmlEscape(questionImage), xmlEscape(fmt.Sprintf("Soru %d", rec.ID)))
+	}
+	if isChoice {
+		b.WriteString(`    <choiceInteraction responseIdentifier="RESPONSE" shuffle="false" maxChoices="1">` + "\n")
+		for _, choice := range []string{"A", "B", "C", "D", "E"} {
+			text := choice
+			if optionText, ok := optionTexts[choice]; ok {
+				text = optionText
+			}
+			fmt.Fprintf(&b, `      <simpleChoice identifier="%s">%s</simpleChoice>`+"\n", choice, xmlEscape(text))
+		}
+		b.WriteString("    </choiceInteraction>\n")
+	} else {
+		b.WriteString(`    <p><textEntryInteraction responseIdentifier="RESPONSE"/></p>` + "\n")
+	}
+	b.WriteString("  </itemBody>\n")
+
+	b.WriteString("  <responseProcessing>\n")
+	b.WriteString("    <responseCondition>\n")
+	b.WriteString(`      <responseIf><match><variable identifier="RESPONSE"/><correct identifier="RESPONSE"/></match>` +
+		`<setOutcomeValue identifier="SCORE"><baseValue baseType="float">1</baseValue></setOutcomeValue></responseIf>` + "\n")
+	b.WriteString(`      <responseElse><setOutcomeValue identifier="SCORE"><baseValue baseType="float">0</baseValue></setOutcomeValue></responseElse>` + "\n")
+	b.WriteString("    </responseCondition>\n")
+	if hasFeedback {
+		b.WriteString(`    <setOutcomeValue identifier="FEEDBACK"><baseValue baseType="identifier">SOLUTION</baseValue></setOutcomeValue>` + "\n")
+	}
+	b.WriteString("  </responseProcessing>\n")
+
+	if hasFeedback {
+		b.WriteString(`  <modalFeedback outcomeIdentifier="FEEDBACK" identifier="SOLUTION" showHide="show" title="Çözüm">`)
+		if solution != "" {
+			fmt.Fprintf(&b, "<p>%s</p>", xmlEscape(solution))
+		}
+		if solutionImage != "" {
+			fmt.Fprintf(&b, `<p><img src="../%s" alt="Çözüm"/></p>`, xmlEscape(solutionImage))
+		}
+		b.WriteString("</modalFeedback>\n")
+	}
+	b.WriteString("</assessmentItem>\n")
+	return b.String()
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+
+	"osymapp/models"
+)
+
+// Soru listesi, dışa aktarma ve uyarlanabilir test tarafından ortak kullanılan sorgu
+// filtreleri. Koşullar $1'den başlayarak numaralandırılır.
+func QuestionFilterConditions(query url.Values, editor bool) ([]string, []interface{}) {
+	// Query parametrelerini al
+	subCategoryID := query.Get("sub_category_id")
+	categoryID := query.Get("category_id")
+	difficulty := query.Get("difficulty")
+	search := query.Get("search")
+	nodeID := query.Get("node_id")
+	status := query.Get("status")
+	collectionID := query.Get("collection_id")
+
+	var conditions []string
+	var args []interface{}
+	argCount := 1
+
+	// Öğrenciler yalnızca yayındaki soruları görür; editörler durum filtresi kullanabilir
+	if !editor {
+		status = models.QuestionStatusPublished
+	}
+	if status != "" {
+		conditions = append(conditions, fmt.Sprintf("q.status = $%d", argCount))
+		args = append(args, status)
+		argCount++
+	}
+
+	// Arama filtresi
+	if search != "" {
+		conditions = append(conditions, fmt.Sprintf("(q.path_url ILIKE $%d OR q.solution_url ILIKE $%d OR q.body::text ILIKE $%d)", argCount, argCount, argCount))
+		args = append(args, "%"+search+"%")
+		argCount++
+	}
+
+	// Alt kategori filtresi
+	if subCategoryID != "" {
+		conditions = append(conditions, fmt.Sprintf(`
+			EXISTS (
+				SELECT 1 FROM question_categories qc 
+				WHERE qc.question_id = q.id AND qc.category_id IN (
+					SELECT id FROM categories WHERE sub_category_id = $%d AND deleted_at IS NULL
+				)
+			)`, argCount))
+		args = append(args, subCategoryID)
+		argCount++
+	}
+
+	// Kategori filtresi
+	if categoryID != "" {
+		conditions = append(conditions, fmt.Sprintf(`
+			EXISTS (
+				SELECT 1 FROM question_categories qc 
+				WHERE qc.question_id = q.id AND qc.category_id = $%d
+			)`, argCount))
+		args = append(args, categoryID)
+		argCount++
+	}
+
+	// Kategori ağacı filtresi (düğüm ve tüm alt düğümleri)
+	if nodeID != "" {
+		conditions = append(conditions, fmt.Sprintf(`
+			EXISTS (
+				WITH RECURSIVE Subtree AS (
+					SELECT id FROM category_tree WHERE id = $%d AND deleted_at IS NULL
+					UNION ALL
+					SELECT c.id FROM category_tree c JOIN Subtree s ON c.parent_id = s.id
+					WHERE c.deleted_at IS NULL
+				)
+				SELECT 1 FROM question_category_nodes qcn 
+				WHERE qcn.question_id = q.id AND qcn.node_id IN (SELECT id FROM Subtree)
+			)`, argCount))
+		args = append(args, nodeID)
+		argCount++
+	}
+
+	// Koleksiyon filtresi (sahiplik kontrolü çağıran tarafta yapılır)
+	if collectionID != "" {
+		conditions = append(conditions, fmt.Sprintf(`
+			EXISTS (
+				SELECT 1 FROM question_collection_items ci
+				WHERE ci.question_id = q.id AND ci.collection_id = $%d
+			)`, argCount))
+		args = append(args, collectionID)
+		argCount++
+	}
+
+	// Zorluk seviyesi filtresi (eski "kolay", "zor" gibi değerler ölçeğe çevrilir)
+	if level, err := NormalizeDifficulty(difficulty); err == nil {
+		difficulty = level
+	}
+	if difficulty != "" {
+		conditions = append(conditions, fmt.Sprintf("q.difficulty_level = $%d", argCount))
+		args = append(args, difficulty)
+		argCount++
+	}
+
+	return conditions, args
+}
@@ -0,0 +1,639 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"osymapp/db"
+	"osymapp/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ZIP içindeki tek bir resmin açılmış boyut sınırı
+const maxImportImageSize = 20 << 20
+
+var importImageExtensions = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true}
+
+// Doğrulanmış ve eklenmeye hazır manifest satırı
+type importQuestion struct {
+	row          int
+	questionFile *zip.File
+	solutionFile *zip.File
+	answer       string
+	difficulty   string
+	publisherID  int
+	categoryIDs  []int
+	body         *models.QuestionBody
+}
+
+// İçe aktarma işini kuyruğa ekler
+func CreateQuestionImportJob(ctx context.Context, fileName, mode string, actorID int) (int64, error) {
+	var id int64
+	err := db.GetPool().QueryRow(ctx,
+		`INSERT INTO question_import_jobs (mode, file_name, created_by)
+         VALUES ($1, $2, NULLIF($3, 0))
+         RETURNING id`,
+		mode, fileName, actorID).Scan(&id)
+	return id, err
+}
+
+// İçe aktarma işini arka planda çalıştırır; iş bitince ZIP dosyası silinir
+func StartQuestionImport(jobID int64, zipPath, mode string, actorID int) {
+	go func() {
+		defer os.Remove(zipPath)
+
+		if err := runQuestionImport(context.Background(), jobID, zipPath, mode, actorID); err != nil {
+			log.Printf("Soru içe aktarma işi %d başarısız: %v", jobID, err)
+			failQuestionImport(context.Background(), jobID, err.Error())
+		}
+	}()
+}
+
+// Sunucu kapanırken yarıda kalan işleri başarısız olarak işaretler
+func FailInterruptedQuestionImports(ctx context.Context) error {
+	_, err := db.GetPool().Exec(ctx,
+		`UPDATE question_import_jobs
+         SET status = 'failed', error = 'sunucu yeniden başlatıldığı için iş yarıda kaldı', finished_at = CURRENT_TIMESTAMP
+         WHERE status IN ('queued', 'running')`)
+	return err
+}
+
+func GetQuestionImportJob(ctx context.Context, id int64) (*models.QuestionImportJob, error) {
+	return scanQuestionImportJob(db.GetPool().QueryRow(ctx,
+		questionImportJobColumns+" WHERE id = $1", id))
+}
+
+func ListQuestionImportJobs(ctx context.Context, limit int) ([]models.QuestionImportJob, error) {
+	rows, err := db.GetPool().Query(ctx,
+		questionImportJobColumns+" ORDER BY id DESC LIMIT $1", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []models.QuestionImportJob{}
+	for rows.Next() {
+		job, err := scanQuestionImportJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, rows.Err()
+}
+
+const questionImportJobColumns = `
+    SELECT id, status, mode, file_name, total_rows, processed_rows, imported_rows, failed_rows,
+           row_errors, question_ids, error, created_by, created_at, started_at, finished_at
+    FROM question_import_jobs`
+
+func scanQuestionImportJob(row pgx.Row) (*models.QuestionImportJob, error) {
+	var job models.QuestionImportJob
+	var rowErrors []byte
+	if err := row.Scan(&job.ID, &job.Status, &job.Mode, &job.FileName, &job.TotalRows,
+		&job.ProcessedRows, &job.ImportedRows, &job.FailedRows, &rowErrors, &job.QuestionIDs,
+		&job.Error, &job.CreatedBy, &job.CreatedAt, &job.StartedAt, &job.FinishedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(rowErrors, &job.RowErrors); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func runQuestionImport(ctx context.Context, jobID int64, zipPath, mode string, actorID int) error {
+	pool := db.GetPool()
+	if _, err := pool.Exec(ctx,
+		"UPDATE question_import_jobs SET status = 'running', started_at = CURRENT_TIMESTAMP WHERE id = $1",
+		jobID); err != nil {
+		return err
+	}
+
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return fmt.Errorf("ZIP dosyası açılamadı: %v", err)
+	}
+	defer zr.Close()
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		if !f.FileInfo().IsDir() {
+			files[path.Clean(f.Name)] = f
+		}
+	}
+
+	manifest, baseDir, err := readImportManifest(zr.File)
+	if err != nil {
+		return err
+	}
+	if _, err := pool.Exec(ctx,
+		"UPDATE question_import_jobs SET total_rows = $1 WHERE id = $2",
+		len(manifest), jobID); err != nil {
+		return err
+	}
+
+	resolver := &importResolver{publishers: map[string]int{}, categories: map[string]int{}}
+	result := &questionImportResult{rowErrors: []models.QuestionImportError{}, questionIDs: []int{}}
+
+	if mode == models.QuestionImportAtomic {
+		return runAtomicQuestionImport(ctx, jobID, manifest, files, baseDir, resolver, actorID, result)
+	}
+
+	// Kısmi mod: her satır kendi transaction'ında eklenir, hatalılar raporlanır
+	for i, row := range manifest {
+		rowNumber := i + 1
+		q, rowErrors := resolver.validate(ctx, rowNumber, row, files, baseDir)
+		if len(rowErrors) > 0 {
+			result.fail(rowErrors...)
+		} else if id, err := importQuestionInTx(ctx, q, actorID); err != nil {
+			result.fail(models.QuestionImportError{Row: rowNumber, Message: err.Error()})
+		} else {
+			result.imported(id)
+		}
+
+		if err := result.saveProgress(ctx, jobID); err != nil {
+			return err
+		}
+	}
+
+	return result.finish(ctx, jobID, "completed", "")
+}
+
+// Atomik mod: önce tüm satırlar doğrulanır, hata yoksa hepsi tek transaction'da eklenir
+func runAtomicQuestionImport(ctx context.Context, jobID int64, manifest []models.QuestionImportManifestRow,
+	files map[string]*zip.File, baseDir string, resolver *importResolver, actorID int, result *questionImportResult) error {
+
+	valid := make([]*importQuestion, 0, len(manifest))
+	for i, row := range manifest {
+		q, rowErrors := resolver.validate(ctx, i+1, row, files, baseDir)
+		if len(rowErrors) > 0 {
+			result.fail(rowErrors...)
+			continue
+		}
+		valid = append(valid, q)
+	}
+
+	if result.failed > 0 {
+		result.processed = len(manifest)
+		return result.finish(ctx, jobID, "failed",
+			fmt.Sprintf("%d satırda hata bulundu; hiçbir soru eklenmedi", result.failed))
+	}
+
+	tx, err := db.GetPool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var written []string
+	cleanup := func() {
+		for _, url := range written {
+			DeleteImage(url)
+		}
+	}
+
+	for _, q := range valid {
+		id, err := insertImportedQuestion(ctx, tx, q, actorID, &written)
+		if err != nil {
+			cleanup()
+			result.questionIDs = []int{}
+			result.rowErrors = append(result.rowErrors, models.QuestionImportError{Row: q.row, Message: err.Error()})
+			result.failed = 1
+			result.processed = len(manifest)
+			return result.finish(ctx, jobID, "failed",
+				fmt.Sprintf("satır %d eklenemedi; hiçbir soru eklenmedi", q.row))
+		}
+		result.imported(id)
+
+		// İlerleme ayrı bağlantı üzerinden yazılır; eklenen sorular commit'e kadar görünmez
+		if err := result.saveProgress(ctx, jobID); err != nil {
+			cleanup()
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		cleanup()
+		return fmt.Errorf("transaction commit hatası: %v", err)
+	}
+
+	return result.finish(ctx, jobID, "completed", "")
+}
+
+type questionImportResult struct {
+	processed   int
+	importedN   int
+	failed      int
+	rowErrors   []models.QuestionImportError
+	questionIDs []int
+}
+
+func (r *questionImportResult) imported(id int) {
+	r.processed++
+	r.importedN++
+	r.questionIDs = append(r.questionIDs, id)
+}
+
+func (r *questionImportResult) fail(errs ...models.QuestionImportError) {
+	r.processed++
+	r.failed++
+	r.rowErrors = append(r.rowErrors, errs...)
+}
+
+func (r *questionImportResult) saveProgress(ctx context.Context, jobID int64) error {
+	_, err := db.GetPool().Exec(ctx,
+		`UPDATE question_import_jobs
+         SET processed_rows = $1, imported_rows = $2, failed_rows = $3
+         WHERE id = $4`,
+		r.processed, r.importedN, r.failed, jobID)
+	return err
+}
+
+func (r *questionImportResult) finish(ctx context.Context, jobID int64, status, message string) error {
+	if status == "failed" {
+		r.importedN = 0
+	}
+	rowErrors, err := json.Marshal(r.rowErrors)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.GetPool().Exec(ctx,
+		`UPDATE question_import_jobs
+         SET status = $1, error = $2, processed_rows = $3, imported_rows = $4, failed_rows = $5,
+             row_errors = $6, question_ids = $7, finished_at = CURRENT_TIMESTAMP
+         WHERE id = $8`,
+		status, message, r.processed, r.importedN, r.failed, rowErrors, r.questionIDs, jobID)
+	return err
+}
+
+func failQuestionImport(ctx context.Context, jobID int64, message string) {
+	_, err := db.GetPool().Exec(ctx,
+		`UPDATE question_import_jobs
+         SET status = 'failed', error = $1, finished_at = CURRENT_TIMESTAMP
+         WHERE id = $2`,
+		message, jobID)
+	if err != nil {
+		log.Printf("İçe aktarma işi %d güncellenemedi: %v", jobID, err)
+	}
+}
+
+func importQuestionInTx(ctx context.Context, q *importQuestion, actorID int) (int, error) {
+	tx, err := db.GetPool().Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var written []string
+	id, err := insertImportedQuestion(ctx, tx, q, actorID, &written)
+	if err == nil {
+		err = tx.Commit(ctx)
+	}
+	if err != nil {
+		for _, url := range written {
+			DeleteImage(url)
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// Resimleri diske çıkarır ve soruyu taslak olarak ekler. Yazılan dosyalar written'a
+// eklenir; çağıran hata durumunda bunları silmekten sorumludur.
+func insertImportedQuestion(ctx context.Context, tx pgx.Tx, q *importQuestion, actorID int, written *[]string) (int, error) {
+	pathURL, err := extractImportImage(q.questionFile, QuestionImagesPath)
+	if err != nil {
+		return 0, err
+	}
+	*written = append(*written, pathURL)
+
+	solutionURL := ""
+	if q.solutionFile != nil {
+		if solutionURL, err = extractImportImage(q.solutionFile, SolutionImagesPath); err != nil {
+			return 0, err
+		}
+		*written = append(*written, solutionURL)
+	}
+
+	var id int
+	err = tx.QueryRow(ctx,
+		`INSERT INTO questions (
+			path_url, answer, popularity, created_user_id,
+			updated_user_id, solution_url, publisher_id,
+			difficulty_level, body, created_at, updated_at
+		) VALUES ($1, $2, 0, $3, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id`,
+		pathURL, q.answer, actorID, solutionURL, q.publisherID, q.difficulty, q.body).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("soru eklenemedi: %v", err)
+	}
+
+	// Kategori ilişkileri ağaç düğümleri üzerinden eklenir
+	if err := SetQuestionCategories(ctx, tx, id, q.categoryIDs, nil); err != nil {
+		return 0, fmt.Errorf("kategori ilişkisi eklenemedi: %v", err)
+	}
+	if err := SetQuestionImages(ctx, tx, id); err != nil {
+		return 0, err
+	}
+
+	if _, err := RecordQuestionRevision(ctx, tx, id, actorID, models.RevisionReasonCreate, nil); err != nil {
+		return 0, fmt.Errorf("revizyon kaydedilemedi: %v", err)
+	}
+	return id, nil
+}
+
+func extractImportImage(f *zip.File, directory string) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", fmt.Errorf("%s açılamadı: %v", f.Name, err)
+	}
+	defer rc.Close()
+
+	// Zip başlığındaki boyut yanlış olabilir; sınırdan bir bayt fazlası okunur ve sınırı aşan
+	// resim kesilerek kaydedilmek yerine satır hatalı sayılır
+	data, err := io.ReadAll(io.LimitReader(rc, maxImportImageSize+1))
+	if err != nil {
+		return "", fmt.Errorf("%s okunamadı: %v", f.Name, err)
+	}
+	if len(data) > maxImportImageSize {
+		return "", fmt.Errorf("%s çok büyük (en fazla %d MB)", f.Name, maxImportImageSize>>20)
+	}
+
+	return SaveImage(bytes.NewReader(data), strings.ToLower(path.Ext(f.Name)), directory)
+}
+
+// ZIP içindeki en üst seviyedeki manifest.csv veya manifest.json dosyasını okur.
+// Resim yolları manifestin bulunduğu dizine göre çözülür.
+func readImportManifest(entries []*zip.File) ([]models.QuestionImportManifestRow, string, error) {
+	var manifest *zip.File
+	for _, f := range entries {
+		base := strings.ToLower(path.Base(f.Name))
+		if base != "manifest.csv" && base != "manifest.json" {
+			continue
+		}
+		if manifest == nil || strings.Count(path.Clean(f.Name), "/") < strings.Count(path.Clean(manifest.Name), "/") {
+			manifest = f
+		}
+	}
+	if manifest == nil {
+		return nil, "", errors.New("ZIP içinde manifest.csv veya manifest.json bulunamadı")
+	}
+
+	rc, err := manifest.Open()
+	if err != nil {
+		return nil, "", fmt.Errorf("manifest açılamadı: %v", err)
+	}
+	defer rc.Close()
+
+	var rows []models.QuestionImportManifestRow
+	if strings.HasSuffix(strings.ToLower(manifest.Name), ".json") {
+		if err := json.NewDecoder(rc).Decode(&rows); err != nil {
+			return nil, "", fmt.Errorf("manifest JSON parse hatası: %v", err)
+		}
+	} else if rows, err = decodeImportManifestCSV(rc); err != nil {
+		return nil, "", err
+	}
+
+	if len(rows) == 0 {
+		return nil, "", errors.New("manifest boş")
+	}
+	return rows, path.Dir(path.Clean(manifest.Name)), nil
+}
+
+// CSV başlığı zorunludur: question_image, solution_image, answer, publisher, difficulty, categories, body.
+// Birden fazla kategori ";" ile ayrılır; soru metni (body) dışa aktarmadaki gibi JSON olarak yazılır.
+func decodeImportManifestCSV(r io.Reader) ([]models.QuestionImportManifestRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("manifest CSV parse hatası: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"question_image", "answer"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("manifest CSV başlığında %s sütunu eksik", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	rows := make([]models.QuestionImportManifestRow, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := models.QuestionImportManifestRow{
+			QuestionImage: field(record, "question_image"),
+			SolutionImage: field(record, "solution_image"),
+			Answer:        field(record, "answer"),
+			Publisher:     field(record, "publisher"),
+			Difficulty:    field(record, "difficulty"),
+		}
+		for _, category := range strings.Split(field(record, "categories"), ";") {
+			if category = strings.TrimSpace(category); category != "" {
+				row.Categories = append(row.Categories, category)
+			}
+		}
+		if body := field(record, "body"); body != "" {
+			if err := json.Unmarshal([]byte(body), &row.Body); err != nil {
+				return nil, fmt.Errorf("manifest CSV satır %d: soru metni JSON parse hatası: %v", len(rows)+1, err)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Yayıncı ve kategori çözümlemelerini iş boyunca önbellekte tutar
+type importResolver struct {
+	publishers map[string]int
+	categories map[string]int
+}
+
+func (res *importResolver) validate(ctx context.Context, rowNumber int, row models.QuestionImportManifestRow,
+	files map[string]*zip.File, baseDir string) (*importQuestion, []models.QuestionImportError) {
+
+	var errs []models.QuestionImportError
+	addErr := func(field, format string, args ...interface{}) {
+		errs = append(errs, models.QuestionImportError{Row: rowNumber, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	q := &importQuestion{
+		row:    rowNumber,
+		answer: strings.TrimSpace(row.Answer),
+	}
+	if q.answer == "" {
+		addErr("answer", "cevap boş olamaz")
+	}
+
+	if difficulty, err := NormalizeDifficulty(row.Difficulty); err != nil {
+		addErr("difficulty", "%v", err)
+	} else {
+		q.difficulty = difficulty
+	}
+
+	if strings.TrimSpace(row.QuestionImage) == "" {
+		addErr("question_image", "soru resmi belirtilmeli")
+	} else if f, err := importImageFile(files, baseDir, row.QuestionImage); err != nil {
+		addErr("question_image", "%v", err)
+	} else {
+		q.questionFile = f
+	}
+
+	if strings.TrimSpace(row.SolutionImage) != "" {
+		if f, err := importImageFile(files, baseDir, row.SolutionImage); err != nil {
+			addErr("solution_image", "%v", err)
+		} else {
+			q.solutionFile = f
+		}
+	}
+
+	q.body = NormalizeQuestionBody(row.Body)
+	for _, e := range ValidateQuestionBody(q.body) {
+		addErr("body."+e.Field, "%s", e.Message)
+	}
+
+	if publisherID, err := res.publisher(ctx, strings.TrimSpace(row.Publisher)); err != nil {
+		addErr("publisher", "%v", err)
+	} else {
+		q.publisherID = publisherID
+	}
+
+	if len(row.Categories) == 0 {
+		addErr("categories", "en az bir kategori belirtilmeli")
+	}
+	for _, categoryPath := range row.Categories {
+		categoryID, err := res.category(ctx, strings.TrimSpace(categoryPath))
+		if err != nil {
+			addErr("categories", "%s: %v", categoryPath, err)
+			continue
+		}
+		q.categoryIDs = append(q.categoryIDs, categoryID)
+	}
+
+	return q, errs
+}
+
+func importImageFile(files map[string]*zip.File, baseDir, name string) (*zip.File, error) {
+	f, ok := files[path.Join(baseDir, strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("%s ZIP içinde bulunamadı", name)
+	}
+	if !importImageExtensions[strings.ToLower(path.Ext(f.Name))] {
+		return nil, fmt.Errorf("%s desteklenmeyen resim formatı", name)
+	}
+	if f.UncompressedSize64 > maxImportImageSize {
+		return nil, fmt.Errorf("%s çok büyük (en fazla %d MB)", name, maxImportImageSize>>20)
+	}
+	return f, nil
+}
+
+func (res *importResolver) publisher(ctx context.Context, value string) (int, error) {
+	if value == "" {
+		return 0, errors.New("yayıncı belirtilmeli")
+	}
+	if id, ok := res.publishers[value]; ok {
+		return id, nil
+	}
+
+	var id int
+	var err error
+	if n, convErr := strconv.Atoi(value); convErr == nil {
+		err = db.GetPool().QueryRow(ctx, "SELECT id FROM publishers WHERE id = $1", n).Scan(&id)
+	} else {
+		err = db.GetPool().QueryRow(ctx,
+			"SELECT id FROM publishers WHERE lower(name) = lower($1) ORDER BY id LIMIT 1",
+			value).Scan(&id)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("yayıncı bulunamadı: %s", value)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	res.publishers[value] = id
+	return id, nil
+}
+
+// Kategori ID'si, slug yolu (tyt/matematik/problemler) veya ad yolu (TYT > Matematik > Problemler) kabul edilir
+func (res *importResolver) category(ctx context.Context, value string) (int, error) {
+	if id, ok := res.categories[value]; ok {
+		return id, nil
+	}
+
+	var id int
+	var err error
+	switch {
+	case strings.Contains(value, ">"):
+		parts := strings.Split(value, ">")
+		if len(parts) != 3 {
+			return 0, errors.New("ad yolu ana > alt > kategori biçiminde olmalı")
+		}
+		err = db.GetPool().QueryRow(ctx, `
+            SELECT c.id
+            FROM categories c
+            JOIN sub_categories sc ON sc.id = c.sub_category_id AND sc.deleted_at IS NULL
+            JOIN main_category_sub_category mcsc ON mcsc.sub_category_id = sc.id
+            JOIN main_categories mc ON mc.id = mcsc.main_category_id AND mc.deleted_at IS NULL
+            WHERE c.deleted_at IS NULL
+              AND lower(mc.name) = lower($1) AND lower(sc.name) = lower($2) AND lower(c.name) = lower($3)
+            ORDER BY c.id
+            LIMIT 1`,
+			strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), strings.TrimSpace(parts[2])).Scan(&id)
+	case strings.Contains(value, "/"):
+		segments := strings.Split(strings.Trim(value, "/"), "/")
+		if len(segments) != 3 {
+			return 0, errors.New("slug yolu ana/alt/kategori biçiminde olmalı")
+		}
+		refs, _, resolveErr := ResolveCategorySlugPath(ctx, segments)
+		if errors.Is(resolveErr, ErrCategorySlugNotFound) {
+			return 0, errors.New("kategori bulunamadı")
+		}
+		if resolveErr != nil {
+			return 0, resolveErr
+		}
+		id = refs[2].ID
+	default:
+		n, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			return 0, errors.New("kategori ID, slug yolu veya ad yolu olmalı")
+		}
+		err = db.GetPool().QueryRow(ctx,
+			"SELECT id FROM categories WHERE id = $1 AND deleted_at IS NULL", n).Scan(&id)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, errors.New("kategori bulunamadı")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	res.categories[value] = id
+	return id, nil
+}
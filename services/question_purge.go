@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"osymapp/db"
+	"osymapp/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const defaultQuestionTrashRetention = 30 * 24 * time.Hour
+
+// Çöp kutusundaki soruların kalıcı silinmeden önce bekleyeceği süre (QUESTION_TRASH_RETENTION)
+func QuestionTrashRetention() time.Duration {
+	v := os.Getenv("QUESTION_TRASH_RETENTION")
+	if v == "" {
+		return defaultQuestionTrashRetention
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("Uyarı: QUESTION_TRASH_RETENTION geçersiz (%s), varsayılan kullanılıyor", v)
+		return defaultQuestionTrashRetention
+	}
+	return d
+}
+
+type QuestionPurgeReport struct {
+	Questions     int      `json:"questions"`
+	RemovedImages []string `json:"removed_images"`
+}
+
+// Saklama süresi dolan soruları ve yalnızca onlara ait resimleri kalıcı olarak siler
+func PurgeTrashedQuestions(ctx context.Context) (*QuestionPurgeReport, error) {
+	pool := db.GetPool()
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("transaction başlatma hatası: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	cutoff := time.Now().Add(-QuestionTrashRetention())
+
+	rows, err := tx.Query(ctx,
+		"SELECT id FROM questions WHERE deleted_at < $1 FOR UPDATE",
+		cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("silinecek sorular alınamadı: %v", err)
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("silinecek soru okunamadı: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("silinecek sorular okunamadı: %v", err)
+	}
+
+	report := &QuestionPurgeReport{Questions: len(ids), RemovedImages: []string{}}
+	if len(ids) == 0 {
+		return report, nil
+	}
+
+	// Silinen soruların güncel ve eski revizyonlarındaki resimler; başka bir
+	// soru veya revizyon tarafından (metne gömülü olarak da) kullanılanlar korunur
+	purged, err := questionImageRefs(ctx, tx, "= ANY($1)", ids)
+	if err != nil {
+		return nil, err
+	}
+	kept, err := questionImageRefs(ctx, tx, "<> ALL($1)", ids)
+	if err != nil {
+		return nil, err
+	}
+	var images []string
+	for url := range purged {
+		if !kept[url] {
+			images = append(images, url)
+		}
+	}
+	sort.Strings(images)
+
+	// Eski kategori ilişkilerinde cascade bulunmadığından önce ilişkiler silinir
+	for _, query := range []string{
+		"DELETE FROM question_categories WHERE question_id = ANY($1)",
+		"DELETE FROM questions WHERE id = ANY($1)",
+	} {
+		if _, err := tx.Exec(ctx, query, ids); err != nil {
+			return nil, fmt.Errorf("sorular silinemedi: %v", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("transaction commit hatası: %v", err)
+	}
+
+	// Dosyalar yalnızca veritabanı değişikliği kalıcı olduktan sonra silinir
+	for _, url := range images {
+		if err := DeleteImage(url); err != nil {
+			log.Printf("Soru resmi silinirken hata: %v", err)
+			continue
+		}
+		report.RemovedImages = append(report.RemovedImages, url)
+	}
+
+	return report, nil
+}
+
+// Koşula uyan soruların ve revizyonlarının resim yolları: resim sütunları ve
+// Markdown ile metne gömülen sunucu resimleri
+func questionImageRefs(ctx context.Context, tx pgx.Tx, idCondition string, ids []int) (map[string]bool, error) {
+	rows, err := tx.Query(ctx, fmt.Sprintf(`
+        SELECT path_url, solution_url, body
+        FROM questions
+        WHERE id %[1]s
+        UNION ALL
+        SELECT snapshot->>'path_url', snapshot->>'solution_url',
+               CASE WHEN jsonb_typeof(snapshot->'body') = 'object' THEN snapshot->'body' END
+        FROM question_revisions
+        WHERE question_id %[1]s`, idCondition), ids)
+	if err != nil {
+		return nil, fmt.Errorf("soru resimleri alınamadı: %v", err)
+	}
+	defer rows.Close()
+
+	refs := make(map[string]bool)
+	for rows.Next() {
+		var pathURL, solutionURL *string
+		var body *models.QuestionBody
+		if err := rows.Scan(&pathURL, &solutionURL, &body); err != nil {
+			return nil, fmt.Errorf("soru resmi okunamadı: %v", err)
+		}
+		for _, url := range []*string{pathURL, solutionURL} {
+			if url != nil && *url != "" {
+				refs[*url] = true
+			}
+		}
+		for _, url := range QuestionBodyImageURLs(body) {
+			refs[url] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("soru resimleri okunamadı: %v", err)
+	}
+	return refs, nil
+}
+
+// Süresi dolan soruları arka planda periyodik olarak temizler
+func StartQuestionPurge(interval time.Duration) {
+	go func() {
+		for {
+			time.Sleep(interval)
+
+			report, err := PurgeTrashedQuestions(context.Background())
+			if err != nil {
+				log.Printf("Soru temizleme hatası: %v", err)
+				continue
+			}
+			if report.Questions > 0 {
+				log.Printf("Soru temizleme: %d soru ve %d resim kalıcı olarak silindi",
+					report.Questions, len(report.RemovedImages))
+			}
+		}
+	}()
+}
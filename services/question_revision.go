@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+
+	"osymapp/models"
+)
+
+// Sorunun veritabanındaki güncel halini okur
+func LoadQuestionSnapshot(ctx context.Context, q DBTX, questionID int) (*models.QuestionSnapshot, error) {
+	var s models.QuestionSnapshot
+	err := q.QueryRow(ctx, `
+        SELECT COALESCE(q.path_url, ''), COALESCE(q.answer, ''), COALESCE(q.solution_url, ''),
+               q.publisher_id, COALESCE(q.difficulty_level, ''), q.body,
+               COALESCE((SELECT array_agg(category_id ORDER BY category_id)
+                         FROM question_categories WHERE question_id = q.id), '{}'),
+               COALESCE((SELECT array_agg(node_id ORDER BY node_id)
+                         FROM question_category_nodes WHERE question_id = q.id), '{}')
+        FROM questions q
+        WHERE q.id = $1`, questionID).Scan(
+		&s.PathURL, &s.Answer, &s.SolutionURL, &s.PublisherID, &s.DifficultyLevel, &s.Body,
+		&s.Categories, &s.Nodes)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Sorunun güncel halini yeni bir revizyon olarak kaydeder ve revizyon numarasını döner.
+// Çağıran, soru satırını aynı transaction içinde güncellemiş (veya kilitlemiş) olmalıdır.
+func RecordQuestionRevision(ctx context.Context, q DBTX, questionID, actorID int, reason string, restoredFrom *int) (int, error) {
+	snapshot, err := LoadQuestionSnapshot(ctx, q, questionID)
+	if err != nil {
+		return 0, err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return 0, err
+	}
+
+	var revision int
+	err = q.QueryRow(ctx, `
+        INSERT INTO question_revisions (question_id, revision, snapshot, reason, restored_from, actor_id)
+        VALUES ($1, (SELECT COALESCE(MAX(revision), 0) + 1 FROM question_revisions WHERE question_id = $1),
+                $2, $3, $4, NULLIF($5, 0))
+        RETURNING revision`,
+		questionID, data, reason, restoredFrom, actorID).Scan(&revision)
+	return revision, err
+}
+
+// Geçmişi olmayan bir soru değiştirilmeden önce mevcut hali ilk revizyon olarak saklanır.
+// Soru satırını kilitler; soru yoksa veya çöp kutusundaysa pgx.ErrNoRows döner.
+func EnsureQuestionBaselineRevision(ctx context.Context, q DBTX, questionID int) error {
+	var hasHistory bool
+	err := q.QueryRow(ctx, `
+        SELECT EXISTS(SELECT 1 FROM question_revisions WHERE question_id = q.id)
+        FROM questions q
+        WHERE q.id = $1 AND q.deleted_at IS NULL
+        FOR UPDATE OF q`, questionID).Scan(&hasHistory)
+	if err != nil || hasHistory {
+		return err
+	}
+
+	_, err = RecordQuestionRevision(ctx, q, questionID, 0, models.RevisionReasonBaseline, nil)
+	return err
+}
+
+// İki revizyon arasındaki alan farklarını listeler
+func DiffQuestionSnapshots(from, to models.QuestionSnapshot) []models.QuestionFieldChange {
+	changes := []models.QuestionFieldChange{}
+
+	fields := []struct {
+		name     string
+		from, to interface{}
+	}{
+		{"path_url", from.PathURL, to.PathURL},
+		{"answer", from.Answer, to.Answer},
+		{"solution_url", from.SolutionURL, to.SolutionURL},
+		{"publisher_id", from.PublisherID, to.PublisherID},
+		{"difficulty_level", from.DifficultyLevel, to.DifficultyLevel},
+	}
+	for _, f := range fields {
+		if f.from != f.to {
+			changes = append(changes, models.QuestionFieldChange{Field: f.name, From: f.from, To: f.to})
+		}
+	}
+
+	if !reflect.DeepEqual(from.Body, to.Body) {
+		changes = append(changes, models.QuestionFieldChange{Field: "body", From: from.Body, To: to.Body})
+	}
+
+	for _, set := range []struct {
+		name     string
+		from, to []int
+	}{
+		{"categories", from.Categories, to.Categories},
+		{"nodes", from.Nodes, to.Nodes},
+	} {
+		added, removed := diffIDSets(set.from, set.to)
+		if len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, models.QuestionFieldChange{
+				Field: set.name, From: set.from, To: set.to, Added: added, Removed: removed,
+			})
+		}
+	}
+
+	return changes
+}
+
+func diffIDSets(from, to []int) (added, removed []int) {
+	for _, id := range to {
+		if !slices.Contains(from, id) {
+			added = append(added, id)
+		}
+	}
+	for _, id := range from {
+		if !slices.Contains(to, id) {
+			removed = append(removed, id)
+		}
+	}
+	return added, removed
+}
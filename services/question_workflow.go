@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"osymapp/db"
+	"osymapp/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Soru üzerinde işlem yapabilen roller
+var (
+	QuestionEditorRoles   = []string{"Admin", "Editor", "Reviewer"}
+	QuestionReviewerRoles = []string{"Admin", "Reviewer"}
+)
+
+var (
+	ErrInvalidQuestionAction     = errors.New("geçersiz işlem")
+	ErrQuestionTransitionInvalid = errors.New("soru bu durumdayken bu işlem yapılamaz")
+	ErrQuestionReviewerRequired  = errors.New("bu işlem için inceleme yetkisi gerekmektedir")
+	ErrQuestionCommentRequired   = errors.New("bu işlem için açıklama gerekmektedir")
+	ErrQuestionSelfApproval      = errors.New("incelemeye gönderdiğiniz soruyu onaylayamazsınız")
+)
+
+type questionTransitionRule struct {
+	from          string
+	to            string
+	reviewerOnly  bool
+	commentNeeded bool
+}
+
+// Durum makinesi: işlem -> izin verilen kaynak durum ve hedef durum
+var questionTransitions = map[string]questionTransitionRule{
+	"submit":  {from: models.QuestionStatusDraft, to: models.QuestionStatusInReview},
+	"approve": {from: models.QuestionStatusInReview, to: models.QuestionStatusPublished, reviewerOnly: true},
+	"reject":  {from: models.QuestionStatusInReview, to: models.QuestionStatusDraft, reviewerOnly: true, commentNeeded: true},
+	"archive": {from: models.QuestionStatusPublished, to: models.QuestionStatusArchived, reviewerOnly: true},
+	"reopen":  {from: models.QuestionStatusArchived, to: models.QuestionStatusDraft},
+}
+
+// İşlemin mevcut durumdan uygulanıp uygulanamayacağını kontrol eder ve hedef durumu döner
+func NextQuestionStatus(action, current string, isReviewer bool, comment string) (string, error) {
+	rule, ok := questionTransitions[action]
+	if !ok {
+		return "", ErrInvalidQuestionAction
+	}
+	if rule.from != current {
+		return "", fmt.Errorf("%w: %s -> %s", ErrQuestionTransitionInvalid, current, action)
+	}
+	if rule.reviewerOnly && !isReviewer {
+		return "", ErrQuestionReviewerRequired
+	}
+	if rule.commentNeeded && comment == "" {
+		return "", ErrQuestionCommentRequired
+	}
+	return rule.to, nil
+}
+
+// Onaylayan kişi soruyu incelemeye gönderen kişi olamaz. Gönderen, soruyu en son incelemeye
+// alan durum geçişinin sahibidir (gönderim veya yayındaki sorunun düzenlenmesi).
+func CheckQuestionApprover(ctx context.Context, q DBTX, questionID, actorID int) error {
+	var submitterID *int
+	err := q.QueryRow(ctx, `
+        SELECT actor_id FROM question_status_transitions
+        WHERE question_id = $1 AND to_status = $2
+        ORDER BY created_at DESC, id DESC
+        LIMIT 1`, questionID, models.QuestionStatusInReview).Scan(&submitterID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if submitterID != nil && *submitterID == actorID {
+		return ErrQuestionSelfApproval
+	}
+	return nil
+}
+
+// Kullanıcının verilen rollerden en az birine sahip olup olmadığını kontrol eder
+func UserHasRole(ctx context.Context, userID int, roles ...string) (bool, error) {
+	var has bool
+	err := db.GetPool().QueryRow(ctx,
+		`SELECT EXISTS(
+            SELECT 1
+            FROM public.roles r
+            JOIN public.users_roles ur ON r.id = ur.role_id
+            WHERE ur.user_id = $1 AND r.name = ANY($2)
+        )`,
+		userID, roles).Scan(&has)
+	return has, err
+}
+
+// Yayındaki sorularda içerik değiştiğinde kaydedilen otomatik geçiş işlemleri
+const (
+	QuestionActionEdit    = "edit"
+	QuestionActionRestore = "restore"
+)
+
+// Yayındaki bir sorunun içeriği değiştiyse soru yeniden incelemeye alınır ve geçiş kaydedilir.
+// before, değişiklikten önce aynı transaction içinde okunan haldir; içerik aynıysa durum korunur.
+func ReturnEditedQuestionToReview(ctx context.Context, q DBTX, questionID, actorID int, action string, before *models.QuestionSnapshot) error {
+	after, err := LoadQuestionSnapshot(ctx, q, questionID)
+	if err != nil {
+		return err
+	}
+	if len(DiffQuestionSnapshots(*before, *after)) == 0 {
+		return nil
+	}
+
+	_, err = q.Exec(ctx, `
+        WITH reopened AS (
+            UPDATE questions SET status = $2
+            WHERE id = $1 AND status = $3
+            RETURNING id
+        )
+        INSERT INTO question_status_transitions (question_id, action, from_status, to_status, actor_id, comment)
+        SELECT id, $4, $3, $2, NULLIF($5, 0), $6 FROM reopened`,
+		questionID, models.QuestionStatusInReview, models.QuestionStatusPublished,
+		action, actorID, "Yayındaki soru değiştirildiği için yeniden incelemeye alındı")
+	return err
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"osymapp/db"
+	"osymapp/models"
+	"osymapp/srs"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Bu süreden uzun düşünülerek verilen doğru cevaplar "zor" sayılır
+const slowAnswerMS = 2 * 60 * 1000
+
+// Tekrar zamanlayıcısının saati
+var reviewClock srs.Clock = srs.SystemClock{}
+
+// Yeni açılan kartlarda kullanılacak algoritma (REVIEW_SCHEDULER: sm2 veya fsrs)
+func ReviewAlgorithm() string {
+	v := os.Getenv("REVIEW_SCHEDULER")
+	switch v {
+	case "":
+		return srs.AlgorithmSM2
+	case srs.AlgorithmSM2, srs.AlgorithmFSRS:
+		return v
+	}
+	log.Printf("Uyarı: REVIEW_SCHEDULER geçersiz (%s), varsayılan kullanılıyor", v)
+	return srs.AlgorithmSM2
+}
+
+// Cevap sonucunu tekrar değerlendirmesine çevirir
+func ReviewGrade(correct bool, timeSpentMS *int) srs.Grade {
+	if !correct {
+		return srs.Again
+	}
+	if timeSpentMS != nil && *timeSpentMS > slowAnswerMS {
+		return srs.Hard
+	}
+	return srs.Good
+}
+
+// Cevabı kullanıcının tekrar kartına işler. Kart yoksa yalnızca yanlış cevapta
+// açılır; doğru cevaplanan ve hiç kaçırılmamış sorular zamanlanmaz. Tekrar kuyruğu
+// dışından gelen cevaplar kartı yalnızca tekrar zamanı geldiyse ilerletir.
+func UpdateReviewCard(ctx context.Context, q DBTX, userID, questionID int, grade srs.Grade, fromReviewQueue bool) error {
+	var card srs.Card
+	var algorithm string
+	err := q.QueryRow(ctx, `
+        SELECT algorithm, repetitions, lapses, interval_days, ease_factor, stability, difficulty,
+               last_reviewed_at, due_at
+        FROM review_cards WHERE user_id = $1 AND question_id = $2
+        FOR UPDATE`, userID, questionID).Scan(
+		&algorithm, &card.Repetitions, &card.Lapses, &card.Interval, &card.EaseFactor,
+		&card.Stability, &card.Difficulty, &card.LastReview, &card.Due)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if grade != srs.Again {
+			return nil
+		}
+		algorithm = ReviewAlgorithm()
+	} else if err != nil {
+		return err
+	}
+
+	scheduler, err := srs.New(algorithm, reviewClock)
+	if err != nil {
+		return err
+	}
+	if fromReviewQueue {
+		card = scheduler.Review(card, grade)
+	} else {
+		var applied bool
+		if card, applied = srs.ReviewIfDue(scheduler, reviewClock, card, grade); !applied {
+			return nil
+		}
+	}
+
+	_, err = q.Exec(ctx, `
+        INSERT INTO review_cards (user_id, question_id, algorithm, repetitions, lapses, interval_days,
+                                  ease_factor, stability, difficulty, last_reviewed_at, due_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        ON CONFLICT (user_id, question_id) DO UPDATE
+        SET repetitions = EXCLUDED.repetitions,
+            lapses = EXCLUDED.lapses,
+            interval_days = EXCLUDED.interval_days,
+            ease_factor = EXCLUDED.ease_factor,
+            stability = EXCLUDED.stability,
+            difficulty = EXCLUDED.difficulty,
+            last_reviewed_at = EXCLUDED.last_reviewed_at,
+            due_at = EXCLUDED.due_at`,
+		userID, questionID, algorithm, card.Repetitions, card.Lapses, card.Interval,
+		card.EaseFactor, card.Stability, card.Difficulty, card.LastReview, card.Due)
+	return err
+}
+
+// Bugün (gün sonuna kadar) tekrar zamanı gelen, yayındaki sorulara ait kartlar. Kartlar alt kategorilere
+// sırayla dağıtılır; her alt kategoride en çok gecikmiş kart önce gelir.
+func GetDueReviewCards(ctx context.Context, userID, limit int) ([]models.ReviewCard, int, time.Time, error) {
+	now := reviewClock.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	rows, err := db.GetPool().Query(ctx, `
+        SELECT rc.question_id, rc.algorithm, rc.repetitions, rc.lapses, rc.interval_days,
+               rc.ease_factor, rc.stability, rc.difficulty, rc.last_reviewed_at, rc.due_at,
+               COALESCE((SELECT MIN(c.sub_category_id)
+                         FROM question_categories qc
+                         JOIN categories c ON c.id = qc.category_id AND c.deleted_at IS NULL
+                         WHERE qc.question_id = rc.question_id), 0)
+        FROM review_cards rc
+        JOIN questions q ON q.id = rc.question_id AND q.deleted_at IS NULL AND q.status = $3
+        WHERE rc.user_id = $1 AND rc.due_at < $2
+        ORDER BY rc.due_at, rc.question_id`, userID, today.AddDate(0, 0, 1), models.QuestionStatusPublished)
+	if err != nil {
+		return nil, 0, today, err
+	}
+	defer rows.Close()
+
+	var cards []models.ReviewCard
+	for rows.Next() {
+		var c models.ReviewCard
+		if err := rows.Scan(&c.QuestionID, &c.Algorithm, &c.Repetitions, &c.Lapses, &c.IntervalDays,
+			&c.EaseFactor, &c.Stability, &c.Difficulty, &c.LastReviewedAt, &c.DueAt, &c.SubCategoryID); err != nil {
+			return nil, 0, today, err
+		}
+		cards = append(cards, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, today, err
+	}
+
+	return balanceReviewCards(cards, limit), len(cards), today, nil
+}
+
+// Tarihe göre sıralı kartları alt kategoriler arasında sırayla seçer
+func balanceReviewCards(cards []models.ReviewCard, limit int) []models.ReviewCard {
+	groups := make(map[int][]models.ReviewCard)
+	var order []int
+	for _, c := range cards {
+		if _, ok := groups[c.SubCategoryID]; !ok {
+			order = append(order, c.SubCategoryID)
+		}
+		groups[c.SubCategoryID] = append(groups[c.SubCategoryID], c)
+	}
+
+	balanced := []models.ReviewCard{}
+	for len(balanced) < limit && len(order) > 0 {
+		remaining := order[:0]
+		for _, area := range order {
+			if len(balanced) == limit {
+				break
+			}
+			balanced = append(balanced, groups[area][0])
+			groups[area] = groups[area][1:]
+			if len(groups[area]) > 0 {
+				remaining = append(remaining, area)
+			}
+		}
+		order = remaining
+	}
+	return balanced
+}
+
+// Algoritma başına kart ve tekrar istatistikleri
+func GetReviewAlgorithmStats(ctx context.Context) ([]models.ReviewAlgorithmStats, error) {
+	rows, err := db.GetPool().Query(ctx, `
+        SELECT rc.algorithm,
+               COUNT(*),
+               COUNT(*) FILTER (WHERE rc.due_at < $1),
+               COALESCE(AVG(rc.interval_days), 0),
+               COALESCE(SUM(rc.lapses), 0),
+               COALESCE(SUM(r.reviews), 0),
+               COALESCE(SUM(r.correct)::float / NULLIF(SUM(r.reviews), 0), 0)
+        FROM review_cards rc
+        LEFT JOIN LATERAL (
+            SELECT COUNT(*) AS reviews, COUNT(*) FILTER (WHERE a.is_correct) AS correct
+            FROM question_attempts a
+            WHERE a.user_id = rc.user_id AND a.question_id = rc.question_id AND a.source = $2
+        ) r ON true
+        GROUP BY rc.algorithm
+        ORDER BY rc.algorithm`, reviewClock.Now(), models.AttemptSourceReview)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []models.ReviewAlgorithmStats{}
+	for rows.Next() {
+		var s models.ReviewAlgorithmStats
+		if err := rows.Scan(&s.Algorithm, &s.Cards, &s.Overdue, &s.AvgIntervalDays, &s.Lapses,
+			&s.Reviews, &s.RecallRate); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"osymapp/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Desteklenen taksonomi dosya formatları
+const (
+	TaxonomyFormatJSON = "json"
+	TaxonomyFormatCSV  = "csv"
+	TaxonomyFormatYAML = "yaml"
+)
+
+var taxonomyCSVHeader = []string{"main_category", "sub_category", "category"}
+
+// İstek parametresinden veya Content-Type başlığından formatı belirler
+func TaxonomyFormat(param, contentType string) (string, error) {
+	format := strings.ToLower(param)
+	if format == "" {
+		switch {
+		case strings.Contains(contentType, "csv"):
+			format = TaxonomyFormatCSV
+		case strings.Contains(contentType, "yaml"):
+			format = TaxonomyFormatYAML
+		default:
+			format = TaxonomyFormatJSON
+		}
+	}
+
+	switch format {
+	case TaxonomyFormatJSON, TaxonomyFormatCSV:
+		return format, nil
+	case TaxonomyFormatYAML, "yml":
+		return TaxonomyFormatYAML, nil
+	}
+	return "", fmt.Errorf("desteklenmeyen format: %s", format)
+}
+
+func TaxonomyContentType(format string) string {
+	switch format {
+	case TaxonomyFormatCSV:
+		return "text/csv; charset=utf-8"
+	case TaxonomyFormatYAML:
+		return "application/yaml; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// Taksonomi dosyasını ana kategori listesine çözer
+func DecodeTaxonomy(r io.Reader, format string) ([]models.CategoryRequest, error) {
+	var taxonomy []models.CategoryRequest
+
+	switch format {
+	case TaxonomyFormatJSON:
+		if err := json.NewDecoder(r).Decode(&taxonomy); err != nil {
+			return nil, fmt.Errorf("JSON parse hatası: %v", err)
+		}
+	case TaxonomyFormatYAML:
+		if err := yaml.NewDecoder(r).Decode(&taxonomy); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("YAML parse hatası: %v", err)
+		}
+	case TaxonomyFormatCSV:
+		var err error
+		if taxonomy, err = decodeTaxonomyCSV(r); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("desteklenmeyen format: %s", format)
+	}
+
+	// CSV hücreleri okunurken kırpılır; JSON ve YAML adları da aynı şekilde eşleşsin
+	trimTaxonomyNames(taxonomy)
+	return taxonomy, validateTaxonomy(taxonomy)
+}
+
+func trimTaxonomyNames(taxonomy []models.CategoryRequest) {
+	for i := range taxonomy {
+		main := &taxonomy[i]
+		main.MainCategory = strings.TrimSpace(main.MainCategory)
+		for j := range main.SubCategories {
+			sub := &main.SubCategories[j]
+			sub.SubCategory = strings.TrimSpace(sub.SubCategory)
+			for k := range sub.Categories {
+				sub.Categories[k] = strings.TrimSpace(sub.Categories[k])
+			}
+		}
+	}
+}
+
+// Ana kategori listesini istenen formatta yazar
+func EncodeTaxonomy(w io.Writer, format string, taxonomy []models.CategoryRequest) error {
+	switch format {
+	case TaxonomyFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(taxonomy)
+	case TaxonomyFormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(taxonomy); err != nil {
+			return err
+		}
+		return enc.Close()
+	case TaxonomyFormatCSV:
+		return encodeTaxonomyCSV(w, taxonomy)
+	}
+	return fmt.Errorf("desteklenmeyen format: %s", format)
+}
+
+// CSV her satırda bir kategori içerir: main_category,sub_category,category.
+// Kategorisi olmayan alt kategoriler ve alt kategorisi olmayan ana kategoriler boş hücreyle yazılır.
+func decodeTaxonomyCSV(r io.Reader) ([]models.CategoryRequest, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("CSV parse hatası: %v", err)
+	}
+
+	if len(records) > 0 && strings.EqualFold(strings.TrimSpace(records[0][0]), taxonomyCSVHeader[0]) {
+		records = records[1:]
+	}
+
+	var taxonomy []models.CategoryRequest
+	mainIndex := make(map[string]int)
+	subIndex := make(map[[2]string]int)
+
+	for i, record := range records {
+		for len(record) < len(taxonomyCSVHeader) {
+			record = append(record, "")
+		}
+		main, sub, category := strings.TrimSpace(record[0]), strings.TrimSpace(record[1]), strings.TrimSpace(record[2])
+
+		if main == "" {
+			return nil, fmt.Errorf("CSV satır %d: ana kategori boş olamaz", i+1)
+		}
+		if sub == "" && category != "" {
+			return nil, fmt.Errorf("CSV satır %d: alt kategori olmadan kategori tanımlanamaz", i+1)
+		}
+
+		mi, ok := mainIndex[main]
+		if !ok {
+			mi = len(taxonomy)
+			mainIndex[main] = mi
+			taxonomy = append(taxonomy, models.CategoryRequest{MainCategory: main, SubCategories: []models.SubCategoryGroup{}})
+		}
+		if sub == "" {
+			continue
+		}
+
+		key := [2]string{main, sub}
+		si, ok := subIndex[key]
+		if !ok {
+			si = len(taxonomy[mi].SubCategories)
+			subIndex[key] = si
+			taxonomy[mi].SubCategories = append(taxonomy[mi].SubCategories, models.SubCategoryGroup{SubCategory: sub, Categories: []string{}})
+		}
+		if category != "" {
+			group := &taxonomy[mi].SubCategories[si]
+			group.Categories = append(group.Categories, category)
+		}
+	}
+
+	return taxonomy, nil
+}
+
+func encodeTaxonomyCSV(w io.Writer, taxonomy []models.CategoryRequest) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(taxonomyCSVHeader); err != nil {
+		return err
+	}
+
+	for _, main := range taxonomy {
+		if len(main.SubCategories) == 0 {
+			writer.Write([]string{main.MainCategory, "", ""})
+		}
+		for _, sub := range main.SubCategories {
+			if len(sub.Categories) == 0 {
+				writer.Write([]string{main.MainCategory, sub.SubCategory, ""})
+			}
+			for _, category := range sub.Categories {
+				writer.Write([]string{main.MainCategory, sub.SubCategory, category})
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func validateTaxonomy(taxonomy []models.CategoryRequest) error {
+	for _, main := range taxonomy {
+		if strings.TrimSpace(main.MainCategory) == "" {
+			return fmt.Errorf("ana kategori adı boş olamaz")
+		}
+		for _, sub := range main.SubCategories {
+			if strings.TrimSpace(sub.SubCategory) == "" {
+				return fmt.Errorf("%s: alt kategori adı boş olamaz", main.MainCategory)
+			}
+			for _, category := range sub.Categories {
+				if strings.TrimSpace(category) == "" {
+					return fmt.Errorf("%s > %s: kategori adı boş olamaz", main.MainCategory, sub.SubCategory)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"osymapp/models"
+)
+
+func TestDecodeTaxonomyTrimsNamesInAllFormats(t *testing.T) {
+	want := []models.CategoryRequest{{
+		MainCategory: "TYT",
+		SubCategories: []models.SubCategoryGroup{
+			{SubCategory: "Matematik", Categories: []string{"Problemler", "Sayılar"}},
+		},
+	}}
+
+	inputs := map[string]string{
+		TaxonomyFormatJSON: `[{"main_category": " TYT ", "sub_categories": [
+			{"sub_category": "Matematik ", "categories": [" Problemler", "Sayılar "]}]}]`,
+		TaxonomyFormatYAML: "- main_category: \" TYT \"\n  sub_categories:\n" +
+			"    - sub_category: \"Matematik \"\n      categories: [\" Problemler\", \"Sayılar \"]\n",
+		TaxonomyFormatCSV: "main_category,sub_category,category\n TYT , Matematik , Problemler \nTYT,Matematik,Sayılar \n",
+	}
+
+	for format, input := range inputs {
+		got, err := DecodeTaxonomy(strings.NewReader(input), format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: %+v, beklenen %+v", format, got, want)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"osymapp/db"
+	"osymapp/models"
+)
+
+// Zayıf konu listesinde yer alabilmek için gereken en az cevap sayısı
+const DefaultWeakTopicMinAttempts = 10
+
+const weakestTopicCount = 5
+
+// Cevabı sorunun bağlı olduğu tüm konuların günlük toplamlarına ekler.
+// Satırlar sabit sırayla kilitlenir; aynı kullanıcının eş zamanlı cevapları kilitlenmez.
+func recordAttemptStats(ctx context.Context, q DBTX, userID, questionID int, correct bool, timeSpentMS *int) error {
+	correctCount, timed, spent := 0, 0, 0
+	if correct {
+		correctCount = 1
+	}
+	if timeSpentMS != nil {
+		timed, spent = 1, *timeSpentMS
+	}
+
+	_, err := q.Exec(ctx, `
+        INSERT INTO user_topic_stats_daily AS s
+               (user_id, level, node_id, day, attempts, correct, timed_attempts, time_spent_ms)
+        SELECT $1::int, stat_level, stat_node_id, CURRENT_DATE, 1, $3::int, $4::int, $5::bigint
+        FROM question_stat_scopes($2)
+        ORDER BY stat_level, stat_node_id
+        ON CONFLICT (user_id, level, node_id, day) DO UPDATE
+        SET attempts = s.attempts + 1,
+            correct = s.correct + EXCLUDED.correct,
+            timed_attempts = s.timed_attempts + EXCLUDED.timed_attempts,
+            time_spent_ms = s.time_spent_ms + EXCLUDED.time_spent_ms`,
+		userID, questionID, correctCount, timed, spent)
+	return err
+}
+
+type UserTopicStatsReport struct {
+	Rows int `json:"rows"`
+}
+
+// Günlük konu toplamlarını cevap geçmişinden yeniden üretir. Cevaplar konulara
+// verildikleri anda sayıldığından, kategori taşıma ve birleştirmelerinden sonra çalıştırılır.
+func RebuildUserTopicStats(ctx context.Context) (*UserTopicStatsReport, error) {
+	tx, err := db.GetPool().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("transaction başlatma hatası: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM user_topic_stats_daily"); err != nil {
+		return nil, fmt.Errorf("istatistikler silinemedi: %v", err)
+	}
+	tag, err := tx.Exec(ctx, `
+        INSERT INTO user_topic_stats_daily (user_id, level, node_id, day, attempts, correct, timed_attempts, time_spent_ms)
+        SELECT a.user_id, s.stat_level, s.stat_node_id, a.day,
+               SUM(a.attempts), SUM(a.correct), SUM(a.timed_attempts), SUM(a.time_spent_ms)
+        FROM (
+            SELECT user_id, question_id, created_at::date AS day,
+                   COUNT(*) AS attempts,
+                   COUNT(*) FILTER (WHERE is_correct) AS correct,
+                   COUNT(time_spent_ms) AS timed_attempts,
+                   COALESCE(SUM(time_spent_ms), 0) AS time_spent_ms
+            FROM question_attempts
+            GROUP BY user_id, question_id, created_at::date
+        ) a
+        CROSS JOIN LATERAL question_stat_scopes(a.question_id) s
+        GROUP BY a.user_id, s.stat_level, s.stat_node_id, a.day`)
+	if err != nil {
+		return nil, fmt.Errorf("istatistikler üretilemedi: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("transaction commit hatası: %v", err)
+	}
+	return &UserTopicStatsReport{Rows: int(tag.RowsAffected())}, nil
+}
+
+type topicKey struct {
+	level string
+	id    int
+}
+
+type topicTotals struct {
+	attempts, correct, timed int
+	spent                    int64
+}
+
+func (t topicTotals) stats() models.PerformanceStats {
+	s := models.PerformanceStats{
+		Attempts: t.attempts,
+		Correct:  t.correct,
+		Wrong:    t.attempts - t.correct,
+	}
+	if t.attempts > 0 {
+		s.Accuracy = float64(t.correct) / float64(t.attempts)
+	}
+	s.Net = float64(s.Correct) - float64(s.Wrong)/models.NetPenaltyRatio
+	if t.timed > 0 {
+		avg := float64(t.spent) / float64(t.timed)
+		s.AvgTimeMS = &avg
+	}
+	return s
+}
+
+// Kullanıcının tüm konulardaki performansı; net trendi son `weeks` hafta için haftalık verilir
+func GetUserStats(ctx context.Context, userID, weeks, minAttempts int) (*models.UserStats, error) {
+	pool := db.GetPool()
+
+	totals := make(map[topicKey]topicTotals)
+	rows, err := pool.Query(ctx, `
+        SELECT level, node_id, SUM(attempts), SUM(correct), SUM(timed_attempts), SUM(time_spent_ms)
+        FROM user_topic_stats_daily
+        WHERE user_id = $1
+        GROUP BY level, node_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var k topicKey
+		var t topicTotals
+		if err := rows.Scan(&k.level, &k.id, &t.attempts, &t.correct, &t.timed, &t.spent); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		totals[k] = t
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	since := today.AddDate(0, 0, -7*weeks)
+	trends := make(map[topicKey][]models.NetTrendPoint)
+	rows, err = pool.Query(ctx, `
+        SELECT level, node_id, date_trunc('week', day)::date AS week,
+               SUM(attempts), SUM(correct), SUM(timed_attempts), SUM(time_spent_ms)
+        FROM user_topic_stats_daily
+        WHERE user_id = $1 AND day >= $2
+        GROUP BY level, node_id, week
+        ORDER BY week`, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var k topicKey
+		var week time.Time
+		var t topicTotals
+		if err := rows.Scan(&k.level, &k.id, &week, &t.attempts, &t.correct, &t.timed, &t.spent); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		trends[k] = append(trends[k], models.NetTrendPoint{
+			WeekStart:        week.Format("2006-01-02"),
+			PerformanceStats: t.stats(),
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stats := &models.UserStats{
+		Overall: totals[topicKey{models.StatsLevelAll, 0}].stats(),
+		Trend:   trends[topicKey{models.StatsLevelAll, 0}],
+	}
+	if stats.Trend == nil {
+		stats.Trend = []models.NetTrendPoint{}
+	}
+
+	levels := []struct {
+		level string
+		query string
+		dest  *[]models.TopicPerformance
+	}{
+		{models.StatsLevelMain, "SELECT id, name, NULL::int FROM main_categories WHERE id = ANY($1) AND deleted_at IS NULL", &stats.MainCategories},
+		{models.StatsLevelSub, "SELECT id, name, NULL::int FROM sub_categories WHERE id = ANY($1) AND deleted_at IS NULL", &stats.SubCategories},
+		{models.StatsLevelCategory, "SELECT id, name, sub_category_id FROM categories WHERE id = ANY($1) AND deleted_at IS NULL", &stats.Categories},
+		{models.StatsLevelNode, "SELECT id, name, parent_id FROM category_tree WHERE id = ANY($1) AND deleted_at IS NULL", &stats.Nodes},
+	}
+	for _, l := range levels {
+		var ids []int
+		for k := range totals {
+			if k.level == l.level {
+				ids = append(ids, k.id)
+			}
+		}
+
+		// Silinmiş konular listelenmez
+		*l.dest = []models.TopicPerformance{}
+		rows, err := pool.Query(ctx, l.query, ids)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			t := models.TopicPerformance{Level: l.level}
+			if err := rows.Scan(&t.ID, &t.Name, &t.ParentID); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			key := topicKey{l.level, t.ID}
+			t.PerformanceStats = totals[key].stats()
+			t.Trend = trends[key]
+			if t.Trend == nil {
+				t.Trend = []models.NetTrendPoint{}
+			}
+			*l.dest = append(*l.dest, t)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		sort.Slice(*l.dest, func(i, j int) bool {
+			a, b := (*l.dest)[i], (*l.dest)[j]
+			if a.Attempts != b.Attempts {
+				return a.Attempts > b.Attempts
+			}
+			return a.ID < b.ID
+		})
+	}
+
+	stats.Weakest = weakestTopics(stats.Categories, minAttempts)
+	return stats, nil
+}
+
+// Yeterince cevaplanmış konular arasından başarısı en düşük olanlar
+func weakestTopics(topics []models.TopicPerformance, minAttempts int) []models.TopicPerformance {
+	weakest := []models.TopicPerformance{}
+	for _, t := range topics {
+		if t.Attempts >= minAttempts {
+			weakest = append(weakest, t)
+		}
+	}
+	sort.SliceStable(weakest, func(i, j int) bool {
+		if weakest[i].Accuracy != weakest[j].Accuracy {
+			return weakest[i].Accuracy < weakest[j].Accuracy
+		}
+		return weakest[i].Attempts > weakest[j].Attempts
+	})
+	if len(weakest) > weakestTopicCount {
+		weakest = weakest[:weakestTopicCount]
+	}
+	return weakest
+}
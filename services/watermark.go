@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"osymapp/db"
+	"osymapp/models"
+
+	"github.com/jackc/pgx/v5"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	_ "image/gif"
+)
+
+const (
+	// Filigranlı resimlerin önbelleği; orijinal dosyalara dokunulmaz
+	WatermarkCachePath = "images/cache/watermarks"
+	// Yayıncı logoları herkese açık dizinde tutulur
+	PublisherLogosPath = PublicImagesPath + "/publishers"
+)
+
+const (
+	watermarkScale  = 0.25 // filigran genişliğinin resim genişliğine oranı
+	watermarkMargin = 0.02 // kenar boşluğunun kısa kenara oranı
+)
+
+var WatermarkPositions = []string{"top-left", "top-right", "bottom-left", "bottom-right", "center"}
+
+type WatermarkSettings struct {
+	PublisherID int
+	Text        string // Logo yoksa yayıncı adı basılır
+	LogoURL     string
+	Position    string
+	Opacity     float64
+}
+
+// Aynı resmin eşzamanlı isteklerde birden fazla kez işlenmesini engeller
+var watermarkLocks = struct {
+	sync.Mutex
+	keys map[string]*sync.Mutex
+}{keys: make(map[string]*sync.Mutex)}
+
+func IsValidWatermarkPosition(position string) bool {
+	for _, p := range WatermarkPositions {
+		if p == position {
+			return true
+		}
+	}
+	return false
+}
+
+// Resmin bağlı olduğu sorunun yayıncısı filigran kullanıyorsa ayarlarını döner, aksi halde nil.
+// Resmin sahibi, soru kaydedilirken doldurulan question_images tablosundan bulunur.
+func PublisherWatermarkForImage(ctx context.Context, url string) (*WatermarkSettings, error) {
+	var s WatermarkSettings
+	err := db.GetPool().QueryRow(ctx, `
+		SELECT p.id, p.name, COALESCE(p.logo_url, ''), p.watermark_position, p.watermark_opacity
+		FROM question_images qi
+		JOIN questions q ON q.id = qi.question_id
+		JOIN publishers p ON p.id = q.publisher_id
+		WHERE qi.url = $1 AND p.watermark_enabled
+		LIMIT 1`, url).Scan(&s.PublisherID, &s.Text, &s.LogoURL, &s.Position, &s.Opacity)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("yayıncı filigran ayarları alınamadı: %v", err)
+	}
+	return &s, nil
+}
+
+// Sorunun kayıtlı haline göre kullandığı resimleri (soru, çözüm ve metne gömülü resimler)
+// question_images tablosuna yeniden yazar; soru her kaydedildiğinde aynı transaction içinde çağrılır.
+func SetQuestionImages(ctx context.Context, q DBTX, questionID int) error {
+	var pathURL, solutionURL string
+	var body *models.QuestionBody
+	if err := q.QueryRow(ctx, `
+		SELECT COALESCE(path_url, ''), COALESCE(solution_url, ''), body FROM questions WHERE id = $1`,
+		questionID).Scan(&pathURL, &solutionURL, &body); err != nil {
+		return fmt.Errorf("soru resimleri alınamadı: %v", err)
+	}
+
+	urls := append([]string{pathURL, solutionURL}, QuestionBodyImageURLs(body)...)
+	var images []string
+	for _, url := range urls {
+		if url != "" {
+			images = append(images, url)
+		}
+	}
+
+	if _, err := q.Exec(ctx, `DELETE FROM question_images WHERE question_id = $1`, questionID); err != nil {
+		return fmt.Errorf("soru resimleri silinemedi: %v", err)
+	}
+	if _, err := q.Exec(ctx, `
+		INSERT INTO question_images (question_id, url)
+		SELECT $1, url FROM unnest($2::text[]) AS url
+		ON CONFLICT DO NOTHING`, questionID, images); err != nil {
+		return fmt.Errorf("soru resimleri kaydedilemedi: %v", err)
+	}
+	return nil
+}
+
+// Filigranlı resmin önbellekteki yolunu döner, yoksa oluşturur.
+// Önbellek anahtarı ayarları ve logo dosyasının değişim zamanını içerir,
+// böylece ayar değişikliği eski varyantları kendiliğinden geçersiz kılar.
+func WatermarkedImagePath(srcPath string, s WatermarkSettings) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%s|%s|%.3f", srcPath, s.Text, s.LogoURL, s.Position, s.Opacity)
+	if s.LogoURL != "" {
+		if info, err := os.Stat(strings.TrimPrefix(s.LogoURL, "/")); err == nil {
+			fmt.Fprintf(h, "|%d", info.ModTime().UnixNano())
+		}
+	}
+	key := hex.EncodeToString(h.Sum(nil))[:32]
+
+	ext := ".jpg"
+	if strings.EqualFold(filepath.Ext(srcPath), ".png") {
+		ext = ".png"
+	}
+	cachePath := filepath.Join(WatermarkCachePath, strconv.Itoa(s.PublisherID), key+ext)
+
+	if _, err := os.Stat(cachePath); err == nil {
+		return cachePath, nil
+	}
+
+	watermarkLocks.Lock()
+	lock, ok := watermarkLocks.keys[cachePath]
+	if !ok {
+		lock = &sync.Mutex{}
+		watermarkLocks.keys[cachePath] = lock
+	}
+	watermarkLocks.Unlock()
+
+	lock.Lock()
+	defer func() {
+		lock.Unlock()
+		watermarkLocks.Lock()
+		delete(watermarkLocks.keys, cachePath)
+		watermarkLocks.Unlock()
+	}()
+
+	// Kilidi beklerken başka bir istek oluşturmuş olabilir
+	if _, err := os.Stat(cachePath); err == nil {
+		return cachePath, nil
+	}
+
+	if err := renderWatermark(srcPath, cachePath, s); err != nil {
+		return "", err
+	}
+	return cachePath, nil
+}
+
+// Yayıncının önbellekteki tüm filigranlı resimlerini siler
+func PurgeWatermarkCache(publisherID int) error {
+	if err := os.RemoveAll(filepath.Join(WatermarkCachePath, strconv.Itoa(publisherID))); err != nil {
+		return fmt.Errorf("filigran önbelleği silinemedi: %v", err)
+	}
+	return nil
+}
+
+func renderWatermark(srcPath, dstPath string, s WatermarkSettings) error {
+	src, err := decodeImageFile(srcPath)
+	if err != nil {
+		return err
+	}
+
+	mark, err := watermarkMark(s)
+	if err != nil {
+		return err
+	}
+
+	bounds := src.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(out, out.Bounds(), src, bounds.Min, draw.Src)
+
+	// Filigranı en-boy oranını koruyarak ölçekle
+	mb := mark.Bounds()
+	width := int(float64(bounds.Dx()) * watermarkScale)
+	height := width * mb.Dy() / mb.Dx()
+	if maxHeight := int(float64(bounds.Dy()) * watermarkScale); height > maxHeight {
+		height = maxHeight
+		width = height * mb.Dx() / mb.Dy()
+	}
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("resim filigran için çok küçük")
+	}
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(scaled, scaled.Bounds(), mark, mb, xdraw.Over, nil)
+
+	margin := int(float64(min(bounds.Dx(), bounds.Dy())) * watermarkMargin)
+	var at image.Point
+	switch s.Position {
+	case "top-left":
+		at = image.Pt(margin, margin)
+	case "top-right":
+		at = image.Pt(bounds.Dx()-width-margin, margin)
+	case "bottom-left":
+		at = image.Pt(margin, bounds.Dy()-height-margin)
+	case "center":
+		at = image.Pt((bounds.Dx()-width)/2, (bounds.Dy()-height)/2)
+	default:
+		at = image.Pt(bounds.Dx()-width-margin, bounds.Dy()-height-margin)
+	}
+
+	alpha := image.NewUniform(color.Alpha{A: uint8(s.Opacity * 255)})
+	draw.DrawMask(out, image.Rectangle{Min: at, Max: at.Add(scaled.Bounds().Size())},
+		scaled, image.Point{}, alpha, image.Point{}, draw.Over)
+
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return fmt.Errorf("dizin oluşturma hatası: %v", err)
+	}
+
+	// Yarım yazılmış dosyanın sunulmaması için önce geçici dosyaya yaz
+	tmp, err := os.CreateTemp(filepath.Dir(dstPath), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("dosya oluşturma hatası: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if filepath.Ext(dstPath) == ".png" {
+		err = png.Encode(tmp, out)
+	} else {
+		err = jpeg.Encode(tmp, out, &jpeg.Options{Quality: 90})
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("filigranlı resim yazılamadı: %v", err)
+	}
+
+	return os.Rename(tmp.Name(), dstPath)
+}
+
+// Yayıncı logosunu, logo yoksa yayıncı adından oluşturulan yazıyı döner
+func watermarkMark(s WatermarkSettings) (image.Image, error) {
+	if s.LogoURL != "" {
+		return decodeImageFile(strings.TrimPrefix(s.LogoURL, "/"))
+	}
+	if s.Text == "" {
+		return nil, fmt.Errorf("filigran için logo veya yayıncı adı gerekli")
+	}
+
+	face := basicfont.Face7x13
+	width := font.MeasureString(face, s.Text).Ceil() + 2
+	height := face.Metrics().Height.Ceil() + 2
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	// Açık ve koyu zeminde okunabilmesi için gölgeli yaz
+	for _, layer := range []struct {
+		offset int
+		color  color.Color
+	}{{1, color.Black}, {0, color.White}} {
+		d := &font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(layer.color),
+			Face: face,
+			Dot:  fixed.P(layer.offset, face.Metrics().Ascent.Ceil()+layer.offset),
+		}
+		d.DrawString(s.Text)
+	}
+	return img, nil
+}
+
+func decodeImageFile(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("resim açılamadı: %v", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("resim çözümlenemedi: %v", err)
+	}
+	return img, nil
+}
@@ -0,0 +1,26 @@
+package srs
+
+import "time"
+
+// Testlerde elle ilerletilen saat
+type fakeClock struct {
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) advanceDays(days int) {
+	c.now = c.now.AddDate(0, 0, days)
+}
+
+// Kartın tekrar tarihine kadar saati ilerletip değerlendirir
+func reviewOnDue(s Scheduler, clock *fakeClock, card Card, grade Grade) Card {
+	if card.LastReview != nil {
+		clock.now = card.Due
+	}
+	return s.Review(card, grade)
+}
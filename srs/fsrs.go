@@ -0,0 +1,121 @@
+package srs
+
+import "math"
+
+// FSRS-4.5 unutma eğrisi sabitleri
+const (
+	fsrsDecay  = -0.5
+	fsrsFactor = 19.0 / 81.0
+)
+
+type FSRSParameters struct {
+	W                [17]float64
+	DesiredRetention float64 // hedeflenen hatırlama olasılığı
+	MaximumInterval  int     // gün
+}
+
+// FSRS-4.5 varsayılan ağırlıkları
+func DefaultFSRSParameters() FSRSParameters {
+	return FSRSParameters{
+		W: [17]float64{
+			0.4872, 1.4003, 3.7145, 13.8206, 5.1618, 1.2298, 0.8975, 0.031,
+			1.6474, 0.1367, 1.0461, 2.1072, 0.0793, 0.3246, 1.587, 0.2272, 2.8755,
+		},
+		DesiredRetention: 0.9,
+		MaximumInterval:  36500,
+	}
+}
+
+// Free Spaced Repetition Scheduler (FSRS-4.5). Kısa süreli öğrenme adımları
+// yoktur; her tekrar gün bazında planlanır.
+type FSRS struct {
+	clock  Clock
+	params FSRSParameters
+}
+
+func NewFSRS(clock Clock, params FSRSParameters) *FSRS {
+	return &FSRS{clock: clock, params: params}
+}
+
+func (f *FSRS) Algorithm() string { return AlgorithmFSRS }
+
+func (f *FSRS) Review(card Card, grade Grade) Card {
+	now := f.clock.Now()
+	w := f.params.W
+
+	if card.Stability == 0 {
+		card.Stability = w[grade-1]
+		card.Difficulty = f.initDifficulty(grade)
+	} else {
+		r := Retrievability(float64(elapsedDays(card, now)), card.Stability)
+		card.Difficulty = f.nextDifficulty(card.Difficulty, grade)
+		if grade == Again {
+			card.Stability = math.Min(f.forgetStability(card, r), card.Stability)
+		} else {
+			card.Stability = f.recallStability(card, r, grade)
+		}
+	}
+
+	if grade == Again {
+		if card.Repetitions > 0 {
+			card.Lapses++
+		}
+		card.Repetitions = 0
+	} else {
+		card.Repetitions++
+	}
+
+	return schedule(card, now, f.interval(card.Stability))
+}
+
+// Kararlılığı S olan kartın t gün sonra hatırlanma olasılığı
+func Retrievability(elapsed, stability float64) float64 {
+	return math.Pow(1+fsrsFactor*elapsed/stability, fsrsDecay)
+}
+
+func (f *FSRS) initDifficulty(grade Grade) float64 {
+	return clamp(f.params.W[4]-f.params.W[5]*float64(grade-3), 1, 10)
+}
+
+func (f *FSRS) nextDifficulty(d float64, grade Grade) float64 {
+	w := f.params.W
+	next := d - w[6]*float64(grade-3)
+	// Başlangıç zorluğuna doğru ortalamaya dönüş
+	return clamp(w[7]*w[4]+(1-w[7])*next, 1, 10)
+}
+
+func (f *FSRS) recallStability(card Card, r float64, grade Grade) float64 {
+	w := f.params.W
+	modifier := 1.0
+	switch grade {
+	case Hard:
+		modifier = w[15]
+	case Easy:
+		modifier = w[16]
+	}
+	return card.Stability * (1 + math.Exp(w[8])*(11-card.Difficulty)*
+		math.Pow(card.Stability, -w[9])*(math.Exp(w[10]*(1-r))-1)*modifier)
+}
+
+func (f *FSRS) forgetStability(card Card, r float64) float64 {
+	w := f.params.W
+	return w[11] * math.Pow(card.Difficulty, -w[12]) *
+		(math.Pow(card.Stability+1, w[13]) - 1) * math.Exp(w[14]*(1-r))
+}
+
+// Hedeflenen hatırlama olasılığına düşene kadar geçecek gün sayısı
+func (f *FSRS) interval(stability float64) int {
+	days := stability / fsrsFactor * (math.Pow(f.params.DesiredRetention, 1/fsrsDecay) - 1)
+	interval := int(math.Round(days))
+	if interval < 1 {
+		interval = 1
+	}
+	if interval > f.params.MaximumInterval {
+		interval = f.params.MaximumInterval
+	}
+	return interval
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
@@ -0,0 +1,131 @@
+package srs
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFSRSInitialReview(t *testing.T) {
+	w := DefaultFSRSParameters().W
+	tests := []struct {
+		grade      Grade
+		stability  float64
+		difficulty float64
+		interval   int
+	}{
+		{Again, w[0], w[4] + 2*w[5], 1},
+		{Hard, w[1], w[4] + w[5], 1},
+		{Good, w[2], w[4], 4},
+		{Easy, w[3], w[4] - w[5], 14},
+	}
+	for _, tt := range tests {
+		clock := newFakeClock()
+		card := NewFSRS(clock, DefaultFSRSParameters()).Review(Card{}, tt.grade)
+		if card.Stability != tt.stability || math.Abs(card.Difficulty-tt.difficulty) > 1e-9 {
+			t.Errorf("grade %d: S = %v, D = %v; beklenen S = %v, D = %v",
+				tt.grade, card.Stability, card.Difficulty, tt.stability, tt.difficulty)
+		}
+		if card.Interval != tt.interval {
+			t.Errorf("grade %d: interval = %d, beklenen %d", tt.grade, card.Interval, tt.interval)
+		}
+		if !card.Due.Equal(clock.Now().AddDate(0, 0, tt.interval)) {
+			t.Errorf("grade %d: due = %v", tt.grade, card.Due)
+		}
+	}
+}
+
+func TestFSRSRetrievabilityAtStabilityMatchesTarget(t *testing.T) {
+	for _, s := range []float64{1, 3.7, 42} {
+		if r := Retrievability(s, s); math.Abs(r-0.9) > 1e-9 {
+			t.Fatalf("R(S, S) = %v, beklenen 0.9", r)
+		}
+	}
+}
+
+func TestFSRSIntervalsGrowWithSuccessfulReviews(t *testing.T) {
+	clock := newFakeClock()
+	f := NewFSRS(clock, DefaultFSRSParameters())
+
+	var card Card
+	prev := 0
+	for i := 0; i < 6; i++ {
+		card = reviewOnDue(f, clock, card, Good)
+		if card.Interval <= prev {
+			t.Fatalf("tekrar %d: interval %d, öncekinden (%d) büyük olmalı", i+1, card.Interval, prev)
+		}
+		prev = card.Interval
+	}
+	if card.Repetitions != 6 || card.Lapses != 0 {
+		t.Fatalf("repetitions = %d, lapses = %d", card.Repetitions, card.Lapses)
+	}
+}
+
+func TestFSRSGradeOrdering(t *testing.T) {
+	clock := newFakeClock()
+	f := NewFSRS(clock, DefaultFSRSParameters())
+	card := f.Review(Card{}, Good)
+	clock.now = card.Due
+
+	intervals := make(map[Grade]int)
+	for _, g := range []Grade{Again, Hard, Good, Easy} {
+		intervals[g] = f.Review(card, g).Interval
+	}
+	if !(intervals[Again] < intervals[Hard] && intervals[Hard] < intervals[Good] && intervals[Good] < intervals[Easy]) {
+		t.Fatalf("aralıklar değerlendirmeyle artmalı: %v", intervals)
+	}
+}
+
+func TestFSRSLapseReducesStability(t *testing.T) {
+	clock := newFakeClock()
+	f := NewFSRS(clock, DefaultFSRSParameters())
+
+	var card Card
+	for i := 0; i < 3; i++ {
+		card = reviewOnDue(f, clock, card, Good)
+	}
+	before := card
+	card = reviewOnDue(f, clock, card, Again)
+
+	if card.Stability >= before.Stability {
+		t.Fatalf("unutma kararlılığı düşürmeli: %v -> %v", before.Stability, card.Stability)
+	}
+	if card.Difficulty <= before.Difficulty {
+		t.Fatalf("unutma zorluğu artırmalı: %v -> %v", before.Difficulty, card.Difficulty)
+	}
+	if card.Lapses != 1 || card.Repetitions != 0 {
+		t.Fatalf("lapses = %d, repetitions = %d", card.Lapses, card.Repetitions)
+	}
+}
+
+func TestFSRSEarlyReviewGrowsLessThanOnTime(t *testing.T) {
+	clock := newFakeClock()
+	f := NewFSRS(clock, DefaultFSRSParameters())
+	card := f.Review(Card{}, Good)
+
+	clock.advanceDays(1)
+	early := f.Review(card, Good)
+	clock.now = card.Due
+	onTime := f.Review(card, Good)
+
+	if early.Stability >= onTime.Stability {
+		t.Fatalf("erken tekrar daha az kararlılık kazandırmalı: erken %v, zamanında %v",
+			early.Stability, onTime.Stability)
+	}
+}
+
+func TestFSRSIsDeterministic(t *testing.T) {
+	grades := []Grade{Good, Good, Again, Hard, Good, Easy}
+	run := func() Card {
+		clock := newFakeClock()
+		f := NewFSRS(clock, DefaultFSRSParameters())
+		var card Card
+		for _, g := range grades {
+			card = reviewOnDue(f, clock, card, g)
+		}
+		return card
+	}
+	a, b := run(), run()
+	if a.Stability != b.Stability || a.Difficulty != b.Difficulty || !a.Due.Equal(b.Due) {
+		t.Fatalf("aynı girdiler farklı sonuç verdi: %+v / %+v", a, b)
+	}
+}
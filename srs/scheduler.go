@@ -0,0 +1,102 @@
+// Package srs aralıklı tekrar (spaced repetition) zamanlayıcılarını içerir.
+// Her (kullanıcı, soru) çifti bir Card ile temsil edilir; cevap sonucu bir
+// Grade'e çevrilip zamanlayıcıya verilir ve bir sonraki tekrar tarihi hesaplanır.
+// SM-2 ve FSRS aynı Scheduler arayüzünü uygular, böylece karşılaştırılabilirler.
+package srs
+
+import (
+	"errors"
+	"time"
+)
+
+// Desteklenen algoritmalar
+const (
+	AlgorithmSM2  = "sm2"
+	AlgorithmFSRS = "fsrs"
+)
+
+var ErrUnknownAlgorithm = errors.New("bilinmeyen tekrar algoritması")
+
+// Cevabın değerlendirmesi
+type Grade int
+
+const (
+	Again Grade = iota + 1 // yanlış / hatırlanamadı
+	Hard                   // doğru ama zorlanarak
+	Good                   // doğru
+	Easy                   // doğru ve çok kolay
+)
+
+func (g Grade) Valid() bool {
+	return g >= Again && g <= Easy
+}
+
+// Bir (kullanıcı, soru) çiftinin tekrar durumu. EaseFactor yalnızca SM-2,
+// Stability ve Difficulty yalnızca FSRS tarafından kullanılır.
+type Card struct {
+	Repetitions int        `json:"repetitions"` // art arda başarılı tekrar sayısı
+	Lapses      int        `json:"lapses"`      // öğrenildikten sonra unutulma sayısı
+	Interval    int        `json:"interval"`    // gün
+	EaseFactor  float64    `json:"ease_factor"`
+	Stability   float64    `json:"stability"`
+	Difficulty  float64    `json:"difficulty"`
+	LastReview  *time.Time `json:"last_review"`
+	Due         time.Time  `json:"due"`
+}
+
+// Zaman kaynağı; testlerde sabit veya elle ilerletilen saat verilir
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// Tekrar zamanlayıcısı
+type Scheduler interface {
+	Algorithm() string
+	// Kartı verilen değerlendirmeye göre günceller ve yeni durumu döner
+	Review(card Card, grade Grade) Card
+}
+
+// Algoritma adına göre zamanlayıcı oluşturur
+func New(algorithm string, clock Clock) (Scheduler, error) {
+	switch algorithm {
+	case AlgorithmSM2:
+		return NewSM2(clock), nil
+	case AlgorithmFSRS:
+		return NewFSRS(clock, DefaultFSRSParameters()), nil
+	}
+	return nil, ErrUnknownAlgorithm
+}
+
+// Kartın tekrar zamanı gelmiş mi; hiç değerlendirilmemiş kartlar her zaman hazırdır
+func (c Card) IsDue(now time.Time) bool {
+	return c.LastReview == nil || !now.Before(c.Due)
+}
+
+// Değerlendirmeyi yalnızca tekrar zamanı gelmiş karta uygular. Zamanı gelmeden
+// verilen cevaplar (ör. serbest çalışmada aynı soruyu tekrar çözmek) kartı
+// ilerletmez; ikinci dönüş değeri kartın güncellenip güncellenmediğini bildirir.
+func ReviewIfDue(s Scheduler, clock Clock, card Card, grade Grade) (Card, bool) {
+	if !card.IsDue(clock.Now()) {
+		return card, false
+	}
+	return s.Review(card, grade), true
+}
+
+// Son tekrardan bu yana geçen tam gün sayısı
+func elapsedDays(card Card, now time.Time) int {
+	if card.LastReview == nil || now.Before(*card.LastReview) {
+		return 0
+	}
+	return int(now.Sub(*card.LastReview) / (24 * time.Hour))
+}
+
+func schedule(card Card, now time.Time, interval int) Card {
+	card.Interval = interval
+	card.LastReview = &now
+	card.Due = now.AddDate(0, 0, interval)
+	return card
+}
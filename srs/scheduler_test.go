@@ -0,0 +1,76 @@
+package srs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewScheduler(t *testing.T) {
+	clock := newFakeClock()
+	for _, algorithm := range []string{AlgorithmSM2, AlgorithmFSRS} {
+		s, err := New(algorithm, clock)
+		if err != nil {
+			t.Fatalf("%s: %v", algorithm, err)
+		}
+		if s.Algorithm() != algorithm {
+			t.Fatalf("Algorithm() = %s, beklenen %s", s.Algorithm(), algorithm)
+		}
+		card := s.Review(Card{}, Good)
+		if card.LastReview == nil || !card.LastReview.Equal(clock.Now()) {
+			t.Fatalf("%s: son tekrar saatten alınmalı: %v", algorithm, card.LastReview)
+		}
+	}
+
+	if _, err := New("leitner", clock); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Fatalf("bilinmeyen algoritma hatası beklendi: %v", err)
+	}
+}
+
+func TestElapsedDays(t *testing.T) {
+	clock := newFakeClock()
+	last := clock.Now()
+	card := Card{LastReview: &last}
+
+	clock.advanceDays(3)
+	if d := elapsedDays(card, clock.Now()); d != 3 {
+		t.Fatalf("elapsedDays = %d, beklenen 3", d)
+	}
+	if d := elapsedDays(card, last.AddDate(0, 0, -1)); d != 0 {
+		t.Fatalf("geçmişteki saat için elapsedDays = %d, beklenen 0", d)
+	}
+	if d := elapsedDays(Card{}, clock.Now()); d != 0 {
+		t.Fatalf("hiç tekrar edilmemiş kart için elapsedDays = %d", d)
+	}
+}
+
+func TestReviewIfDueIgnoresEarlyAnswers(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmSM2, AlgorithmFSRS} {
+		clock := newFakeClock()
+		s, _ := New(algorithm, clock)
+
+		card, applied := ReviewIfDue(s, clock, Card{}, Again)
+		if !applied {
+			t.Fatalf("%s: yeni kart her zaman değerlendirilmeli", algorithm)
+		}
+		due := card.Due
+
+		// Aynı gün tekrar tekrar çözülen soru kartı ilerletmez
+		clock.now = due.Add(-time.Minute)
+		for i := 0; i < 5; i++ {
+			next, applied := ReviewIfDue(s, clock, card, Good)
+			if applied || next != card {
+				t.Fatalf("%s: zamanı gelmemiş kart değişmemeli (deneme %d): %+v", algorithm, i+1, next)
+			}
+		}
+
+		clock.now = due
+		next, applied := ReviewIfDue(s, clock, card, Good)
+		if !applied {
+			t.Fatalf("%s: zamanı gelen kart değerlendirilmeli", algorithm)
+		}
+		if !next.Due.After(due) || next.LastReview == nil || !next.LastReview.Equal(due) {
+			t.Fatalf("%s: kart ilerlemedi: %+v", algorithm, next)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package srs
+
+import "math"
+
+const (
+	DefaultEaseFactor = 2.5
+	minEaseFactor     = 1.3
+)
+
+// SuperMemo-2 algoritması. Değerlendirmeler SM-2'nin 0-5 kalite ölçeğine
+// Again=2, Hard=3, Good=4, Easy=5 olarak karşılık gelir.
+type SM2 struct {
+	clock Clock
+}
+
+func NewSM2(clock Clock) *SM2 {
+	return &SM2{clock: clock}
+}
+
+func (s *SM2) Algorithm() string { return AlgorithmSM2 }
+
+func (s *SM2) Review(card Card, grade Grade) Card {
+	if card.EaseFactor == 0 {
+		card.EaseFactor = DefaultEaseFactor
+	}
+	quality := float64(grade) + 1
+
+	var interval int
+	if quality < 3 {
+		if card.Repetitions > 0 {
+			card.Lapses++
+		}
+		card.Repetitions = 0
+		interval = 1
+	} else {
+		switch card.Repetitions {
+		case 0:
+			interval = 1
+		case 1:
+			interval = 6
+		default:
+			interval = int(math.Round(float64(card.Interval) * card.EaseFactor))
+		}
+		card.Repetitions++
+	}
+
+	card.EaseFactor += 0.1 - (5-quality)*(0.08+(5-quality)*0.02)
+	if card.EaseFactor < minEaseFactor {
+		card.EaseFactor = minEaseFactor
+	}
+
+	return schedule(card, s.clock.Now(), interval)
+}
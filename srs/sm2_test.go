@@ -0,0 +1,78 @@
+package srs
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSM2IntervalsForConsecutiveGoodAnswers(t *testing.T) {
+	clock := newFakeClock()
+	s := NewSM2(clock)
+	start := clock.Now()
+
+	var card Card
+	wantIntervals := []int{1, 6, 15, 38, 95}
+	elapsed := 0
+	for i, want := range wantIntervals {
+		card = reviewOnDue(s, clock, card, Good)
+		if card.Interval != want {
+			t.Fatalf("tekrar %d: interval = %d, beklenen %d", i+1, card.Interval, want)
+		}
+		elapsed += want
+		if !card.Due.Equal(start.AddDate(0, 0, elapsed)) {
+			t.Fatalf("tekrar %d: due = %v, beklenen %v", i+1, card.Due, start.AddDate(0, 0, elapsed))
+		}
+	}
+	if card.EaseFactor != DefaultEaseFactor {
+		t.Fatalf("Good cevaplar ease factor'ü değiştirmemeli: %v", card.EaseFactor)
+	}
+	if card.Repetitions != len(wantIntervals) || card.Lapses != 0 {
+		t.Fatalf("repetitions = %d, lapses = %d", card.Repetitions, card.Lapses)
+	}
+}
+
+func TestSM2AgainResetsRepetitions(t *testing.T) {
+	clock := newFakeClock()
+	s := NewSM2(clock)
+
+	var card Card
+	card = reviewOnDue(s, clock, card, Good)
+	card = reviewOnDue(s, clock, card, Good)
+	card = reviewOnDue(s, clock, card, Again)
+
+	if card.Repetitions != 0 || card.Interval != 1 || card.Lapses != 1 {
+		t.Fatalf("repetitions = %d, interval = %d, lapses = %d", card.Repetitions, card.Interval, card.Lapses)
+	}
+	if math.Abs(card.EaseFactor-2.18) > 1e-9 {
+		t.Fatalf("ease factor = %v, beklenen 2.18", card.EaseFactor)
+	}
+	if !card.Due.Equal(clock.Now().AddDate(0, 0, 1)) {
+		t.Fatalf("yanlış cevaptan sonra kart ertesi gün tekrar edilmeli: %v", card.Due)
+	}
+}
+
+func TestSM2FirstMissIsNotALapse(t *testing.T) {
+	s := NewSM2(newFakeClock())
+	card := s.Review(Card{}, Again)
+	if card.Lapses != 0 {
+		t.Fatalf("hiç öğrenilmemiş kartın yanlışı unutma sayılmamalı: lapses = %d", card.Lapses)
+	}
+}
+
+func TestSM2EaseFactorBounds(t *testing.T) {
+	clock := newFakeClock()
+	s := NewSM2(clock)
+
+	var card Card
+	for i := 0; i < 10; i++ {
+		card = reviewOnDue(s, clock, card, Again)
+	}
+	if card.EaseFactor != minEaseFactor {
+		t.Fatalf("ease factor alt sınırı = %v, beklenen %v", card.EaseFactor, minEaseFactor)
+	}
+
+	card = reviewOnDue(s, clock, Card{}, Easy)
+	if math.Abs(card.EaseFactor-2.6) > 1e-9 {
+		t.Fatalf("Easy ease factor'ü artırmalı: %v", card.EaseFactor)
+	}
+}
@@ -1,9 +1,14 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"osymapp/models"
+	"strings"
+	"time"
 )
 
 func SendResponse(w http.ResponseWriter, statusCode int, success bool, message string, data interface{}, err string) {
@@ -27,3 +32,38 @@ func SendSuccess(w http.ResponseWriter, message string, data interface{}) {
 func SendError(w http.ResponseWriter, statusCode int, message string) {
 	SendResponse(w, statusCode, false, "", nil, message)
 }
+
+// Önbelleğe alınabilir yanıt gönderir. Gövdenin özeti ETag olarak eklenir;
+// istemcinin If-None-Match başlığı eşleşirse gövde gönderilmeden 304 döner.
+func SendCacheable(w http.ResponseWriter, r *http.Request, maxAge time.Duration, message string, data interface{}) {
+	body, err := json.Marshal(models.Response{Success: true, Message: message, Data: data})
+	if err != nil {
+		SendError(w, http.StatusInternalServerError, "Error encoding response")
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}